@@ -0,0 +1,106 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Command is a subcommand of the argo-events CLI
+type Command struct {
+	// Name of the subcommand
+	Name string
+	// Short describes the subcommand in the usage
+	Short string
+	// Run runs the subcommand with its arguments and writes its output to out
+	Run func(args []string, out io.Writer) error
+}
+
+// commands are the subcommands of the CLI by name
+var commands = map[string]*Command{}
+
+// register adds the subcommand to the CLI
+func register(command *Command) {
+	commands[command.Name] = command
+}
+
+// Run runs the subcommand named by the first argument
+func Run(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(out)
+		return nil
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		usage(out)
+		return fmt.Errorf("unknown command %s", args[0])
+	}
+	return command.Run(args[1:], out)
+}
+
+// usage lists the subcommands
+func usage(out io.Writer) {
+	fmt.Fprintln(out, "Usage: argo-events <command> [flags]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := newTabWriter(out)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\t%s\n", name, commands[name].Short)
+	}
+	_ = w.Flush()
+}
+
+// newFlagSet returns the flag set of a subcommand that writes its errors and usage to out
+func newFlagSet(name string, out io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(out)
+	return flags
+}
+
+// parseFlags parses the arguments of a subcommand. Asking for the usage is not an error.
+func parseFlags(flags *flag.FlagSet, args []string) (bool, error) {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// newTabWriter returns a writer that aligns the tab separated columns of the output
+func newTabWriter(out io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+}
+
+// formatTime formats the time of a status, "-" if the time is not set
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
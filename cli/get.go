@@ -0,0 +1,195 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	gatewayv1alpha1 "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	gatewayclientset "github.com/argoproj/argo-events/pkg/client/gateway/clientset/versioned"
+	sensorclientset "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	register(&Command{
+		Name:  "get",
+		Short: "Show the status of the event sources of a gateway, or of the dependencies and triggers of a sensor",
+		Run:   runGet,
+	})
+}
+
+func runGet(args []string, out io.Writer) error {
+	flags := newFlagSet("get", out)
+	flags.Usage = func() {
+		fmt.Fprintln(out, "Usage: argo-events get gateway|sensor NAME [flags]")
+		flags.PrintDefaults()
+	}
+	kube := addKubeFlags(flags)
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("the kind and the name of the resource must be specified")
+	}
+	namespace, err := kube.getNamespace()
+	if err != nil {
+		return err
+	}
+
+	switch flags.Arg(0) {
+	case "gateway", "gateways", "gw":
+		client, err := kube.gatewayClient()
+		if err != nil {
+			return err
+		}
+		return getGateway(client, namespace, flags.Arg(1), out)
+	case "sensor", "sensors", "sn":
+		client, err := kube.sensorClient()
+		if err != nil {
+			return err
+		}
+		return getSensor(client, namespace, flags.Arg(1), out)
+	default:
+		return errors.Errorf("unknown resource %s, either a gateway or a sensor can be shown", flags.Arg(0))
+	}
+}
+
+// getGateway prints the status of the gateway and of each of its event sources
+func getGateway(client gatewayclientset.Interface, namespace, name string, out io.Writer) error {
+	gateway, err := client.ArgoprojV1alpha1().Gateways(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the gateway %s", name)
+	}
+
+	w := newTabWriter(out)
+	fmt.Fprintf(w, "Name:\t%s\n", gateway.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", gateway.Namespace)
+	fmt.Fprintf(w, "Type:\t%s\n", gateway.Spec.Type)
+	if gateway.Spec.EventSourceRef != nil {
+		fmt.Fprintf(w, "Event source:\t%s\n", gateway.Spec.EventSourceRef.Name)
+	}
+	fmt.Fprintf(w, "Phase:\t%s\n", phaseOrNew(string(gateway.Status.Phase)))
+	fmt.Fprintf(w, "Started:\t%s\n", formatTime(gateway.Status.StartedAt.Time))
+	fmt.Fprintf(w, "Message:\t%s\n", gateway.Status.Message)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var nodes []gatewayv1alpha1.NodeStatus
+	for _, node := range gateway.Status.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].DisplayName < nodes[j].DisplayName
+	})
+	fmt.Fprintln(out)
+	w = newTabWriter(out)
+	fmt.Fprintln(w, "EVENT SOURCE\tPHASE\tSTARTED\tUPDATED\tMESSAGE")
+	for _, node := range nodes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", node.DisplayName, phaseOrNew(string(node.Phase)), formatTime(node.StartedAt.Time), formatTime(node.UpdateTime.Time), node.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(gateway.Status.PendingEvents) > 0 {
+		var watchers []string
+		for watcher := range gateway.Status.PendingEvents {
+			watchers = append(watchers, watcher)
+		}
+		sort.Strings(watchers)
+		fmt.Fprintln(out)
+		w = newTabWriter(out)
+		fmt.Fprintln(w, "SENSOR\tPENDING EVENTS")
+		for _, watcher := range watchers {
+			fmt.Fprintf(w, "%s\t%d\n", watcher, gateway.Status.PendingEvents[watcher])
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+// getSensor prints the status of the sensor, the last event of each dependency and the status of the groups and triggers
+func getSensor(client sensorclientset.Interface, namespace, name string, out io.Writer) error {
+	sensor, err := client.ArgoprojV1alpha1().Sensors(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the sensor %s", name)
+	}
+
+	w := newTabWriter(out)
+	fmt.Fprintf(w, "Name:\t%s\n", sensor.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", sensor.Namespace)
+	fmt.Fprintf(w, "Phase:\t%s\n", phaseOrNew(string(sensor.Status.Phase)))
+	fmt.Fprintf(w, "Message:\t%s\n", sensor.Status.Message)
+	fmt.Fprintf(w, "Trigger cycles:\t%d\n", sensor.Status.TriggerCycleCount)
+	if sensor.Status.TriggerCycleStatus != "" {
+		fmt.Fprintf(w, "Last cycle:\t%s at %s\n", sensor.Status.TriggerCycleStatus, formatTime(sensor.Status.LastCycleTime.Time))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	w = newTabWriter(out)
+	fmt.Fprintln(w, "DEPENDENCY\tPHASE\tLAST EVENT\tEVENT TIME\tRECEIVED\tMESSAGE")
+	for _, dependency := range sensor.Spec.Dependencies {
+		node, ok := sensor.Status.Nodes[sensor.NodeID(dependency.Name)]
+		if !ok {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\t\n", dependency.Name)
+			continue
+		}
+		eventID, eventTime := "-", "-"
+		if node.Event != nil {
+			eventID = node.Event.Context.ID
+			eventTime = formatTime(node.Event.Context.Time.Time)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", dependency.Name, phaseOrNew(string(node.Phase)), eventID, eventTime, formatTime(node.CompletedAt.Time), node.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(sensor.Spec.DependencyGroups) > 0 {
+		fmt.Fprintln(out)
+		w = newTabWriter(out)
+		fmt.Fprintln(w, "GROUP\tPHASE\tCOMPLETED\tMESSAGE")
+		for _, group := range sensor.Spec.DependencyGroups {
+			node := sensor.Status.Nodes[sensor.NodeID(group.Name)]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", group.Name, phaseOrNew(string(node.Phase)), formatTime(node.CompletedAt.Time), node.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(out)
+	w = newTabWriter(out)
+	fmt.Fprintln(w, "TRIGGER\tPHASE\tCOMPLETED\tMESSAGE")
+	for _, trigger := range sensor.Spec.Triggers {
+		if trigger.Template == nil {
+			continue
+		}
+		node := sensor.Status.Nodes[sensor.NodeID(trigger.Template.Name)]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", trigger.Template.Name, phaseOrNew(string(node.Phase)), formatTime(node.CompletedAt.Time), node.Message)
+	}
+	return w.Flush()
+}
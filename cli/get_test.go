@@ -0,0 +1,150 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	gatewayv1alpha1 "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	fakegateway "github.com/argoproj/argo-events/pkg/client/gateway/clientset/versioned/fake"
+	fakesensor "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newGateway() *gatewayv1alpha1.Gateway {
+	return &gatewayv1alpha1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "webhook-gateway",
+			Namespace: "argo-events",
+		},
+		Spec: gatewayv1alpha1.GatewaySpec{
+			Type: apicommon.WebhookEvent,
+		},
+		Status: gatewayv1alpha1.GatewayStatus{
+			Phase: gatewayv1alpha1.NodePhaseRunning,
+			Nodes: map[string]gatewayv1alpha1.NodeStatus{
+				"3456789": {
+					ID:          "3456789",
+					Name:        "example",
+					DisplayName: "example",
+					Phase:       gatewayv1alpha1.NodePhaseRunning,
+					Message:     "event source is running",
+				},
+			},
+			PendingEvents: map[string]int32{
+				"argo-events/webhook-sensor": 3,
+			},
+		},
+	}
+}
+
+// newGatewayClient returns a fake client that holds the gateway.
+// The gateway is created through the client, the object tracker would store it under a misspelled resource.
+func newGatewayClient(t *testing.T) *fakegateway.Clientset {
+	client := fakegateway.NewSimpleClientset()
+	gateway := newGateway()
+	_, err := client.ArgoprojV1alpha1().Gateways(gateway.Namespace).Create(gateway)
+	assert.Nil(t, err)
+	return client
+}
+
+func newSensor() *v1alpha1.Sensor {
+	sensor := &v1alpha1.Sensor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "webhook-sensor",
+			Namespace: "argo-events",
+		},
+		Spec: v1alpha1.SensorSpec{
+			Dependencies: []v1alpha1.EventDependency{
+				{
+					Name: "webhook-gateway:example",
+				},
+				{
+					Name: "webhook-gateway:example-2",
+				},
+			},
+			Triggers: []v1alpha1.Trigger{
+				{
+					Template: &v1alpha1.TriggerTemplate{
+						Name: "webhook-workflow-trigger",
+					},
+				},
+			},
+		},
+		Status: v1alpha1.SensorStatus{
+			Phase:              v1alpha1.NodePhaseActive,
+			TriggerCycleCount:  2,
+			TriggerCycleStatus: v1alpha1.TriggerCycleSuccess,
+			LastCycleTime:      metav1.Now(),
+		},
+	}
+	id := sensor.NodeID("webhook-gateway:example")
+	sensor.Status.Nodes = map[string]v1alpha1.NodeStatus{
+		id: {
+			ID:    id,
+			Name:  "webhook-gateway:example",
+			Type:  v1alpha1.NodeTypeEventDependency,
+			Phase: v1alpha1.NodePhaseActive,
+			Event: &apicommon.Event{
+				Context: apicommon.EventContext{
+					ID:   "event-1",
+					Time: metav1.MicroTime{Time: time.Date(2019, 11, 5, 10, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+	}
+	return sensor
+}
+
+func TestList(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := listGateways(newGatewayClient(t), "argo-events", out)
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "webhook-gateway")
+	assert.Contains(t, out.String(), string(gatewayv1alpha1.NodePhaseRunning))
+
+	out.Reset()
+	err = listSensors(fakesensor.NewSimpleClientset(newSensor()), "argo-events", out)
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "webhook-sensor")
+	assert.Contains(t, out.String(), string(v1alpha1.TriggerCycleSuccess))
+}
+
+func TestGet(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := getGateway(newGatewayClient(t), "argo-events", "webhook-gateway", out)
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "event source is running")
+	assert.Contains(t, out.String(), "argo-events/webhook-sensor")
+
+	out.Reset()
+	err = getSensor(fakesensor.NewSimpleClientset(newSensor()), "argo-events", "webhook-sensor", out)
+	assert.Nil(t, err)
+	// the last event of each dependency is shown by the name of the dependency rather than the ID of its node
+	assert.Contains(t, out.String(), "event-1")
+	assert.Contains(t, out.String(), "2019-11-05T10:00:00Z")
+	assert.Contains(t, out.String(), "webhook-gateway:example-2")
+	assert.Contains(t, out.String(), "webhook-workflow-trigger")
+
+	err = getSensor(fakesensor.NewSimpleClientset(), "argo-events", "webhook-sensor", out)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"flag"
+
+	gatewayclientset "github.com/argoproj/argo-events/pkg/client/gateway/clientset/versioned"
+	sensorclientset "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubeFlags are the flags of the commands that talk to the cluster
+type kubeFlags struct {
+	kubeConfig string
+	namespace  string
+}
+
+// addKubeFlags adds the kubeconfig and namespace flags to the flag set
+func addKubeFlags(flags *flag.FlagSet) *kubeFlags {
+	kube := &kubeFlags{}
+	flags.StringVar(&kube.kubeConfig, "kubeconfig", "", "path to the kubeconfig. Defaults to $KUBECONFIG or ~/.kube/config")
+	flags.StringVar(&kube.namespace, "n", "", "namespace. Defaults to the namespace of the current context")
+	return kube
+}
+
+// clientConfig loads the kubeconfig the way kubectl does
+func (kube *kubeFlags) clientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kube.kubeConfig
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.Context.Namespace = kube.namespace
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// restConfig returns the config of the clients
+func (kube *kubeFlags) restConfig() (*rest.Config, error) {
+	restConfig, err := kube.clientConfig().ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the kubeconfig")
+	}
+	return restConfig, nil
+}
+
+// getNamespace returns the namespace of the flag or of the current context, the default namespace without kubeconfig
+func (kube *kubeFlags) getNamespace() (string, error) {
+	namespace, _, err := kube.clientConfig().Namespace()
+	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			return metav1.NamespaceDefault, nil
+		}
+		return "", errors.Wrap(err, "failed to resolve the namespace")
+	}
+	return namespace, nil
+}
+
+// kubeClient returns the kubernetes client
+func (kube *kubeFlags) kubeClient() (kubernetes.Interface, error) {
+	restConfig, err := kube.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// gatewayClient returns the client of the gateways
+func (kube *kubeFlags) gatewayClient() (gatewayclientset.Interface, error) {
+	restConfig, err := kube.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	return gatewayclientset.NewForConfig(restConfig)
+}
+
+// sensorClient returns the client of the sensors
+func (kube *kubeFlags) sensorClient() (sensorclientset.Interface, error) {
+	restConfig, err := kube.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	return sensorclientset.NewForConfig(restConfig)
+}
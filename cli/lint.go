@@ -0,0 +1,146 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	eventsourcecontroller "github.com/argoproj/argo-events/controllers/eventsource"
+	gatewaycontroller "github.com/argoproj/argo-events/controllers/gateway"
+	sensorcontroller "github.com/argoproj/argo-events/controllers/sensor"
+	eventsourcev1alpha1 "github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	gatewayv1alpha1 "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	sensorv1alpha1 "github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// documentSeparator separates the documents of a YAML file
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+func init() {
+	register(&Command{
+		Name:  "lint",
+		Short: "Validate the gateway, sensor and event source manifests of files or directories",
+		Run:   runLint,
+	})
+}
+
+func runLint(args []string, out io.Writer) error {
+	flags := newFlagSet("lint", out)
+	flags.Usage = func() {
+		fmt.Fprintln(out, "Usage: argo-events lint FILE|DIRECTORY...")
+		flags.PrintDefaults()
+	}
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("at least one file or directory must be specified")
+	}
+
+	var files []string
+	for _, path := range flags.Args() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if ext := filepath.Ext(entry.Name()); !entry.IsDir() && (ext == ".yaml" || ext == ".yml" || ext == ".json") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	invalid := 0
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		for _, document := range documentSeparator.Split(string(content), -1) {
+			if strings.TrimSpace(document) == "" {
+				continue
+			}
+			kind, name, err := lintDocument([]byte(document))
+			if kind == "" {
+				kind = "unknown"
+			}
+			if err != nil {
+				invalid++
+				fmt.Fprintf(out, "%s: %s/%s: %v\n", file, kind, name, err)
+				continue
+			}
+			fmt.Fprintf(out, "%s: %s/%s: ok\n", file, kind, name)
+		}
+	}
+	if invalid > 0 {
+		return errors.Errorf("%d invalid resources", invalid)
+	}
+	return nil
+}
+
+// lintDocument validates a manifest with the validation of the controller of its kind
+func lintDocument(document []byte) (string, string, error) {
+	var meta struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if err := yaml.Unmarshal(document, &meta); err != nil {
+		return "", "", errors.Wrap(err, "failed to decode the manifest")
+	}
+
+	switch meta.Kind {
+	case "Gateway":
+		gateway := &gatewayv1alpha1.Gateway{}
+		if err := yaml.Unmarshal(document, gateway); err != nil {
+			return meta.Kind, meta.Metadata.Name, errors.Wrap(err, "failed to decode the gateway")
+		}
+		return meta.Kind, meta.Metadata.Name, gatewaycontroller.Validate(gateway)
+	case "Sensor":
+		sensor := &sensorv1alpha1.Sensor{}
+		if err := yaml.Unmarshal(document, sensor); err != nil {
+			return meta.Kind, meta.Metadata.Name, errors.Wrap(err, "failed to decode the sensor")
+		}
+		return meta.Kind, meta.Metadata.Name, sensorcontroller.ValidateSensor(sensor)
+	case "EventSource":
+		eventSource := &eventsourcev1alpha1.EventSource{}
+		if err := yaml.Unmarshal(document, eventSource); err != nil {
+			return meta.Kind, meta.Metadata.Name, errors.Wrap(err, "failed to decode the event source")
+		}
+		return meta.Kind, meta.Metadata.Name, eventsourcecontroller.ValidateEventSource(eventSource)
+	default:
+		return meta.Kind, meta.Metadata.Name, errors.New("unsupported kind, only gateways, sensors and event sources are linted")
+	}
+}
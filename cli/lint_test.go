@@ -0,0 +1,94 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunLint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lint")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	invalid := strings.Replace(simulationSensor, "name: webhook-sensor", "name: invalid-sensor", 1)
+	invalid = strings.Replace(invalid, `- name: "webhook-gateway:example"`, "- name: example", 1)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "sensor.yaml"), []byte(simulationSensor), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "sensors.yaml"), []byte(simulationSensor+"\n---\n"+invalid), 0644))
+
+	out := &bytes.Buffer{}
+	err = Run([]string{"lint", filepath.Join(dir, "sensor.yaml")}, out)
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "Sensor/webhook-sensor: ok")
+
+	out.Reset()
+	err = Run([]string{"lint", dir}, out)
+	assert.NotNil(t, err)
+	assert.Contains(t, out.String(), "Sensor/invalid-sensor: event dependency must have format")
+	assert.Equal(t, 3, strings.Count(out.String(), "\n"))
+
+	// the entries of an event source are validated by the gateway server of its type
+	eventSource := `
+apiVersion: argoproj.io/v1alpha1
+kind: EventSource
+metadata:
+  name: mqtt-event-source
+spec:
+  type: "mqtt"
+  mqtt:
+    example:
+      url: "tcp://mqtt.argo-events:1883"
+`
+	eventSourceDir, err := ioutil.TempDir("", "lint")
+	assert.Nil(t, err)
+	defer os.RemoveAll(eventSourceDir)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(eventSourceDir, "event-source.yaml"), []byte(eventSource), 0644))
+	out.Reset()
+	err = Run([]string{"lint", eventSourceDir}, out)
+	assert.NotNil(t, err)
+	assert.Contains(t, out.String(), "topic must be specified")
+}
+
+func TestSendEvent(t *testing.T) {
+	received := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		received <- body
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	event, err := newTestEvent("webhook-gateway", "example", "test", []byte(`{"name": "fake"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "webhook-gateway", event.Source())
+	assert.Equal(t, "example", event.Subject())
+
+	assert.Nil(t, sendEventOverHTTP(server.URL, event))
+	assert.Equal(t, `{"name": "fake"}`, string(<-received))
+
+	_, err = newTestEvent("webhook-gateway", "example", "test", []byte("not json"))
+	assert.NotNil(t, err)
+}
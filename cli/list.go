@@ -0,0 +1,117 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	gatewayclientset "github.com/argoproj/argo-events/pkg/client/gateway/clientset/versioned"
+	sensorclientset "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	register(&Command{
+		Name:  "list",
+		Short: "List the gateways or the sensors with their phase",
+		Run:   runList,
+	})
+}
+
+func runList(args []string, out io.Writer) error {
+	flags := newFlagSet("list", out)
+	flags.Usage = func() {
+		fmt.Fprintln(out, "Usage: argo-events list gateways|sensors [flags]")
+		flags.PrintDefaults()
+	}
+	kube := addKubeFlags(flags)
+	allNamespaces := flags.Bool("all-namespaces", false, "list the resources of all the namespaces")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("either gateways or sensors must be listed")
+	}
+
+	namespace := metav1.NamespaceAll
+	if !*allNamespaces {
+		var err error
+		if namespace, err = kube.getNamespace(); err != nil {
+			return err
+		}
+	}
+
+	switch flags.Arg(0) {
+	case "gateways", "gateway", "gw":
+		client, err := kube.gatewayClient()
+		if err != nil {
+			return err
+		}
+		return listGateways(client, namespace, out)
+	case "sensors", "sensor", "sn":
+		client, err := kube.sensorClient()
+		if err != nil {
+			return err
+		}
+		return listSensors(client, namespace, out)
+	default:
+		return errors.Errorf("unknown resource %s, either gateways or sensors can be listed", flags.Arg(0))
+	}
+}
+
+// listGateways prints the gateways of the namespace with their phase
+func listGateways(client gatewayclientset.Interface, namespace string, out io.Writer) error {
+	gateways, err := client.ArgoprojV1alpha1().Gateways(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the gateways")
+	}
+	w := newTabWriter(out)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tTYPE\tPHASE\tEVENT SOURCES\tMESSAGE")
+	for _, gateway := range gateways.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", gateway.Namespace, gateway.Name, gateway.Spec.Type, phaseOrNew(string(gateway.Status.Phase)), len(gateway.Status.Nodes), gateway.Status.Message)
+	}
+	return w.Flush()
+}
+
+// listSensors prints the sensors of the namespace with their phase and the outcome of their last trigger cycle
+func listSensors(client sensorclientset.Interface, namespace string, out io.Writer) error {
+	sensors, err := client.ArgoprojV1alpha1().Sensors(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the sensors")
+	}
+	w := newTabWriter(out)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tPHASE\tTRIGGER CYCLES\tLAST CYCLE\tLAST CYCLE TIME\tMESSAGE")
+	for _, sensor := range sensors.Items {
+		lastCycle := string(sensor.Status.TriggerCycleStatus)
+		if lastCycle == "" {
+			lastCycle = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", sensor.Namespace, sensor.Name, phaseOrNew(string(sensor.Status.Phase)), sensor.Status.TriggerCycleCount, lastCycle, formatTime(sensor.Status.LastCycleTime.Time), sensor.Status.Message)
+	}
+	return w.Flush()
+}
+
+// phaseOrNew returns the phase, "New" for the resources not yet processed by their controller
+func phaseOrNew(phase string) string {
+	if phase == "" {
+		return "New"
+	}
+	return phase
+}
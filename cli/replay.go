@@ -0,0 +1,144 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/replay"
+	"github.com/pkg/errors"
+)
+
+// EnvVarReplayToken is the environment variable the bearer token of the replay endpoint is read from
+const EnvVarReplayToken = "ARGO_EVENTS_REPLAY_TOKEN"
+
+func init() {
+	register(&Command{
+		Name:  "replay",
+		Short: "Re-inject a dead-letter record or a logged event into a dependency of a sensor",
+		Run:   runReplay,
+	})
+}
+
+func runReplay(args []string, out io.Writer) error {
+	flags := newFlagSet("replay", out)
+	url := flags.String("url", "", "URL of the replay endpoint of the sensor, e.g. http://my-sensor.argo-events.svc:12100")
+	token := flags.String("token", os.Getenv(EnvVarReplayToken), "bearer token of the replay endpoint. Defaults to $"+EnvVarReplayToken)
+	dependency := flags.String("dependency", "", "dependency to replay the event to. Defaults to the dependency of the record")
+	recordID := flags.String("record", "", "ID of the dead-letter record to replay")
+	file := flags.String("file", "", "file of JSON lines with dead-letter records or events to replay")
+	dryRun := flags.Bool("dry-run", false, "only apply the filters of the dependency on the event")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if *url == "" {
+		return errors.New("url of the replay endpoint must be specified")
+	}
+	if *token == "" {
+		return errors.Errorf("token must be specified with --token or $%s", EnvVarReplayToken)
+	}
+
+	var requests []*replay.Request
+	switch {
+	case *file != "":
+		records, err := readEventLog(*file)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if *recordID != "" && record.ID != *recordID {
+				continue
+			}
+			name := *dependency
+			if name == "" {
+				name = record.Dependency
+			}
+			requests = append(requests, &replay.Request{
+				Dependency: name,
+				Event:      record.Event,
+				Attempts:   record.Attempts,
+				DryRun:     *dryRun,
+			})
+		}
+		if len(requests) == 0 {
+			return errors.Errorf("no event to replay in %s", *file)
+		}
+	case *recordID != "":
+		// the sensor looks the record up in its dead-letter sink
+		requests = append(requests, &replay.Request{
+			Dependency: *dependency,
+			RecordID:   *recordID,
+			DryRun:     *dryRun,
+		})
+	default:
+		return errors.New("either a record id or a file must be specified")
+	}
+
+	client := replay.NewClient(*url, *token)
+	for _, request := range requests {
+		response, err := client.Submit(request)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s -> %s: %s\n", response.EventID, response.Dependency, response.Message)
+	}
+	return nil
+}
+
+// readEventLog reads a file of JSON lines. A line is either a dead-letter record or a bare event.
+func readEventLog(path string) ([]*deadletter.Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", path)
+	}
+	defer file.Close()
+
+	var records []*deadletter.Record
+	scanner := bufio.NewScanner(file)
+	// the events can be larger than the default line limit
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &deadletter.Record{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode line %d of %s", line, path)
+		}
+		if record.Event == nil {
+			event := &apicommon.Event{}
+			if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+				return nil, errors.Wrapf(err, "failed to decode line %d of %s", line, path)
+			}
+			if event.Context.Source == "" && len(event.Data) == 0 {
+				return nil, errors.Errorf("line %d of %s is neither a dead-letter record nor an event", line, path)
+			}
+			record = &deadletter.Record{
+				ID:    event.Context.ID,
+				Event: event,
+			}
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
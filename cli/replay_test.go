@@ -0,0 +1,105 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/replay"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunReplay(t *testing.T) {
+	var lock sync.Mutex
+	var requests []*replay.Request
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "Bearer secret" {
+			writer.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(writer).Encode(&replay.Response{Error: "bearer token is invalid"})
+			return
+		}
+		replayRequest := &replay.Request{}
+		assert.Nil(t, json.NewDecoder(request.Body).Decode(replayRequest))
+		lock.Lock()
+		requests = append(requests, replayRequest)
+		lock.Unlock()
+		writer.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(writer).Encode(&replay.Response{Dependency: replayRequest.Dependency, Passed: true, Message: "event is queued for processing"})
+	}))
+	defer server.Close()
+
+	event := &apicommon.Event{
+		Context: apicommon.EventContext{
+			ID:     "event-1",
+			Source: "webhook-gateway",
+		},
+		Data: []byte(`{"name": "fake"}`),
+	}
+	record := deadletter.NewRecord("test-sensor", "dep1", "trigger", event, errors.New("trigger failed"), 3)
+
+	file, err := ioutil.TempFile("", "events")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	encoder := json.NewEncoder(file)
+	assert.Nil(t, encoder.Encode(record))
+	assert.Nil(t, encoder.Encode(event))
+	assert.Nil(t, file.Close())
+
+	records, err := readEventLog(file.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, record.ID, records[0].ID)
+	assert.Equal(t, "event-1", records[1].ID)
+	assert.Equal(t, "", records[1].Dependency)
+
+	// a record id without a file is looked up by the sensor
+	out := &bytes.Buffer{}
+	err = Run([]string{"replay", "--url", server.URL, "--token", "secret", "--record", record.ID, "--dry-run"}, out)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, record.ID, requests[0].RecordID)
+	assert.True(t, requests[0].DryRun)
+
+	// the events of the file are sent inline
+	err = Run([]string{"replay", "--url", server.URL, "--token", "secret", "--file", file.Name(), "--dependency", "dep2"}, out)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(requests))
+	assert.Equal(t, "dep2", requests[1].Dependency)
+	assert.Equal(t, 3, requests[1].Attempts)
+	assert.Equal(t, "event-1", requests[2].Event.Context.ID)
+
+	// the file can be narrowed down to a record
+	err = Run([]string{"replay", "--url", server.URL, "--token", "secret", "--file", file.Name(), "--record", record.ID}, out)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(requests))
+	assert.Equal(t, "dep1", requests[3].Dependency)
+
+	err = Run([]string{"replay", "--url", server.URL, "--token", "wrong", "--record", record.ID}, out)
+	assert.NotNil(t, err)
+	err = Run([]string{"replay", "--url", server.URL, "--token", "secret"}, out)
+	assert.NotNil(t, err)
+}
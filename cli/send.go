@@ -0,0 +1,174 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/google/uuid"
+	"github.com/nats-io/go-nats"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	register(&Command{
+		Name:  "send",
+		Short: "Send a test event to a sensor the way a gateway does",
+		Run:   runSend,
+	})
+}
+
+func runSend(args []string, out io.Writer) error {
+	flags := newFlagSet("send", out)
+	kube := addKubeFlags(flags)
+	sensorName := flags.String("sensor", "", "sensor to send the event to, over the event protocol of the sensor")
+	url := flags.String("url", "", "URL of the http server of the sensor, e.g. http://localhost:9300 through a port-forward. Overrides the address of the sensor")
+	gateway := flags.String("gateway", "", "name of the gateway the event is sent on behalf of")
+	eventName := flags.String("event", "", "name of the event source the event is sent on behalf of")
+	eventType := flags.String("type", "test", "type of the event")
+	data := flags.String("data", "{}", "JSON payload of the event")
+	dataFile := flags.String("data-file", "", "file with the payload of the event. Overrides the data")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if *gateway == "" || *eventName == "" {
+		return errors.New("gateway and event must be specified")
+	}
+	if *sensorName == "" && *url == "" {
+		return errors.New("either a sensor or a url must be specified")
+	}
+
+	payload := []byte(*data)
+	if *dataFile != "" {
+		var err error
+		if payload, err = ioutil.ReadFile(*dataFile); err != nil {
+			return errors.Wrapf(err, "failed to read %s", *dataFile)
+		}
+	}
+	event, err := newTestEvent(*gateway, *eventName, *eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	if *url != "" {
+		if err := sendEventOverHTTP(*url, event); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "sent event %s to %s\n", event.ID(), *url)
+		return nil
+	}
+
+	client, err := kube.sensorClient()
+	if err != nil {
+		return err
+	}
+	namespace, err := kube.getNamespace()
+	if err != nil {
+		return err
+	}
+	sensor, err := client.ArgoprojV1alpha1().Sensors(namespace).Get(*sensorName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the sensor %s", *sensorName)
+	}
+	target, err := sendEventToSensor(sensor, *gateway, event)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "sent event %s to %s\n", event.ID(), target)
+	return nil
+}
+
+// newTestEvent returns a cloud event as the gateway transforms the events of its event sources
+func newTestEvent(gateway, eventName, eventType string, payload []byte) (*cloudevents.Event, error) {
+	if !json.Valid(payload) {
+		return nil, errors.New("payload of the event must be valid JSON")
+	}
+	event := cloudevents.NewEvent(cloudevents.VersionV03)
+	event.SetID(fmt.Sprintf("%x", uuid.New()))
+	event.SetType(eventType)
+	event.SetSource(gateway)
+	event.SetDataContentType("application/json")
+	event.SetSubject(eventName)
+	event.SetTime(time.Now())
+	if err := event.SetData(payload); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// sendEventToSensor sends the event over the event protocol of the sensor and returns the target of the event
+func sendEventToSensor(sensor *v1alpha1.Sensor, gateway string, event *cloudevents.Event) (string, error) {
+	protocol := sensor.Spec.EventProtocol
+	if protocol != nil && protocol.Type == apicommon.NATS {
+		if protocol.Nats.Type == apicommon.Streaming {
+			return "", errors.New("sending events over nats streaming is not supported, send the event with --url instead")
+		}
+		subject := common.DefaultNatsSubject(gateway)
+		return subject, sendEventOverNATS(protocol.Nats.URL, subject, event)
+	}
+	if protocol == nil || protocol.Http.Port == "" {
+		return "", errors.Errorf("sensor %s has no http port", sensor.Name)
+	}
+	target := fmt.Sprintf("http://%s:%s%s", common.ServiceDNSName(sensor.Name, sensor.Namespace), protocol.Http.Port, common.SensorServiceEndpoint)
+	return target, sendEventOverHTTP(target, event)
+}
+
+// sendEventOverHTTP sends the event with the cloudevents http transport, the way the gateways send the events
+func sendEventOverHTTP(target string, event *cloudevents.Event) error {
+	t, err := cloudevents.NewHTTPTransport(
+		cloudevents.WithTarget(target),
+		cloudevents.WithEncoding(cloudevents.HTTPBinaryV02),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a transport")
+	}
+	client, err := cloudevents.NewClient(t)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a client")
+	}
+	if _, _, err := client.Send(context.Background(), *event); err != nil {
+		return errors.Wrap(err, "failed to send the event")
+	}
+	return nil
+}
+
+// sendEventOverNATS publishes the event in structured JSON encoding on the subject of the gateway
+func sendEventOverNATS(url, subject string, event *cloudevents.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %s", url)
+	}
+	defer conn.Close()
+	if err := conn.Publish(subject, payload); err != nil {
+		return errors.Wrap(err, "failed to publish the event")
+	}
+	return conn.Flush()
+}
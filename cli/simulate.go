@@ -0,0 +1,123 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/sensors/simulation"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+func init() {
+	register(&Command{
+		Name:  "simulate",
+		Short: "Feed sample events to a sensor offline and print the resources and payloads its triggers would create",
+		Run:   runSimulate,
+	})
+}
+
+func runSimulate(args []string, out io.Writer) error {
+	flags := newFlagSet("simulate", out)
+	sensorFile := flags.String("sensor", "", "YAML or JSON file of the sensor")
+	eventsFile := flags.String("events", "", "file of JSON lines with the events, or dead-letter records, fed to the sensor in order")
+	output := flags.String("output", "yaml", "output format, yaml or json")
+	inCluster := flags.Bool("in-cluster", false, "read the trigger sources that live in the cluster, e.g. config maps, with the kubeconfig. Nothing is written to the cluster")
+	kube := addKubeFlags(flags)
+	verbose := flags.Bool("verbose", false, "log the processing of the events")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if *sensorFile == "" || *eventsFile == "" {
+		return errors.New("sensor and events files must be specified")
+	}
+	if *output != "yaml" && *output != "json" {
+		return errors.Errorf("unknown output format %s", *output)
+	}
+
+	sensor, err := readSensor(*sensorFile)
+	if err != nil {
+		return err
+	}
+	if sensor.Namespace == "" {
+		if sensor.Namespace, err = kube.getNamespace(); err != nil {
+			return err
+		}
+	}
+	records, err := readEventLog(*eventsFile)
+	if err != nil {
+		return err
+	}
+	var events []*apicommon.Event
+	for _, record := range records {
+		events = append(events, record.Event)
+	}
+
+	var kubeClient kubernetes.Interface
+	if *inCluster {
+		if kubeClient, err = kube.kubeClient(); err != nil {
+			return err
+		}
+	}
+
+	logger := logrus.New()
+	if !*verbose {
+		logger.SetOutput(ioutil.Discard)
+	}
+
+	result, err := simulation.Simulate(kubeClient, sensor, events, logger)
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	if *output == "json" {
+		content, err = json.MarshalIndent(result, "", "  ")
+	} else {
+		content, err = yaml.Marshal(result)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the result")
+	}
+	if _, err := out.Write(append(content, '\n')); err != nil {
+		return err
+	}
+	if result.Failed() {
+		return errors.New("a trigger of the sensor failed")
+	}
+	return nil
+}
+
+// readSensor reads a sensor from a YAML or JSON file
+func readSensor(path string) (*v1alpha1.Sensor, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", path)
+	}
+	sensor := &v1alpha1.Sensor{}
+	if err := yaml.Unmarshal(content, sensor); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the sensor in %s", path)
+	}
+	return sensor, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var simulationSensor = `
+apiVersion: argoproj.io/v1alpha1
+kind: Sensor
+metadata:
+  name: webhook-sensor
+spec:
+  template:
+    spec:
+      containers:
+        - name: sensor
+          image: argoproj/sensor
+  eventProtocol:
+    type: HTTP
+    http:
+      port: "9300"
+  dependencies:
+    - name: "webhook-gateway:example"
+      gatewayName: webhook-gateway
+      eventName: example
+  triggers:
+    - template:
+        name: http-trigger
+        http:
+          url: http://example.com/deploy
+          payload:
+            - src:
+                event: "webhook-gateway:example"
+                dataKey: name
+              dest: app
+`
+
+func TestRunSimulate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simulate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	sensorFile := filepath.Join(dir, "sensor.yaml")
+	assert.Nil(t, ioutil.WriteFile(sensorFile, []byte(simulationSensor), 0644))
+	eventsFile := filepath.Join(dir, "events.json")
+	event := `{"context": {"eventID": "1", "source": "webhook-gateway", "subject": "example", "dataContentType": "application/json", "time": "2019-11-05T10:00:00.000000Z"}, "data": "eyJuYW1lIjogImZha2UifQ=="}`
+	assert.Nil(t, ioutil.WriteFile(eventsFile, []byte(event+"\n"), 0644))
+
+	out := &bytes.Buffer{}
+	err = Run([]string{"simulate", "--sensor", sensorFile, "--events", eventsFile}, out)
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "destination: http://example.com/deploy")
+	assert.Contains(t, out.String(), "app: fake")
+
+	err = Run([]string{"simulate", "--sensor", sensorFile}, out)
+	assert.NotNil(t, err)
+}
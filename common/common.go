@@ -30,6 +30,8 @@ const (
 	StandardYYYYMMDDFormat = "2006-01-02"
 	// DefaultControllerNamespace is the default namespace where the sensor and gateways controllers are installed
 	DefaultControllerNamespace = "argo-events"
+	// MaxConfigMapSize is the maximum size of the data of a config map
+	MaxConfigMapSize = 1024 * 1024
 )
 
 // Environment variables
@@ -80,6 +82,8 @@ const (
 	LabelSensorName = "sensor-name"
 	// Port for the sensor server to listen events on
 	SensorServerPort = 12000
+	// SensorReplayPort is the default port of the replay endpoint of the sensor
+	SensorReplayPort = 12100
 )
 
 // Gateway constants
@@ -91,6 +95,14 @@ const (
 	EnvVarGatewayServerPort = "GATEWAY_SERVER_PORT"
 	// Server Connection Timeout, 10 seconds
 	ServerConnTimeout = 10
+	// EnvVarGatewayQueueDir refers to the directory where the gateway client keeps the undelivered events
+	EnvVarGatewayQueueDir = "GATEWAY_QUEUE_DIR"
+	// DefaultGatewayQueueDir is the default directory for the undelivered events
+	DefaultGatewayQueueDir = "/tmp/argo-events/queue"
+	// DefaultGatewayQueueMaxEvents is the default maximum number of undelivered events kept for a sensor watcher
+	DefaultGatewayQueueMaxEvents = 10000
+	// GatewayQueueVolumeName is the name of the volume mounted at the directory of the undelivered events
+	GatewayQueueVolumeName = "event-queue"
 )
 
 const (
@@ -39,6 +39,7 @@ const (
 	LabelVersion        = "version"
 	LabelTime           = "time"
 	LabelTriggerName    = "trigger-name"
+	LabelNatsSubject    = "nats-subject"
 )
 
 // NewArgoEventsLogger returns a new ArgoEventsLogger
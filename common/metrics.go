@@ -0,0 +1,85 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+// Metrics constants
+const (
+	// EnvVarMetricsPort refers to the port the metrics server listens on
+	EnvVarMetricsPort = "METRICS_PORT"
+	// DefaultMetricsPort is the default port for the metrics server
+	DefaultMetricsPort = "9090"
+	// MetricsEndpoint is the endpoint to scrape metrics from
+	MetricsEndpoint = "/metrics"
+	// MetricsNamespace is the namespace of all argo-events metrics
+	MetricsNamespace = "argo_events"
+)
+
+// Metric labels
+const (
+	// MetricLabelGatewayName is the metric label for the gateway name
+	MetricLabelGatewayName = "gateway_name"
+	// MetricLabelEventSourceName is the metric label for the event source name
+	MetricLabelEventSourceName = "event_source_name"
+	// MetricLabelSensorName is the metric label for the sensor name
+	MetricLabelSensorName = "sensor_name"
+	// MetricLabelWatcher is the metric label for the sensor watcher an event is dispatched to
+	MetricLabelWatcher = "watcher"
+	// MetricLabelDependencyName is the metric label for the event dependency name
+	MetricLabelDependencyName = "dependency_name"
+	// MetricLabelTriggerName is the metric label for the trigger template name
+	MetricLabelTriggerName = "trigger_name"
+	// MetricLabelControllerName is the metric label for the controller name
+	MetricLabelControllerName = "controller_name"
+	// MetricLabelStatus is the metric label for the outcome of an operation
+	MetricLabelStatus = "status"
+	// MetricLabelReason is the metric label for the reason of an operation
+	MetricLabelReason = "reason"
+)
+
+// Metric label values for the outcome of an operation
+const (
+	MetricStatusSuccess = "success"
+	MetricStatusFailure = "failure"
+)
+
+// GetMetricsPort returns the port for the metrics server
+func GetMetricsPort() string {
+	if port, ok := os.LookupEnv(EnvVarMetricsPort); ok {
+		return port
+	}
+	return DefaultMetricsPort
+}
+
+// StartMetricsServer starts a http server that exposes the metrics registered with the default prometheus registry
+func StartMetricsServer(port string, logger *logrus.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle(MetricsEndpoint, promhttp.Handler())
+
+	logger.WithField("port", port).Infoln("starting metrics server")
+	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), mux); err != nil {
+		logger.WithError(err).Errorln("metrics server stopped")
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Knetic/govaluate"
+)
+
+// FuncMap returns the functions available to the templates.
+// The functions follow the names and argument order of the sprig library so the value can be piped as the last argument,
+// e.g. {{ .events.push.ref | trimPrefix "refs/heads/" }}
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		// strings
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      strings.Title,
+		"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"trunc":      trunc,
+		"substr":     substr,
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"quote":      func(v interface{}) string { return strconv.Quote(toString(v)) },
+		"squote":     func(v interface{}) string { return fmt.Sprintf("'%s'", toString(v)) },
+		"nospace":    func(s string) string { return strings.Join(strings.Fields(s), "") },
+
+		// regular expressions
+		"regexMatch":      func(regex, s string) (bool, error) { return regexp.MatchString(regex, s) },
+		"regexFind":       regexFind,
+		"regexReplaceAll": regexReplaceAll,
+
+		// defaults
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+
+		// encoding and conversion
+		"toJson":    toJSON,
+		"toString":  toString,
+		"atoi":      func(s string) (int, error) { return strconv.Atoi(s) },
+		"b64enc":    func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":    b64dec,
+		"sha256sum": sha256sum,
+
+		// time
+		"now":       time.Now,
+		"unixEpoch": func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) },
+		"date":      func(layout string, t time.Time) string { return t.Format(layout) },
+	}
+}
+
+// ExpressionFunctions returns the functions available to the expressions.
+// They take the value as the first argument, e.g. trimPrefix([events.push.ref], 'refs/heads/')
+func ExpressionFunctions() map[string]govaluate.ExpressionFunction {
+	return map[string]govaluate.ExpressionFunction{
+		"trimPrefix": stringFunction(2, func(args []string) interface{} { return strings.TrimPrefix(args[0], args[1]) }),
+		"trimSuffix": stringFunction(2, func(args []string) interface{} { return strings.TrimSuffix(args[0], args[1]) }),
+		"upper":      stringFunction(1, func(args []string) interface{} { return strings.ToUpper(args[0]) }),
+		"lower":      stringFunction(1, func(args []string) interface{} { return strings.ToLower(args[0]) }),
+		"contains":   stringFunction(2, func(args []string) interface{} { return strings.Contains(args[0], args[1]) }),
+		"hasPrefix":  stringFunction(2, func(args []string) interface{} { return strings.HasPrefix(args[0], args[1]) }),
+		"hasSuffix":  stringFunction(2, func(args []string) interface{} { return strings.HasSuffix(args[0], args[1]) }),
+		"replace":    stringFunction(3, func(args []string) interface{} { return strings.Replace(args[0], args[1], args[2], -1) }),
+		"len": func(args ...interface{}) (interface{}, error) {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("len expects 1 argument, got %d", len(args))
+			}
+			value := reflect.ValueOf(args[0])
+			switch value.Kind() {
+			case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+				return float64(value.Len()), nil
+			default:
+				return nil, fmt.Errorf("len is not supported for %T", args[0])
+			}
+		},
+	}
+}
+
+// stringFunction returns an expression function that converts its arguments to strings
+func stringFunction(count int, f func(args []string) interface{}) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) != count {
+			return nil, fmt.Errorf("function expects %d arguments, got %d", count, len(args))
+		}
+		values := make([]string, len(args))
+		for i, arg := range args {
+			values[i] = toString(arg)
+		}
+		return f(values), nil
+	}
+}
+
+// toString converts the value to a string. Maps and slices are rendered as JSON.
+func toString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case bool:
+		return strconv.FormatBool(value)
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	case map[string]interface{}, []interface{}:
+		return toJSON(value)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func toJSON(v interface{}) string {
+	output, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(output)
+}
+
+func join(sep string, v interface{}) string {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return toString(v)
+	}
+	values := make([]string, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		values[i] = toString(value.Index(i).Interface())
+	}
+	return strings.Join(values, sep)
+}
+
+func trunc(length int, s string) string {
+	if length < 0 || len(s) <= length {
+		return s
+	}
+	return s[:length]
+}
+
+func substr(start, end int, s string) string {
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 || end > len(s) {
+		end = len(s)
+	}
+	if start > end {
+		return ""
+	}
+	return s[start:end]
+}
+
+func regexFind(regex, s string) (string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return r.FindString(s), nil
+}
+
+func regexReplaceAll(regex, s, replacement string) (string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return r.ReplaceAllString(s, replacement), nil
+}
+
+func b64dec(s string) (string, error) {
+	output, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+func sha256sum(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:])
+}
+
+// empty returns true if the value is the zero value of its type
+func empty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+// defaultValue returns the default if the given value is empty
+func defaultValue(d interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || empty(given[0]) {
+		return d
+	}
+	return given[0]
+}
+
+// coalesce returns the first value that isn't empty
+func coalesce(values ...interface{}) interface{} {
+	for _, value := range values {
+		if !empty(value) {
+			return value
+		}
+	}
+	return nil
+}
+
+func ternary(vt, vf interface{}, condition bool) interface{} {
+	if condition {
+		return vt
+	}
+	return vf
+}
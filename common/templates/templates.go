@@ -0,0 +1,83 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package templates provides the Go template functions and the govaluate expressions that transform event data,
+// e.g. to compute the values of the trigger parameters.
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Knetic/govaluate"
+)
+
+// Parse parses the Go template with the template functions.
+// Referring to a key that doesn't exist fails the rendering instead of producing "<no value>".
+func Parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(FuncMap()).Option("missingkey=error").Parse(text)
+}
+
+// NewExpression parses the govaluate expression with the expression functions
+func NewExpression(expression string) (*govaluate.EvaluableExpression, error) {
+	return govaluate.NewEvaluableExpressionWithFunctions(expression, ExpressionFunctions())
+}
+
+// Parameters are the nested parameters of an expression. A parameter name is a path of keys separated by a dot
+// and must be escaped with brackets, e.g. [events.push.ref]. Keys may contain dots themselves, the longest matching key is used.
+type Parameters map[string]interface{}
+
+// Get returns the value of the parameter
+func (parameters Parameters) Get(name string) (interface{}, error) {
+	value, ok := lookup(map[string]interface{}(parameters), name)
+	if !ok {
+		return nil, fmt.Errorf("no parameter '%s' found", name)
+	}
+	return value, nil
+}
+
+// lookup returns the value at the path of keys within the nested maps
+func lookup(values map[string]interface{}, path string) (interface{}, bool) {
+	if value, ok := values[path]; ok {
+		return value, true
+	}
+	// try the longest key first so keys with dots are matched
+	for i := strings.LastIndex(path, "."); i > 0; i = strings.LastIndex(path[:i], ".") {
+		value, ok := values[path[:i]]
+		if !ok {
+			continue
+		}
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, ok := lookup(nested, path[i+1:]); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// Evaluate evaluates the expression with the parameters and returns the result as a string.
+// Numbers are formatted without trailing zeros so the result can be set as a JSON number.
+func Evaluate(expression *govaluate.EvaluableExpression, parameters Parameters) (string, error) {
+	result, err := expression.Eval(parameters)
+	if err != nil {
+		return "", err
+	}
+	return toString(result), nil
+}
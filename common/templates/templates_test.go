@@ -0,0 +1,105 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, text string, data interface{}) (string, error) {
+	tmpl, err := Parse("test", text)
+	assert.Nil(t, err)
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	return buf.String(), err
+}
+
+func TestTemplateFunctions(t *testing.T) {
+	data := map[string]interface{}{
+		"events": map[string]interface{}{
+			"push": map[string]interface{}{
+				"body": map[string]interface{}{
+					"ref":     "refs/heads/master",
+					"commits": []interface{}{"a", "b"},
+					"count":   float64(2),
+				},
+			},
+		},
+	}
+
+	tests := map[string]string{
+		`{{ .events.push.body.ref | trimPrefix "refs/heads/" }}`:                              "master",
+		`{{ .events.push.body.ref | trimPrefix "refs/heads/" | upper }}`:                      "MASTER",
+		`{{ .events.push.body.ref | replace "/" "-" }}`:                                       "refs-heads-master",
+		`{{ .events.push.body.commits | join "," }}`:                                          "a,b",
+		`{{ .events.push.body.commits | toJson }}`:                                            `["a","b"]`,
+		`{{ .events.push.body.count | toString }}`:                                            "2",
+		`{{ "" | default "fallback" }}`:                                                       "fallback",
+		`{{ regexReplaceAll "^refs/(tags|heads)/" .events.push.body.ref "" }}`:                "master",
+		`{{ if .events.push.body.ref | hasPrefix "refs/tags/" }}tag{{ else }}branch{{ end }}`: "branch",
+		`{{ "hello" | b64enc | b64dec }}`:                                                     "hello",
+		`{{ .events.push.body.ref | trunc 4 | quote }}`:                                       `"refs"`,
+	}
+	for text, expected := range tests {
+		output, err := render(t, text, data)
+		assert.Nil(t, err, text)
+		assert.Equal(t, expected, output, text)
+	}
+
+	_, err := render(t, `{{ .events.push.body.unknown }}`, data)
+	assert.NotNil(t, err)
+
+	_, err = Parse("test", `{{ .events | unknownFunction }}`)
+	assert.NotNil(t, err)
+}
+
+func TestExpressions(t *testing.T) {
+	parameters := Parameters{
+		"events": map[string]interface{}{
+			"push": map[string]interface{}{
+				"ref":   "refs/heads/master",
+				"count": float64(3),
+			},
+			"webhook-gateway:example.v1": map[string]interface{}{
+				"message": "hello",
+			},
+		},
+	}
+
+	tests := map[string]string{
+		`[events.push.count] * 2`:                                   "6",
+		`[events.push.count] > 2`:                                   "true",
+		`trimPrefix([events.push.ref], 'refs/heads/')`:              "master",
+		`upper([events.webhook-gateway:example.v1.message])`:        "HELLO",
+		`[events.push.ref] == 'refs/heads/master' ? 'prod' : 'dev'`: "prod",
+	}
+	for text, expected := range tests {
+		expression, err := NewExpression(text)
+		assert.Nil(t, err, text)
+		output, err := Evaluate(expression, parameters)
+		assert.Nil(t, err, text)
+		assert.Equal(t, expected, output, text)
+	}
+
+	expression, err := NewExpression(`[events.push.unknown] == 'a'`)
+	assert.Nil(t, err)
+	_, err = Evaluate(expression, parameters)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"contrib.go.opencensus.io/exporter/zipkin"
+	openzipkin "github.com/openzipkin/zipkin-go"
+	zipkinhttp "github.com/openzipkin/zipkin-go/reporter/http"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// EnvVarTracingExporter selects the exporter of the spans, either log or zipkin
+	EnvVarTracingExporter = "TRACING_EXPORTER"
+	// EnvVarTracingEndpoint is the url the zipkin exporter sends the spans to
+	EnvVarTracingEndpoint = "TRACING_ENDPOINT"
+	// ExporterLog is the default exporter, it logs the spans at debug level
+	ExporterLog = "log"
+	// ExporterZipkin sends the spans to a Zipkin compatible collector
+	ExporterZipkin = "zipkin"
+)
+
+var (
+	exporterLock sync.Mutex
+	exporter     trace.Exporter
+)
+
+// SetExporter replaces the exporter the spans are exported with. Every trace is sampled.
+func SetExporter(e trace.Exporter) {
+	exporterLock.Lock()
+	defer exporterLock.Unlock()
+	if exporter != nil {
+		trace.UnregisterExporter(exporter)
+	}
+	exporter = e
+	trace.RegisterExporter(e)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+}
+
+// ConfigureExporter sets the exporter selected by the environment of the process.
+// The service name identifies the process in the exported spans.
+// The returned function flushes the spans that are not exported yet.
+func ConfigureExporter(serviceName string) (func(), error) {
+	switch name := os.Getenv(EnvVarTracingExporter); name {
+	case "", ExporterLog:
+		SetExporter(&LogExporter{})
+		return func() {}, nil
+	case ExporterZipkin:
+		url, ok := os.LookupEnv(EnvVarTracingEndpoint)
+		if !ok {
+			return nil, fmt.Errorf("%s is required by the %s exporter", EnvVarTracingEndpoint, ExporterZipkin)
+		}
+		localEndpoint, err := openzipkin.NewEndpoint(serviceName, "")
+		if err != nil {
+			return nil, err
+		}
+		// the reporter logs the spans it drops when the collector can't keep up
+		reporter := zipkinhttp.NewReporter(url, zipkinhttp.Logger(log.New(logrus.StandardLogger().WriterLevel(logrus.WarnLevel), "", 0)))
+		SetExporter(zipkin.NewExporter(reporter, localEndpoint))
+		return func() {
+			if err := reporter.Close(); err != nil {
+				logrus.WithError(err).Warnln("failed to flush the spans")
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %s", name)
+	}
+}
+
+// FlushOnTermination flushes the spans once the process is asked to terminate, then exits the process
+func FlushOnTermination(flush func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	<-signals
+	flush()
+	os.Exit(0)
+}
+
+// LogExporter logs the spans at debug level
+type LogExporter struct{}
+
+// ExportSpan logs the span
+func (e *LogExporter) ExportSpan(span *trace.SpanData) {
+	fields := logrus.Fields{
+		"span-name":      span.Name,
+		"trace-id":       span.TraceID.String(),
+		"span-id":        span.SpanID.String(),
+		"parent-span-id": span.ParentSpanID.String(),
+		"duration":       span.EndTime.Sub(span.StartTime).String(),
+	}
+	for key, value := range span.Attributes {
+		fields[key] = value
+	}
+	entry := logrus.WithFields(fields)
+	if span.Code != trace.StatusCodeOK {
+		entry = entry.WithField("error", span.Message)
+	}
+	entry.Debugln("span ended")
+}
+
+// InMemoryExporter keeps the spans in memory. It is meant for tests.
+type InMemoryExporter struct {
+	lock  sync.Mutex
+	spans []*trace.SpanData
+}
+
+// NewInMemoryExporter returns an exporter that keeps the spans in memory
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// ExportSpan keeps the span
+func (e *InMemoryExporter) ExportSpan(span *trace.SpanData) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns the exported spans in the order they ended
+func (e *InMemoryExporter) Spans() []*trace.SpanData {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	spans := make([]*trace.SpanData, len(e.spans))
+	copy(spans, e.spans)
+	return spans
+}
+
+// SpansByName returns the exported spans with given name
+func (e *InMemoryExporter) SpansByName(name string) []*trace.SpanData {
+	var spans []*trace.SpanData
+	for _, span := range e.Spans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// Reset drops the exported spans
+func (e *InMemoryExporter) Reset() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = nil
+}
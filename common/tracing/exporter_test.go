@@ -0,0 +1,76 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+func TestConfigureExporter(t *testing.T) {
+	defer SetExporter(&LogExporter{})
+	defer os.Unsetenv(EnvVarTracingExporter)
+	defer os.Unsetenv(EnvVarTracingEndpoint)
+
+	flush, err := ConfigureExporter("fake-service")
+	assert.Nil(t, err)
+	assert.IsType(t, &LogExporter{}, exporter)
+	flush()
+
+	os.Setenv(EnvVarTracingExporter, ExporterZipkin)
+	_, err = ConfigureExporter("fake-service")
+	assert.NotNil(t, err)
+
+	os.Setenv(EnvVarTracingExporter, "unknown")
+	_, err = ConfigureExporter("fake-service")
+	assert.NotNil(t, err)
+}
+
+func TestConfigureExporter_Zipkin(t *testing.T) {
+	defer SetExporter(&LogExporter{})
+	defer os.Unsetenv(EnvVarTracingExporter)
+	defer os.Unsetenv(EnvVarTracingEndpoint)
+
+	var spans []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Nil(t, json.NewDecoder(request.Body).Decode(&spans))
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	os.Setenv(EnvVarTracingExporter, ExporterZipkin)
+	os.Setenv(EnvVarTracingEndpoint, server.URL)
+	flush, err := ConfigureExporter("fake-service")
+	assert.Nil(t, err)
+
+	_, span := trace.StartSpan(context.Background(), "span")
+	span.End()
+
+	// the pending spans are sent when they are flushed
+	flush()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "span", spans[0]["name"])
+	assert.Equal(t, span.SpanContext().TraceID.String(), spans[0]["traceId"])
+	assert.Equal(t, "fake-service", spans[0]["localEndpoint"].(map[string]interface{})["serviceName"])
+}
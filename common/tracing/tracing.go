@@ -0,0 +1,97 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing propagates the OpenCensus trace context with the events in the W3C traceparent format
+// (https://www.w3.org/TR/trace-context/), from the gateway server that receives them to the resources created by the sensor triggers.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"go.opencensus.io/trace"
+)
+
+const (
+	// TraceParentExtension is the name of the CloudEvent extension that carries the trace context
+	// See https://github.com/cloudevents/spec/blob/v0.3/extensions/distributed-tracing.md
+	TraceParentExtension = "traceparent"
+	// AnnotationTraceID is the annotation of the trace ID on the resources created by the triggers
+	AnnotationTraceID = "events.argoproj.io/trace-id"
+	// traceParentVersion is the only version of the traceparent format
+	traceParentVersion = "00"
+)
+
+// TraceParent returns the span context in the traceparent format, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func TraceParent(sc trace.SpanContext) string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceParentVersion, sc.TraceID, sc.SpanID, sc.TraceOptions)
+}
+
+// ParseTraceParent parses a span context in the traceparent format
+func ParseTraceParent(traceParent string) (trace.SpanContext, error) {
+	sc := trace.SpanContext{}
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) != 4 {
+		return sc, fmt.Errorf("invalid traceparent %s", traceParent)
+	}
+	if parts[0] != traceParentVersion {
+		return sc, fmt.Errorf("unsupported traceparent version %s", parts[0])
+	}
+	if !decodeID(parts[1], sc.TraceID[:]) {
+		return sc, fmt.Errorf("invalid trace id %s", parts[1])
+	}
+	if !decodeID(parts[2], sc.SpanID[:]) {
+		return sc, fmt.Errorf("invalid span id %s", parts[2])
+	}
+	options := make([]byte, 1)
+	if _, err := hex.Decode(options, []byte(parts[3])); err != nil || len(parts[3]) != 2 {
+		return sc, fmt.Errorf("invalid trace flags %s", parts[3])
+	}
+	sc.TraceOptions = trace.TraceOptions(options[0])
+	return sc, nil
+}
+
+// decodeID decodes the hex encoded id into the bytes of the id. All zero ids are invalid.
+func decodeID(id string, into []byte) bool {
+	if len(id) != 2*len(into) || id == strings.Repeat("0", 2*len(into)) {
+		return false
+	}
+	_, err := hex.Decode(into, []byte(id))
+	return err == nil
+}
+
+// StartSpanWithTraceParent starts a span as a child of the span in the traceparent format.
+// A new trace is started if the traceparent is invalid.
+func StartSpanWithTraceParent(ctx context.Context, name, traceParent string) (context.Context, *trace.Span) {
+	parent, err := ParseTraceParent(traceParent)
+	if err != nil {
+		return trace.StartSpan(ctx, name)
+	}
+	return trace.StartSpanWithRemoteParent(ctx, name, parent)
+}
+
+// RecordError sets the status of the span to the error the operation failed with. nil errors are ignored.
+func RecordError(span *trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.SetStatus(trace.Status{
+		Code:    trace.StatusCodeUnknown,
+		Message: err.Error(),
+	})
+}
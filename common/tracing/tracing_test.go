@@ -0,0 +1,80 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	sc, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Nil(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.True(t, sc.IsSampled())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", TraceParent(sc))
+
+	for _, traceParent := range []string{
+		"",
+		"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-xyz067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+	} {
+		_, err := ParseTraceParent(traceParent)
+		assert.NotNil(t, err, traceParent)
+	}
+}
+
+func TestStartSpanWithTraceParent(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	SetExporter(exporter)
+	defer SetExporter(&LogExporter{})
+
+	_, root := trace.StartSpan(context.Background(), "root")
+
+	// propagate the context the way it is carried by the events
+	_, child := StartSpanWithTraceParent(context.Background(), "child", TraceParent(root.SpanContext()))
+	assert.Equal(t, root.SpanContext().TraceID, child.SpanContext().TraceID)
+	assert.NotEqual(t, root.SpanContext().SpanID, child.SpanContext().SpanID)
+
+	child.AddAttributes(trace.StringAttribute("key", "value"))
+	RecordError(child, errors.New("fake error"))
+	child.End()
+	root.End()
+
+	spans := exporter.Spans()
+	assert.Equal(t, 2, len(spans))
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, root.SpanContext().SpanID, spans[0].ParentSpanID)
+	assert.Equal(t, "value", spans[0].Attributes["key"])
+	assert.Equal(t, "fake error", spans[0].Message)
+	assert.Equal(t, 1, len(exporter.SpansByName("root")))
+
+	// an invalid trace parent starts a new trace
+	_, span := StartSpanWithTraceParent(context.Background(), "new", "invalid")
+	assert.NotEqual(t, root.SpanContext().TraceID, span.SpanContext().TraceID)
+
+	exporter.Reset()
+	assert.Equal(t, 0, len(exporter.Spans()))
+}
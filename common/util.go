@@ -50,6 +50,11 @@ func DefaultNatsQueueName(subject string) string {
 	return fmt.Sprintf("%s-%s", subject, "queue")
 }
 
+// DefaultNatsSubject returns the nats subject a gateway publishes its events on
+func DefaultNatsSubject(gatewayName string) string {
+	return fmt.Sprintf("%s-%s", gatewayName, "events")
+}
+
 // GetClientConfig return rest config, if path not specified, assume in cluster config
 func GetClientConfig(kubeconfig string) (*rest.Config, error) {
 	if kubeconfig != "" {
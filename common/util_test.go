@@ -71,6 +71,12 @@ func TestDefaultNatsQueueName(t *testing.T) {
 	})
 }
 
+func TestDefaultNatsSubject(t *testing.T) {
+	convey.Convey("Given a gateway name, get the default nats subject", t, func() {
+		convey.So(DefaultNatsSubject("webhook-gateway"), convey.ShouldEqual, "webhook-gateway-events")
+	})
+}
+
 func TestHTTPMethods(t *testing.T) {
 	convey.Convey("Given a http write", t, func() {
 		convey.Convey("Write a success response", func() {
@@ -0,0 +1,43 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+
+	"github.com/argoproj/argo-events/controllers/admission"
+)
+
+func main() {
+	port, ok := os.LookupEnv(admission.EnvVarPort)
+	if !ok {
+		port = admission.DefaultPort
+	}
+	certFile, ok := os.LookupEnv(admission.EnvVarCertFile)
+	if !ok {
+		panic("tls certificate of the admission webhook is not provided")
+	}
+	keyFile, ok := os.LookupEnv(admission.EnvVarKeyFile)
+	if !ok {
+		panic("tls private key of the admission webhook is not provided")
+	}
+
+	server := admission.NewServer(port, certFile, keyFile)
+	if err := server.Start(); err != nil {
+		panic(err)
+	}
+}
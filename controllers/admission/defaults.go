@@ -0,0 +1,122 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"github.com/argoproj/argo-events/common"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	eventsourcev1alpha1 "github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	gatewayv1alpha1 "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	sensorv1alpha1 "github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+)
+
+const (
+	// DefaultGatewayProcessorPort is the default port of the gateway server
+	DefaultGatewayProcessorPort = "9330"
+	// DefaultHTTPPort is the default port of the http server that receives events from gateways
+	DefaultHTTPPort = "9300"
+	// DefaultGatewayReplica is the default number of gateway replicas
+	DefaultGatewayReplica = 1
+)
+
+// setGatewayDefaults fills in the defaults of the gateway
+func setGatewayDefaults(gateway *gatewayv1alpha1.Gateway) {
+	if gateway.Spec.ProcessorPort == "" {
+		gateway.Spec.ProcessorPort = DefaultGatewayProcessorPort
+	}
+	if gateway.Spec.Replica == 0 {
+		gateway.Spec.Replica = DefaultGatewayReplica
+	}
+	setEventProtocolDefaults(gateway.Spec.EventProtocol)
+}
+
+// setSensorDefaults fills in the defaults of the sensor
+func setSensorDefaults(sensor *sensorv1alpha1.Sensor) {
+	setEventProtocolDefaults(sensor.Spec.EventProtocol)
+	if sensor.Spec.Replay != nil && sensor.Spec.Replay.Port == 0 {
+		sensor.Spec.Replay.Port = common.SensorReplayPort
+	}
+	for _, trigger := range sensor.Spec.Triggers {
+		if trigger.Template == nil {
+			continue
+		}
+		if trigger.Template.NATS != nil && trigger.Template.NATS.ConnectionBackoff == nil {
+			trigger.Template.NATS.ConnectionBackoff = defaultSensorBackoff()
+		}
+		if trigger.Template.AMQP != nil && trigger.Template.AMQP.ConnectionBackoff == nil {
+			trigger.Template.AMQP.ConnectionBackoff = defaultSensorBackoff()
+		}
+	}
+}
+
+// setEventSourceDefaults fills in the defaults of the event source
+func setEventSourceDefaults(eventSource *eventsourcev1alpha1.EventSource) {
+	if eventSource.Spec == nil {
+		return
+	}
+	for name, value := range eventSource.Spec.AMQP {
+		if value.ConnectionBackoff == nil {
+			value.ConnectionBackoff = defaultBackoff()
+			eventSource.Spec.AMQP[name] = value
+		}
+	}
+	for name, value := range eventSource.Spec.Kafka {
+		if value.ConnectionBackoff == nil {
+			value.ConnectionBackoff = defaultBackoff()
+			eventSource.Spec.Kafka[name] = value
+		}
+	}
+	for name, value := range eventSource.Spec.MQTT {
+		if value.ConnectionBackoff == nil {
+			value.ConnectionBackoff = defaultBackoff()
+			eventSource.Spec.MQTT[name] = value
+		}
+	}
+	for name, value := range eventSource.Spec.NATS {
+		if value.ConnectionBackoff == nil {
+			value.ConnectionBackoff = defaultBackoff()
+			eventSource.Spec.NATS[name] = value
+		}
+	}
+}
+
+// setEventProtocolDefaults fills in the port of the http server of the event protocol
+func setEventProtocolDefaults(eventProtocol *apicommon.EventProtocol) {
+	if eventProtocol != nil && eventProtocol.Type == apicommon.HTTP && eventProtocol.Http.Port == "" {
+		eventProtocol.Http.Port = DefaultHTTPPort
+	}
+}
+
+// defaultBackoff returns the connection backoff of the event sources, the retry settings the gateway servers fall back to
+func defaultBackoff() *common.Backoff {
+	return &common.Backoff{
+		Duration: common.DefaultRetry.Duration,
+		Factor:   common.DefaultRetry.Factor,
+		Jitter:   common.DefaultRetry.Jitter,
+		Steps:    common.DefaultRetry.Steps,
+	}
+}
+
+// defaultSensorBackoff returns the connection backoff of the triggers, the retry settings the triggers fall back to
+func defaultSensorBackoff() *sensorv1alpha1.Backoff {
+	return &sensorv1alpha1.Backoff{
+		Duration: common.DefaultRetry.Duration,
+		Factor:   common.DefaultRetry.Factor,
+		Jitter:   common.DefaultRetry.Jitter,
+		Steps:    common.DefaultRetry.Steps,
+	}
+}
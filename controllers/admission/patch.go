@@ -0,0 +1,149 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultsPatch returns the JSON patch operations that add the defaulted fields of the specification to the object of the request.
+// The specification before and after the defaults are compared to find the defaulted fields. A defaulted field is added along with
+// its first ancestor that is missing from the object, so the patch applies to the object as it was sent and leaves the other fields untouched.
+func defaultsPatch(raw []byte, before, after []byte) ([]patchOperation, error) {
+	var obj struct {
+		Spec interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	var beforeSpec, afterSpec interface{}
+	if err := json.Unmarshal(before, &beforeSpec); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(after, &afterSpec); err != nil {
+		return nil, err
+	}
+
+	var operations []patchOperation
+	added := make(map[string]bool)
+	for _, path := range changedPaths(beforeSpec, afterSpec, []string{"spec"}) {
+		path = firstMissingPath(map[string]interface{}{"spec": obj.Spec}, path)
+		pointer := jsonPointer(path)
+		if added[pointer] {
+			continue
+		}
+		added[pointer] = true
+		operations = append(operations, patchOperation{
+			Op:    "add",
+			Path:  pointer,
+			Value: valueAt(map[string]interface{}{"spec": afterSpec}, path),
+		})
+	}
+	return operations, nil
+}
+
+// changedPaths returns the paths of the values that are set or changed in after. The defaults don't remove fields, so the removed ones are ignored.
+func changedPaths(before, after interface{}, path []string) [][]string {
+	if reflect.DeepEqual(before, after) || after == nil {
+		return nil
+	}
+	switch after := after.(type) {
+	case map[string]interface{}:
+		before, ok := before.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(after))
+		for key := range after {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var paths [][]string
+		for _, key := range keys {
+			paths = append(paths, changedPaths(before[key], after[key], appendPath(path, key))...)
+		}
+		return paths
+	case []interface{}:
+		before, ok := before.([]interface{})
+		if !ok || len(before) != len(after) {
+			break
+		}
+		var paths [][]string
+		for i := range after {
+			paths = append(paths, changedPaths(before[i], after[i], appendPath(path, strconv.Itoa(i)))...)
+		}
+		return paths
+	}
+	return [][]string{path}
+}
+
+// firstMissingPath returns the path of the first ancestor of the value at the path that is missing from the object, or the path itself if all its ancestors exist
+func firstMissingPath(obj interface{}, path []string) []string {
+	current := obj
+	for i, key := range path[:len(path)-1] {
+		next, ok := child(current, key)
+		if !ok || next == nil {
+			return path[:i+1]
+		}
+		current = next
+	}
+	return path
+}
+
+// valueAt returns the value at the path of the object
+func valueAt(obj interface{}, path []string) interface{} {
+	for _, key := range path {
+		obj, _ = child(obj, key)
+	}
+	return obj
+}
+
+// child returns the field or the item of the object with the key
+func child(obj interface{}, key string) (interface{}, bool) {
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		value, ok := obj[key]
+		return value, ok
+	case []interface{}:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(obj) {
+			return nil, false
+		}
+		return obj[i], true
+	}
+	return nil, false
+}
+
+// appendPath returns a copy of the path with the key appended, so that sibling paths don't share their backing array
+func appendPath(path []string, key string) []string {
+	return append(append([]string{}, path...), key)
+}
+
+// jsonPointer returns the JSON pointer of the path as per RFC 6901
+func jsonPointer(path []string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	var pointer strings.Builder
+	for _, key := range path {
+		pointer.WriteString("/")
+		pointer.WriteString(escaper.Replace(key))
+	}
+	return pointer.String()
+}
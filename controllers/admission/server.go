@@ -0,0 +1,278 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/controllers/eventsource"
+	"github.com/argoproj/argo-events/controllers/gateway"
+	"github.com/argoproj/argo-events/controllers/sensor"
+	eventsourceapi "github.com/argoproj/argo-events/pkg/apis/eventsources"
+	eventsourcev1alpha1 "github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	gatewayapi "github.com/argoproj/argo-events/pkg/apis/gateway"
+	gatewayv1alpha1 "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	sensorapi "github.com/argoproj/argo-events/pkg/apis/sensor"
+	sensorv1alpha1 "github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ValidateEndpoint is the endpoint of the validating admission webhook
+	ValidateEndpoint = "/validate"
+	// MutateEndpoint is the endpoint of the defaulting admission webhook
+	MutateEndpoint = "/mutate"
+	// DefaultPort is the default port of the admission webhook server
+	DefaultPort = "8443"
+	// EnvVarPort refers to the port of the admission webhook server
+	EnvVarPort = "ADMISSION_WEBHOOK_PORT"
+	// EnvVarCertFile refers to the path of the TLS certificate of the admission webhook server
+	EnvVarCertFile = "ADMISSION_WEBHOOK_CERT_FILE"
+	// EnvVarKeyFile refers to the path of the TLS private key of the admission webhook server
+	EnvVarKeyFile = "ADMISSION_WEBHOOK_KEY_FILE"
+)
+
+// Server serves the validating and defaulting admission webhooks of gateways, sensors and event sources
+type Server struct {
+	// Port on which the server listens
+	Port string
+	// CertFile is the path to the TLS certificate of the server
+	CertFile string
+	// KeyFile is the path to the TLS private key of the server
+	KeyFile string
+	// logger to log stuff
+	logger *logrus.Logger
+}
+
+// patchOperation is a JSON patch operation
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// NewServer returns a new admission webhook server
+func NewServer(port, certFile, keyFile string) *Server {
+	return &Server{
+		Port:     port,
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		logger:   common.NewArgoEventsLogger(),
+	}
+}
+
+// Handler returns the http handler of the admission webhooks
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ValidateEndpoint, server.serve(validate))
+	mux.HandleFunc(MutateEndpoint, server.serve(mutate))
+	return mux
+}
+
+// Start starts the TLS server of the admission webhooks. The API server only calls webhooks over TLS.
+func (server *Server) Start() error {
+	server.logger.WithField("port", server.Port).Infoln("starting the admission webhook server...")
+	httpServer := &http.Server{
+		Addr:    fmt.Sprintf(":%s", server.Port),
+		Handler: server.Handler(),
+	}
+	return httpServer.ListenAndServeTLS(server.CertFile, server.KeyFile)
+}
+
+// serve decodes the admission review, admits its request and writes the review back with the response
+func (server *Server) serve(admit func(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			writer.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			server.logger.WithError(err).Errorln("failed to read the admission review")
+			common.SendErrorResponse(writer, err.Error())
+			return
+		}
+		var review *v1beta1.AdmissionReview
+		if err := json.Unmarshal(body, &review); err != nil || review == nil || review.Request == nil {
+			server.logger.WithError(err).Errorln("failed to decode the admission review")
+			common.SendErrorResponse(writer, "invalid admission review")
+			return
+		}
+
+		logger := server.logger.WithFields(logrus.Fields{
+			"kind":      review.Request.Kind.Kind,
+			"name":      review.Request.Name,
+			"namespace": review.Request.Namespace,
+			"operation": review.Request.Operation,
+		})
+
+		response := admit(review.Request)
+		response.UID = review.Request.UID
+		if !response.Allowed {
+			logger.WithField("reason", response.Result.Message).Infoln("rejected the resource")
+		}
+
+		review.Response = response
+		review.Request = nil
+		data, err := json.Marshal(review)
+		if err != nil {
+			logger.WithError(err).Errorln("failed to encode the admission review")
+			common.SendInternalErrorResponse(writer, err.Error())
+			return
+		}
+		writer.Header().Set("Content-Type", common.MediaTypeJSON)
+		writer.WriteHeader(http.StatusOK)
+		writer.Write(data)
+	}
+}
+
+// validate rejects the resources that fail the validation of their controller.
+// The updates that leave the specification unchanged, e.g. the status updates of the controllers, are admitted as is.
+func validate(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	if request.Operation == v1beta1.Delete || specUnchanged(request) {
+		return &v1beta1.AdmissionResponse{Allowed: true}
+	}
+	obj, err := decodeObject(request)
+	if err != nil {
+		return deny(err)
+	}
+	switch obj := obj.(type) {
+	case *gatewayv1alpha1.Gateway:
+		err = gateway.Validate(obj)
+	case *sensorv1alpha1.Sensor:
+		err = sensor.ValidateSensor(obj)
+	case *eventsourcev1alpha1.EventSource:
+		err = eventsource.ValidateEventSource(obj)
+	}
+	if err != nil {
+		return deny(err)
+	}
+	return &v1beta1.AdmissionResponse{Allowed: true}
+}
+
+// mutate fills in the defaults of the resources with a JSON patch that adds the defaulted fields of their specification.
+// The updates that leave the specification unchanged, e.g. the status updates of the controllers, are not patched.
+// The resources that can't be decoded are admitted unpatched, rejecting them is left to the validating webhook.
+func mutate(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	if request.Operation != v1beta1.Create && request.Operation != v1beta1.Update || specUnchanged(request) {
+		return &v1beta1.AdmissionResponse{Allowed: true}
+	}
+	obj, err := decodeObject(request)
+	if err != nil {
+		return &v1beta1.AdmissionResponse{Allowed: true}
+	}
+
+	var spec func() interface{}
+	var setDefaults func()
+	switch obj := obj.(type) {
+	case *gatewayv1alpha1.Gateway:
+		spec = func() interface{} { return obj.Spec }
+		setDefaults = func() { setGatewayDefaults(obj) }
+	case *sensorv1alpha1.Sensor:
+		spec = func() interface{} { return obj.Spec }
+		setDefaults = func() { setSensorDefaults(obj) }
+	case *eventsourcev1alpha1.EventSource:
+		spec = func() interface{} { return obj.Spec }
+		setDefaults = func() { setEventSourceDefaults(obj) }
+	}
+
+	before, err := json.Marshal(spec())
+	if err != nil {
+		return deny(err)
+	}
+	setDefaults()
+	after, err := json.Marshal(spec())
+	if err != nil {
+		return deny(err)
+	}
+	if bytes.Equal(before, after) {
+		return &v1beta1.AdmissionResponse{Allowed: true}
+	}
+
+	operations, err := defaultsPatch(request.Object.Raw, before, after)
+	if err != nil {
+		return deny(err)
+	}
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return deny(err)
+	}
+	patchType := v1beta1.PatchTypeJSONPatch
+	return &v1beta1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+	}
+}
+
+// specUnchanged tells whether the request updates the resource without changing its specification
+func specUnchanged(request *v1beta1.AdmissionRequest) bool {
+	if request.Operation != v1beta1.Update || request.OldObject.Raw == nil {
+		return false
+	}
+	var oldObj, obj struct {
+		Spec interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal(request.OldObject.Raw, &oldObj); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(request.Object.Raw, &obj); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldObj.Spec, obj.Spec)
+}
+
+// decodeObject decodes the object of the admission request into the resource of its kind
+func decodeObject(request *v1beta1.AdmissionRequest) (interface{}, error) {
+	var obj interface{}
+	switch request.Kind.Kind {
+	case gatewayapi.Kind:
+		obj = &gatewayv1alpha1.Gateway{}
+	case sensorapi.Kind:
+		obj = &sensorv1alpha1.Sensor{}
+	case eventsourceapi.Kind:
+		obj = &eventsourcev1alpha1.EventSource{}
+	default:
+		return nil, errors.Errorf("unsupported kind %s", request.Kind.Kind)
+	}
+	if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the %s", request.Kind.Kind)
+	}
+	return obj, nil
+}
+
+// deny returns a response that rejects the resource
+func deny(err error) *v1beta1.AdmissionResponse {
+	return &v1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var gatewayWithoutPorts = `
+apiVersion: argoproj.io/v1alpha1
+kind: Gateway
+metadata:
+  name: webhook-gateway
+spec:
+  type: "webhook"
+  eventSourceRef:
+    name: "webhook-event-source"
+  eventProtocol:
+    type: "HTTP"
+  template:
+    spec:
+      containers:
+        - name: "gateway-client"
+          image: "argoproj/gateway-client"
+  watchers:
+    sensors:
+      - name: "webhook-sensor"
+`
+
+var invalidEventSource = `
+apiVersion: argoproj.io/v1alpha1
+kind: EventSource
+metadata:
+  name: mqtt-event-source
+spec:
+  type: "mqtt"
+  mqtt:
+    example:
+      url: "tcp://mqtt.argo-events:1883"
+`
+
+var eventSourceWithoutBackoff = `
+apiVersion: argoproj.io/v1alpha1
+kind: EventSource
+metadata:
+  name: mqtt-event-source
+spec:
+  type: "mqtt"
+  mqtt:
+    example:
+      url: "tcp://mqtt.argo-events:1883"
+      topic: "foo"
+      clientId: "1234"
+`
+
+func review(t *testing.T, endpoint, kind, manifest string, operation v1beta1.Operation) *v1beta1.AdmissionResponse {
+	raw, err := yaml.YAMLToJSON([]byte(manifest))
+	assert.Nil(t, err)
+	return send(t, endpoint, &v1beta1.AdmissionRequest{
+		UID:       types.UID("1234"),
+		Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: kind},
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+	})
+}
+
+func reviewUpdate(t *testing.T, endpoint, kind, oldManifest, manifest string) *v1beta1.AdmissionResponse {
+	oldRaw, err := yaml.YAMLToJSON([]byte(oldManifest))
+	assert.Nil(t, err)
+	raw, err := yaml.YAMLToJSON([]byte(manifest))
+	assert.Nil(t, err)
+	return send(t, endpoint, &v1beta1.AdmissionRequest{
+		UID:       types.UID("1234"),
+		Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: kind},
+		Operation: v1beta1.Update,
+		Object:    runtime.RawExtension{Raw: raw},
+		OldObject: runtime.RawExtension{Raw: oldRaw},
+	})
+}
+
+func send(t *testing.T, endpoint string, request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	body, err := json.Marshal(&v1beta1.AdmissionReview{
+		Request: request,
+	})
+	assert.Nil(t, err)
+
+	server := NewServer(DefaultPort, "", "")
+	writer := httptest.NewRecorder()
+	server.Handler().ServeHTTP(writer, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	var result *v1beta1.AdmissionReview
+	err = json.Unmarshal(writer.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.NotNil(t, result.Response)
+	assert.Equal(t, types.UID("1234"), result.Response.UID)
+	return result.Response
+}
+
+// applyPatch applies the add operations of the JSON patch to the manifest
+func applyPatch(t *testing.T, manifest string, patch []byte) map[string]interface{} {
+	raw, err := yaml.YAMLToJSON([]byte(manifest))
+	assert.Nil(t, err)
+	var obj map[string]interface{}
+	assert.Nil(t, json.Unmarshal(raw, &obj))
+	var operations []patchOperation
+	assert.Nil(t, json.Unmarshal(patch, &operations))
+	for _, operation := range operations {
+		assert.Equal(t, "add", operation.Op)
+		keys := strings.Split(strings.TrimPrefix(operation.Path, "/"), "/")
+		parent := obj
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := parent[key].(map[string]interface{})
+			assert.True(t, ok, "the parent of %s must exist", operation.Path)
+			parent = child
+		}
+		parent[keys[len(keys)-1]] = operation.Value
+	}
+	return obj
+}
+
+func TestValidate(t *testing.T) {
+	response := review(t, ValidateEndpoint, "Gateway", gatewayWithoutPorts, v1beta1.Create)
+	assert.False(t, response.Allowed)
+	assert.Equal(t, "gateway processor port is not specified", response.Result.Message)
+
+	response = review(t, ValidateEndpoint, "EventSource", invalidEventSource, v1beta1.Create)
+	assert.False(t, response.Allowed)
+	assert.Contains(t, response.Result.Message, "topic must be specified")
+
+	response = review(t, ValidateEndpoint, "EventSource", eventSourceWithoutBackoff, v1beta1.Update)
+	assert.True(t, response.Allowed)
+
+	response = review(t, ValidateEndpoint, "Workflow", eventSourceWithoutBackoff, v1beta1.Create)
+	assert.False(t, response.Allowed)
+
+	content, err := ioutil.ReadFile("../../examples/sensors/webhook.yaml")
+	assert.Nil(t, err)
+	response = review(t, ValidateEndpoint, "Sensor", string(content), v1beta1.Create)
+	assert.True(t, response.Allowed)
+}
+
+func TestMutate(t *testing.T) {
+	response := review(t, MutateEndpoint, "Gateway", gatewayWithoutPorts, v1beta1.Create)
+	assert.True(t, response.Allowed)
+	assert.Equal(t, v1beta1.PatchTypeJSONPatch, *response.PatchType)
+	var operations []patchOperation
+	assert.Nil(t, json.Unmarshal(response.Patch, &operations))
+	var paths []string
+	for _, operation := range operations {
+		paths = append(paths, operation.Path)
+	}
+	// only the defaulted fields are added, the fields of the gateway are left as they were sent
+	assert.Equal(t, []string{"/spec/eventProtocol/http", "/spec/processorPort", "/spec/replica"}, paths)
+	gateway := applyPatch(t, gatewayWithoutPorts, response.Patch)
+	spec := gateway["spec"].(map[string]interface{})
+	assert.Equal(t, DefaultGatewayProcessorPort, spec["processorPort"])
+	assert.Equal(t, float64(DefaultGatewayReplica), spec["replica"])
+	assert.Equal(t, DefaultHTTPPort, spec["eventProtocol"].(map[string]interface{})["http"].(map[string]interface{})["port"])
+
+	defaulted, err := json.Marshal(gateway)
+	assert.Nil(t, err)
+	response = review(t, ValidateEndpoint, "Gateway", string(defaulted), v1beta1.Create)
+	assert.True(t, response.Allowed)
+	response = review(t, MutateEndpoint, "Gateway", string(defaulted), v1beta1.Create)
+	assert.True(t, response.Allowed)
+	assert.Nil(t, response.Patch)
+
+	response = review(t, MutateEndpoint, "EventSource", eventSourceWithoutBackoff, v1beta1.Create)
+	assert.True(t, response.Allowed)
+	eventSource := applyPatch(t, eventSourceWithoutBackoff, response.Patch)
+	backoff := eventSource["spec"].(map[string]interface{})["mqtt"].(map[string]interface{})["example"].(map[string]interface{})["connectionBackoff"].(map[string]interface{})
+	assert.Equal(t, float64(common.DefaultRetry.Duration), backoff["duration"])
+	assert.Equal(t, float64(common.DefaultRetry.Steps), backoff["steps"])
+
+	response = review(t, MutateEndpoint, "EventSource", eventSourceWithoutBackoff, v1beta1.Delete)
+	assert.True(t, response.Allowed)
+	assert.Nil(t, response.Patch)
+
+	// the resources that can't be decoded are left to the validating webhook
+	undecodable := gatewayWithoutPorts + `
+  replica: "two"
+`
+	response = review(t, MutateEndpoint, "Gateway", undecodable, v1beta1.Create)
+	assert.True(t, response.Allowed)
+	assert.Nil(t, response.Patch)
+	response = review(t, ValidateEndpoint, "Gateway", undecodable, v1beta1.Create)
+	assert.False(t, response.Allowed)
+}
+
+func TestDefaultsPatch(t *testing.T) {
+	raw := []byte(`{"spec": {"labels": {"app.kubernetes.io/name": "foo"}, "triggers": [{"name": "a"}, {"name": "b", "backoff": null}]}}`)
+	before := []byte(`{"labels": {"app.kubernetes.io/name": "foo"}, "triggers": [{"name": "a"}, {"name": "b"}]}`)
+	after := []byte(`{"labels": {"app.kubernetes.io/name": "foo", "a/b~c": "bar"}, "triggers": [{"name": "a", "port": 1}, {"name": "b", "backoff": {"steps": 1}}], "replay": {"port": 2}}`)
+	operations, err := defaultsPatch(raw, before, after)
+	assert.Nil(t, err)
+	assert.Equal(t, []patchOperation{
+		{Op: "add", Path: "/spec/labels/a~1b~0c", Value: "bar"},
+		{Op: "add", Path: "/spec/replay", Value: map[string]interface{}{"port": float64(2)}},
+		{Op: "add", Path: "/spec/triggers/0/port", Value: float64(1)},
+		{Op: "add", Path: "/spec/triggers/1/backoff", Value: map[string]interface{}{"steps": float64(1)}},
+	}, operations)
+}
+
+func TestStatusUpdate(t *testing.T) {
+	// the controller can record the status of a resource that fails the validation
+	withStatus := gatewayWithoutPorts + `
+status:
+  phase: Error
+  message: gateway processor port is not specified
+`
+	response := reviewUpdate(t, ValidateEndpoint, "Gateway", gatewayWithoutPorts, withStatus)
+	assert.True(t, response.Allowed)
+	response = reviewUpdate(t, MutateEndpoint, "Gateway", gatewayWithoutPorts, withStatus)
+	assert.True(t, response.Allowed)
+	assert.Nil(t, response.Patch)
+
+	// an update of the specification is still validated and defaulted
+	response = reviewUpdate(t, ValidateEndpoint, "EventSource", eventSourceWithoutBackoff, invalidEventSource)
+	assert.False(t, response.Allowed)
+	response = reviewUpdate(t, MutateEndpoint, "EventSource", invalidEventSource, eventSourceWithoutBackoff)
+	assert.True(t, response.Allowed)
+	assert.NotNil(t, response.Patch)
+}
+
+func TestServeInvalidReview(t *testing.T) {
+	server := NewServer(DefaultPort, "", "")
+
+	writer := httptest.NewRecorder()
+	server.Handler().ServeHTTP(writer, httptest.NewRequest(http.MethodPost, ValidateEndpoint, bytes.NewReader([]byte("{}"))))
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+
+	writer = httptest.NewRecorder()
+	server.Handler().ServeHTTP(writer, httptest.NewRequest(http.MethodGet, ValidateEndpoint, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, writer.Code)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// reconcileDuration observes how long the controllers take to operate on a resource
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "controller",
+		Name:      "reconcile_duration_seconds",
+		Help:      "Time taken by the controller to operate on a resource, by outcome",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{common.MetricLabelControllerName, common.MetricLabelStatus})
+
+	// queueDepth is the number of resources waiting in the controller queue
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "controller",
+		Name:      "queue_depth",
+		Help:      "Number of resources waiting in the controller queue",
+	}, []string{common.MetricLabelControllerName})
+)
+
+func init() {
+	prometheus.MustRegister(reconcileDuration, queueDepth)
+}
+
+// RecordReconcile records the duration and the outcome of a controller operation that started at given time
+func RecordReconcile(controllerName string, start time.Time, err error) {
+	status := common.MetricStatusSuccess
+	if err != nil {
+		status = common.MetricStatusFailure
+	}
+	reconcileDuration.WithLabelValues(controllerName, status).Observe(time.Since(start).Seconds())
+}
+
+// RecordQueueDepth records the number of resources waiting in the controller queue
+func RecordQueueDepth(controllerName string, depth int) {
+	queueDepth.WithLabelValues(controllerName).Set(float64(depth))
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// sampleCount returns the number of observations made by the histogram
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	metric := &dto.Metric{}
+	assert.Nil(t, observer.(prometheus.Histogram).Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestRecordQueueDepth(t *testing.T) {
+	RecordQueueDepth("fake-controller", 3)
+	assert.Equal(t, float64(3), testutil.ToFloat64(queueDepth.WithLabelValues("fake-controller")))
+
+	RecordQueueDepth("fake-controller", 0)
+	assert.Equal(t, float64(0), testutil.ToFloat64(queueDepth.WithLabelValues("fake-controller")))
+}
+
+func TestRecordReconcile(t *testing.T) {
+	RecordReconcile("fake-controller", time.Now(), nil)
+	RecordReconcile("fake-controller", time.Now(), errors.New("fake error"))
+	RecordReconcile("fake-controller", time.Now(), errors.New("fake error"))
+
+	assert.Equal(t, uint64(1), sampleCount(t, reconcileDuration.WithLabelValues("fake-controller", common.MetricStatusSuccess)))
+	assert.Equal(t, uint64(2), sampleCount(t, reconcileDuration.WithLabelValues("fake-controller", common.MetricStatusFailure)))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	base "github.com/argoproj/argo-events"
+	"github.com/argoproj/argo-events/common"
+	controllerscommon "github.com/argoproj/argo-events/controllers/common"
+	"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	eventsourceclientset "github.com/argoproj/argo-events/pkg/client/eventsources/clientset/versioned"
+	gatewayclientset "github.com/argoproj/argo-events/pkg/client/gateway/clientset/versioned"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	eventSourceResyncPeriod = 20 * time.Minute
+	rateLimiterBaseDelay    = 5 * time.Second
+	rateLimiterMaxDelay     = 1000 * time.Second
+	// controllerName is the name of the controller in the metrics
+	controllerName = "event-source-controller"
+)
+
+// Controller listens for event sources, validates their entries and records the result along with
+// the gateways that refer to them in the event source status
+type Controller struct {
+	// Namespace to watch the event sources and gateways in. All namespaces are watched if it is empty.
+	Namespace string
+	// logger to logger stuff
+	logger *logrus.Logger
+	// eventSourceClient is the Argo-Events event source resource client
+	eventSourceClient eventsourceclientset.Interface
+	// gatewayClient is the Argo-Events gateway resource client
+	gatewayClient gatewayclientset.Interface
+	// event source informer and queue
+	informer cache.SharedIndexInformer
+	queue    workqueue.RateLimitingInterface
+	// gatewayInformer tracks the gateways that refer to the event sources
+	gatewayInformer cache.SharedIndexInformer
+}
+
+// NewEventSourceController creates a new controller
+func NewEventSourceController(rest *rest.Config, namespace string) *Controller {
+	rateLimiter := workqueue.NewItemExponentialFailureRateLimiter(rateLimiterBaseDelay, rateLimiterMaxDelay)
+	return &Controller{
+		Namespace:         namespace,
+		logger:            common.NewArgoEventsLogger(),
+		eventSourceClient: eventsourceclientset.NewForConfigOrDie(rest),
+		gatewayClient:     gatewayclientset.NewForConfigOrDie(rest),
+		queue:             workqueue.NewRateLimitingQueue(rateLimiter),
+	}
+}
+
+// processNextItem processes an event source resource on the controller's queue
+func (c *Controller) processNextItem() bool {
+	// Wait until there is a new item in the queue
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	controllerscommon.RecordQueueDepth(controllerName, c.queue.Len())
+
+	obj, exists, err := c.informer.GetIndexer().GetByKey(key.(string))
+	if err != nil {
+		c.logger.WithField(common.LabelResourceName, key.(string)).WithError(err).Warnln("failed to get event source from informer index")
+		return true
+	}
+
+	if !exists {
+		// this happens after the event source was deleted, or when a gateway refers to an event source that doesn't exist
+		return true
+	}
+
+	eventSource, ok := obj.(*v1alpha1.EventSource)
+	if !ok {
+		c.logger.WithField(common.LabelResourceName, key.(string)).Warnln("key in index is not an event source")
+		return true
+	}
+
+	ctx := newEventSourceContext(eventSource, c)
+
+	start := time.Now()
+	err = ctx.operate()
+	controllerscommon.RecordReconcile(controllerName, start, err)
+
+	err = c.handleErr(err, key)
+	if err != nil {
+		ctx.logger.WithError(err).Errorln("controller failed to handle error")
+	}
+
+	return true
+}
+
+// handleErr checks if an error happened and make sure we will retry later
+// returns an error if unable to handle the error
+func (c *Controller) handleErr(err error, key interface{}) error {
+	if err == nil {
+		// Forget about the #AddRateLimited history of key on every successful sync
+		// Ensure future updates for this key are not delayed because of outdated error history
+		c.queue.Forget(key)
+		return nil
+	}
+
+	if c.queue.NumRequeues(key) < 20 {
+		c.logger.WithField(common.LabelResourceName, key.(string)).WithError(err).Errorln("error syncing event source")
+
+		// Re-enqueue the key rate limited. This key will be processed later again.
+		c.queue.AddRateLimited(key)
+		return nil
+	}
+	return errors.New("exceeded max requeues")
+}
+
+// Run processes the event source resources on the controller's queue
+func (c *Controller) Run(ctx context.Context, threads int) {
+	defer c.queue.ShutDown()
+
+	c.logger.WithFields(
+		map[string]interface{}{
+			common.LabelNamespace: c.Namespace,
+			common.LabelVersion:   base.GetVersion().Version,
+		}).Infoln("starting controller")
+
+	c.informer = c.newEventSourceInformer()
+	c.gatewayInformer = c.newGatewayInformer()
+	go c.informer.Run(ctx.Done())
+	go c.gatewayInformer.Run(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), c.informer.HasSynced, c.gatewayInformer.HasSynced) {
+		c.logger.Errorln("timed out waiting for the caches to sync for event sources and gateways")
+		return
+	}
+
+	for i := 0; i < threads; i++ {
+		go wait.Until(c.runWorker, time.Second, ctx.Done())
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextItem() {
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	fakeeventsource "github.com/argoproj/argo-events/pkg/client/eventsources/clientset/versioned/fake"
+	fakegateway "github.com/argoproj/argo-events/pkg/client/gateway/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func newController() *Controller {
+	controller := &Controller{
+		Namespace:         common.DefaultControllerNamespace,
+		eventSourceClient: fakeeventsource.NewSimpleClientset(),
+		gatewayClient:     fakegateway.NewSimpleClientset(),
+		queue:             workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		logger:            common.NewArgoEventsLogger(),
+	}
+	controller.informer = controller.newEventSourceInformer()
+	controller.gatewayInformer = controller.newGatewayInformer()
+	return controller
+}
+
+func TestEventSourceController_ProcessNextItem(t *testing.T) {
+	controller := newController()
+	eventSource := &v1alpha1.EventSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fake-event-source",
+			Namespace: common.DefaultControllerNamespace,
+		},
+		Spec: &v1alpha1.EventSourceSpec{},
+	}
+	err := controller.informer.GetIndexer().Add(eventSource)
+	assert.Nil(t, err)
+
+	controller.queue.Add("fake-event-source")
+	res := controller.processNextItem()
+	assert.Equal(t, res, true)
+
+	controller.queue.ShutDown()
+	res = controller.processNextItem()
+	assert.Equal(t, res, false)
+}
+
+func TestEventSourceController_HandleErr(t *testing.T) {
+	controller := newController()
+	controller.queue.Add("hi")
+	var err error
+	for i := 0; i < 21; i++ {
+		err = controller.handleErr(fmt.Errorf("real error"), "bye")
+	}
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "exceeded max requeues")
+}
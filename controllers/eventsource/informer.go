@@ -0,0 +1,112 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	gatewayv1alpha1 "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	eventsourceinformers "github.com/argoproj/argo-events/pkg/client/eventsources/informers/externalversions"
+	gatewayinformers "github.com/argoproj/argo-events/pkg/client/gateway/informers/externalversions"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newEventSourceInformer returns the informer that adds new event sources to the controller's queue based on
+// Add, Update, and Delete Event Handlers for the event source resources
+func (c *Controller) newEventSourceInformer() cache.SharedIndexInformer {
+	eventSourceInformerFactory := eventsourceinformers.NewSharedInformerFactoryWithOptions(
+		c.eventSourceClient,
+		eventSourceResyncPeriod,
+		eventsourceinformers.WithNamespace(c.Namespace),
+	)
+	informer := eventSourceInformerFactory.Argoproj().V1alpha1().EventSources().Informer()
+	informer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				key, err := cache.MetaNamespaceKeyFunc(obj)
+				if err == nil {
+					c.queue.Add(key)
+				}
+			},
+			UpdateFunc: func(old, new interface{}) {
+				key, err := cache.MetaNamespaceKeyFunc(new)
+				if err == nil {
+					c.queue.Add(key)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+				if err == nil {
+					c.queue.Add(key)
+				}
+			},
+		},
+	)
+	return informer
+}
+
+// newGatewayInformer returns the informer that adds the event sources referred by gateways to the controller's queue
+// whenever a gateway is added, updated or deleted, so the gateways listed in the event source status stay current
+func (c *Controller) newGatewayInformer() cache.SharedIndexInformer {
+	gatewayInformerFactory := gatewayinformers.NewSharedInformerFactoryWithOptions(
+		c.gatewayClient,
+		eventSourceResyncPeriod,
+		gatewayinformers.WithNamespace(c.Namespace),
+	)
+	informer := gatewayInformerFactory.Argoproj().V1alpha1().Gateways().Informer()
+	informer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueEventSourceRef(obj)
+			},
+			UpdateFunc: func(old, new interface{}) {
+				c.enqueueEventSourceRef(old)
+				c.enqueueEventSourceRef(new)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				c.enqueueEventSourceRef(obj)
+			},
+		},
+	)
+	return informer
+}
+
+// enqueueEventSourceRef adds the event source referred by the gateway to the controller's queue
+func (c *Controller) enqueueEventSourceRef(obj interface{}) {
+	gateway, ok := obj.(*gatewayv1alpha1.Gateway)
+	if !ok {
+		return
+	}
+	if key := eventSourceKey(gateway); key != "" {
+		c.queue.Add(key)
+	}
+}
+
+// eventSourceKey returns the key of the event source referred by the gateway. The event source lives in
+// the namespace of the gateway unless the reference says otherwise.
+func eventSourceKey(gateway *gatewayv1alpha1.Gateway) string {
+	ref := gateway.Spec.EventSourceRef
+	if ref == nil || ref.Name == "" {
+		return ""
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = gateway.Namespace
+	}
+	return namespace + "/" + ref.Name
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	"sort"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	gatewayv1alpha1 "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the context of an operation in the controller.
+// the controller creates this context each time it picks an event source off its queue.
+type eventSourceContext struct {
+	// eventSource is the controller object
+	eventSource *v1alpha1.EventSource
+	// logger is the logger for an event source
+	logger *logrus.Logger
+	// reference to the controller
+	controller *Controller
+}
+
+// newEventSourceContext creates and initializes a new eventSourceContext object
+func newEventSourceContext(eventSource *v1alpha1.EventSource, controller *Controller) *eventSourceContext {
+	eventSource = eventSource.DeepCopy()
+	return &eventSourceContext{
+		eventSource: eventSource,
+		logger: common.NewArgoEventsLogger().WithFields(
+			map[string]interface{}{
+				common.LabelResourceName: eventSource.Name,
+				common.LabelNamespace:    eventSource.Namespace,
+			}).Logger,
+		controller: controller,
+	}
+}
+
+// operate validates the entries of the event source, looks up the gateways that refer to it and
+// persists the status of the event source if it changed.
+func (ctx *eventSourceContext) operate() error {
+	status := ctx.eventSource.Status.DeepCopy()
+	if status.CreatedAt.IsZero() {
+		status.CreatedAt = ctx.eventSource.CreationTimestamp
+	}
+
+	results, err := ValidateEntries(ctx.eventSource)
+	if err != nil {
+		ctx.logger.WithError(err).Errorln("event source is invalid")
+		status.Message = err.Error()
+		status.Conditions = nil
+	} else {
+		status.Message = ""
+		status.Conditions = ctx.conditions(results)
+	}
+	status.Gateways = ctx.gateways()
+
+	if equality.Semantic.DeepEqual(*status, ctx.eventSource.Status) {
+		return nil
+	}
+
+	ctx.eventSource.Status = *status
+	eventSource, err := ctx.controller.eventSourceClient.ArgoprojV1alpha1().EventSources(ctx.eventSource.Namespace).Update(ctx.eventSource)
+	if err != nil {
+		ctx.logger.WithError(err).Errorln("failed to persist the event source status")
+		return err
+	}
+	ctx.eventSource = eventSource
+	ctx.logger.Infoln("event source status updated successfully")
+	return nil
+}
+
+// conditions turns the validation results of the entries into conditions sorted by the entry names.
+// The transition time of an entry is kept as long as its validity doesn't change.
+func (ctx *eventSourceContext) conditions(results map[string]error) []v1alpha1.EventSourceCondition {
+	previous := make(map[string]v1alpha1.EventSourceCondition, len(ctx.eventSource.Status.Conditions))
+	for _, condition := range ctx.eventSource.Status.Conditions {
+		previous[condition.Name] = condition
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := metav1.Now()
+	conditions := make([]v1alpha1.EventSourceCondition, 0, len(names))
+	for _, name := range names {
+		condition := v1alpha1.EventSourceCondition{
+			Name:               name,
+			Valid:              results[name] == nil,
+			LastTransitionTime: now,
+		}
+		if results[name] != nil {
+			condition.Message = results[name].Error()
+			ctx.logger.WithField(common.LabelEventSource, name).WithError(results[name]).Warnln("event source entry is invalid")
+		}
+		if prev, ok := previous[name]; ok && prev.Valid == condition.Valid {
+			condition.LastTransitionTime = prev.LastTransitionTime
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
+
+// gateways returns the gateways that refer to the event source, sorted by namespace and name
+func (ctx *eventSourceContext) gateways() []v1alpha1.GatewayReference {
+	key := ctx.eventSource.Namespace + "/" + ctx.eventSource.Name
+	var references []v1alpha1.GatewayReference
+	for _, obj := range ctx.controller.gatewayInformer.GetIndexer().List() {
+		gateway, ok := obj.(*gatewayv1alpha1.Gateway)
+		if !ok || eventSourceKey(gateway) != key {
+			continue
+		}
+		references = append(references, v1alpha1.GatewayReference{
+			Name:      gateway.Name,
+			Namespace: gateway.Namespace,
+		})
+	}
+	sort.Slice(references, func(i, j int) bool {
+		if references[i].Namespace != references[j].Namespace {
+			return references[i].Namespace < references[j].Namespace
+		}
+		return references[i].Name < references[j].Name
+	})
+	return references
+}
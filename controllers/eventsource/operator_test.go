@@ -0,0 +1,124 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	gatewayv1alpha1 "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newEventSource() *v1alpha1.EventSource {
+	return &v1alpha1.EventSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mqtt-event-source",
+			Namespace: common.DefaultControllerNamespace,
+		},
+		Spec: &v1alpha1.EventSourceSpec{
+			Type: apicommon.MQTTEvent,
+			MQTT: map[string]v1alpha1.MQTTEventSource{
+				"valid": {
+					URL:      "tcp://mqtt.argo-events:1883",
+					Topic:    "foo",
+					ClientId: "1234",
+				},
+				"invalid": {
+					URL: "tcp://mqtt.argo-events:1883",
+				},
+			},
+		},
+	}
+}
+
+func newGateway(name, namespace string, ref *gatewayv1alpha1.EventSourceRef) *gatewayv1alpha1.Gateway {
+	return &gatewayv1alpha1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: gatewayv1alpha1.GatewaySpec{
+			EventSourceRef: ref,
+		},
+	}
+}
+
+func TestOperator_Operate(t *testing.T) {
+	controller := newController()
+	eventSource, err := controller.eventSourceClient.ArgoprojV1alpha1().EventSources(common.DefaultControllerNamespace).Create(newEventSource())
+	assert.Nil(t, err)
+
+	for _, gateway := range []*gatewayv1alpha1.Gateway{
+		newGateway("mqtt-gateway", common.DefaultControllerNamespace, &gatewayv1alpha1.EventSourceRef{Name: "mqtt-event-source"}),
+		newGateway("other-mqtt-gateway", "other", &gatewayv1alpha1.EventSourceRef{Name: "mqtt-event-source", Namespace: common.DefaultControllerNamespace}),
+		newGateway("webhook-gateway", common.DefaultControllerNamespace, &gatewayv1alpha1.EventSourceRef{Name: "webhook-event-source"}),
+		newGateway("no-ref-gateway", common.DefaultControllerNamespace, nil),
+	} {
+		err = controller.gatewayInformer.GetIndexer().Add(gateway)
+		assert.Nil(t, err)
+	}
+
+	ctx := newEventSourceContext(eventSource, controller)
+	err = ctx.operate()
+	assert.Nil(t, err)
+
+	eventSource, err = controller.eventSourceClient.ArgoprojV1alpha1().EventSources(common.DefaultControllerNamespace).Get(eventSource.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	status := eventSource.Status
+	assert.Equal(t, "", status.Message)
+	assert.Equal(t, 2, len(status.Conditions))
+	assert.Equal(t, "invalid", status.Conditions[0].Name)
+	assert.False(t, status.Conditions[0].Valid)
+	assert.Equal(t, "topic must be specified", status.Conditions[0].Message)
+	assert.Equal(t, "valid", status.Conditions[1].Name)
+	assert.True(t, status.Conditions[1].Valid)
+	assert.Equal(t, "", status.Conditions[1].Message)
+	assert.Equal(t, []v1alpha1.GatewayReference{
+		{Name: "mqtt-gateway", Namespace: common.DefaultControllerNamespace},
+		{Name: "other-mqtt-gateway", Namespace: "other"},
+	}, status.Gateways)
+
+	// the transition time only changes when the validity of the entry changes
+	transitionTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	eventSource.Status.Conditions[0].LastTransitionTime = transitionTime
+	eventSource.Status.Conditions[1].LastTransitionTime = transitionTime
+	mqtt := eventSource.Spec.MQTT["invalid"]
+	mqtt.Topic = "bar"
+	mqtt.ClientId = "5678"
+	eventSource.Spec.MQTT["invalid"] = mqtt
+
+	ctx = newEventSourceContext(eventSource, controller)
+	err = ctx.operate()
+	assert.Nil(t, err)
+	assert.True(t, ctx.eventSource.Status.Conditions[0].Valid)
+	assert.NotEqual(t, transitionTime, ctx.eventSource.Status.Conditions[0].LastTransitionTime)
+	assert.Equal(t, transitionTime, ctx.eventSource.Status.Conditions[1].LastTransitionTime)
+
+	// an event source with an unknown type
+	eventSource = ctx.eventSource
+	eventSource.Spec.Type = "unknown"
+	ctx = newEventSourceContext(eventSource, controller)
+	err = ctx.operate()
+	assert.Nil(t, err)
+	assert.Equal(t, "unknown event source type unknown", ctx.eventSource.Status.Message)
+	assert.Nil(t, ctx.eventSource.Status.Conditions)
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	"context"
+	"sort"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/gateways"
+	"github.com/argoproj/argo-events/gateways/server/amqp"
+	snsserver "github.com/argoproj/argo-events/gateways/server/aws-sns"
+	sqsserver "github.com/argoproj/argo-events/gateways/server/aws-sqs"
+	"github.com/argoproj/argo-events/gateways/server/calendar"
+	"github.com/argoproj/argo-events/gateways/server/file"
+	pubsubserver "github.com/argoproj/argo-events/gateways/server/gcp-pubsub"
+	"github.com/argoproj/argo-events/gateways/server/github"
+	"github.com/argoproj/argo-events/gateways/server/gitlab"
+	"github.com/argoproj/argo-events/gateways/server/hdfs"
+	"github.com/argoproj/argo-events/gateways/server/kafka"
+	"github.com/argoproj/argo-events/gateways/server/minio"
+	"github.com/argoproj/argo-events/gateways/server/mqtt"
+	"github.com/argoproj/argo-events/gateways/server/nats"
+	"github.com/argoproj/argo-events/gateways/server/resource"
+	"github.com/argoproj/argo-events/gateways/server/slack"
+	"github.com/argoproj/argo-events/gateways/server/storagegrid"
+	"github.com/argoproj/argo-events/gateways/server/webhook"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// validator validates a single entry of an event source
+type validator interface {
+	ValidateEventSource(ctx context.Context, eventSource *gateways.EventSource) (*gateways.ValidEventSource, error)
+}
+
+// validators hold the event source validation of the gateway server of each event source type
+var validators = func() map[apicommon.EventSourceType]validator {
+	logger := common.NewArgoEventsLogger()
+	return map[apicommon.EventSourceType]validator{
+		apicommon.MinioEvent:       &minio.EventListener{Logger: logger},
+		apicommon.CalendarEvent:    &calendar.EventListener{Logger: logger},
+		apicommon.FileEvent:        &file.EventListener{Logger: logger},
+		apicommon.ResourceEvent:    &resource.EventListener{Logger: logger},
+		apicommon.WebhookEvent:     &webhook.EventListener{Logger: logger},
+		apicommon.AMQPEvent:        &amqp.EventListener{Logger: logger},
+		apicommon.KafkaEvent:       &kafka.EventListener{Logger: logger},
+		apicommon.MQTTEvent:        &mqtt.EventListener{Logger: logger},
+		apicommon.NATSEvent:        &nats.EventListener{Logger: logger},
+		apicommon.SNSEvent:         &snsserver.EventListener{Logger: logger},
+		apicommon.SQSEvent:         &sqsserver.EventListener{Logger: logger},
+		apicommon.PubSubEvent:      &pubsubserver.EventListener{Logger: logger},
+		apicommon.GitHubEvent:      &github.EventListener{Logger: logger},
+		apicommon.GitLabEvent:      &gitlab.EventListener{Logger: logger},
+		apicommon.HDFSEvent:        &hdfs.EventListener{Logger: logger},
+		apicommon.SlackEvent:       &slack.EventListener{Logger: logger},
+		apicommon.StorageGridEvent: &storagegrid.EventListener{Logger: logger},
+	}
+}()
+
+// ValidateEventSource validates the event source resource and each of its entries.
+func ValidateEventSource(eventSource *v1alpha1.EventSource) error {
+	results, err := ValidateEntries(eventSource)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if results[name] != nil {
+			return errors.Errorf("event source %s is invalid. err: %v", name, results[name])
+		}
+	}
+	return nil
+}
+
+// ValidateEntries validates each entry of the event source using the validation of the gateway server
+// of the event source type. The result maps the name of each entry to its validation error, nil if the entry is valid.
+func ValidateEntries(eventSource *v1alpha1.EventSource) (map[string]error, error) {
+	if err := v1alpha1.ValidateEventSource(eventSource); err != nil {
+		return nil, err
+	}
+	if eventSource.Spec.Type == "" {
+		return nil, errors.New("event source type is not specified")
+	}
+	validator, ok := validators[eventSource.Spec.Type]
+	if !ok {
+		return nil, errors.Errorf("unknown event source type %s", eventSource.Spec.Type)
+	}
+	entries, err := Entries(eventSource.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.Errorf("no %s event sources are specified", eventSource.Spec.Type)
+	}
+
+	results := make(map[string]error, len(entries))
+	for name, value := range entries {
+		results[name] = validateEntry(validator, eventSource.Spec.Type, name, value)
+	}
+	return results, nil
+}
+
+// validateEntry marshals the entry the way the gateway client does and hands it to the validator
+func validateEntry(validator validator, eventSourceType apicommon.EventSourceType, name string, value interface{}) error {
+	body, err := yaml.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the event source")
+	}
+	valid, err := validator.ValidateEventSource(context.Background(), &gateways.EventSource{
+		Id:    common.Hasher(name + string(body)),
+		Name:  name,
+		Value: body,
+		Type:  string(eventSourceType),
+	})
+	if err != nil {
+		return err
+	}
+	if !valid.IsValid {
+		return errors.New(valid.Reason)
+	}
+	return nil
+}
+
+// Entries returns the entries of the event source keyed by their names, for the type of the event source.
+func Entries(spec *v1alpha1.EventSourceSpec) (map[string]interface{}, error) {
+	entries := make(map[string]interface{})
+	switch spec.Type {
+	case apicommon.MinioEvent:
+		for name, value := range spec.Minio {
+			entries[name] = value
+		}
+	case apicommon.CalendarEvent:
+		for name, value := range spec.Calendar {
+			entries[name] = value
+		}
+	case apicommon.FileEvent:
+		for name, value := range spec.File {
+			entries[name] = value
+		}
+	case apicommon.ResourceEvent:
+		for name, value := range spec.Resource {
+			entries[name] = value
+		}
+	case apicommon.WebhookEvent:
+		for name, value := range spec.Webhook {
+			entries[name] = value
+		}
+	case apicommon.AMQPEvent:
+		for name, value := range spec.AMQP {
+			entries[name] = value
+		}
+	case apicommon.KafkaEvent:
+		for name, value := range spec.Kafka {
+			entries[name] = value
+		}
+	case apicommon.MQTTEvent:
+		for name, value := range spec.MQTT {
+			entries[name] = value
+		}
+	case apicommon.NATSEvent:
+		for name, value := range spec.NATS {
+			entries[name] = value
+		}
+	case apicommon.SNSEvent:
+		for name, value := range spec.SNS {
+			entries[name] = value
+		}
+	case apicommon.SQSEvent:
+		for name, value := range spec.SQS {
+			entries[name] = value
+		}
+	case apicommon.PubSubEvent:
+		for name, value := range spec.PubSub {
+			entries[name] = value
+		}
+	case apicommon.GitHubEvent:
+		for name, value := range spec.Github {
+			entries[name] = value
+		}
+	case apicommon.GitLabEvent:
+		for name, value := range spec.Gitlab {
+			entries[name] = value
+		}
+	case apicommon.HDFSEvent:
+		for name, value := range spec.HDFS {
+			entries[name] = value
+		}
+	case apicommon.SlackEvent:
+		for name, value := range spec.Slack {
+			entries[name] = value
+		}
+	case apicommon.StorageGridEvent:
+		for name, value := range spec.StorageGrid {
+			entries[name] = value
+		}
+	default:
+		return nil, errors.Errorf("unknown event source type %s", spec.Type)
+	}
+	return entries, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsource
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateEventSource(t *testing.T) {
+	dir := "../../examples/event-sources"
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", dir, file.Name()))
+		assert.Nil(t, err)
+		var eventSource *v1alpha1.EventSource
+		err = yaml.Unmarshal(content, &eventSource)
+		assert.Nil(t, err)
+		err = ValidateEventSource(eventSource)
+		assert.Nil(t, err, file.Name())
+	}
+}
+
+func TestValidateEntries(t *testing.T) {
+	eventSource := &v1alpha1.EventSource{
+		Spec: &v1alpha1.EventSourceSpec{
+			Type: apicommon.MQTTEvent,
+			MQTT: map[string]v1alpha1.MQTTEventSource{
+				"valid": {
+					URL:      "tcp://mqtt.argo-events:1883",
+					Topic:    "foo",
+					ClientId: "1234",
+				},
+				"invalid": {
+					URL: "tcp://mqtt.argo-events:1883",
+				},
+			},
+		},
+	}
+
+	results, err := ValidateEntries(eventSource)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.Nil(t, results["valid"])
+	assert.NotNil(t, results["invalid"])
+
+	err = ValidateEventSource(eventSource)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "event source invalid is invalid")
+
+	eventSource.Spec.Type = "unknown"
+	_, err = ValidateEntries(eventSource)
+	assert.NotNil(t, err)
+
+	eventSource.Spec.Type = apicommon.NATSEvent
+	_, err = ValidateEntries(eventSource)
+	assert.NotNil(t, err)
+
+	_, err = ValidateEntries(&v1alpha1.EventSource{})
+	assert.NotNil(t, err)
+}
@@ -51,6 +51,9 @@ func main() {
 		panic(err)
 	}
 
+	// expose controller metrics
+	go common.StartMetricsServer(common.GetMetricsPort(), common.NewArgoEventsLogger())
+
 	go controller.Run(context.Background(), 1)
 	select {}
 }
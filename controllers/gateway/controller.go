@@ -24,6 +24,7 @@ import (
 
 	base "github.com/argoproj/argo-events"
 	"github.com/argoproj/argo-events/common"
+	controllerscommon "github.com/argoproj/argo-events/controllers/common"
 	"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
 	clientset "github.com/argoproj/argo-events/pkg/client/gateway/clientset/versioned"
 	"github.com/sirupsen/logrus"
@@ -39,6 +40,8 @@ const (
 	gatewayResyncPeriod  = 20 * time.Minute
 	rateLimiterBaseDelay = 5 * time.Second
 	rateLimiterMaxDelay  = 1000 * time.Second
+	// controllerName is the name of the controller in the metrics
+	controllerName = "gateway-controller"
 )
 
 // ControllerConfig contain the configuration settings for the controller
@@ -95,6 +98,8 @@ func (c *Controller) processNextItem() bool {
 	}
 	defer c.queue.Done(key)
 
+	controllerscommon.RecordQueueDepth(controllerName, c.queue.Len())
+
 	obj, exists, err := c.informer.GetIndexer().GetByKey(key.(string))
 	if err != nil {
 		c.logger.WithField(common.LabelResourceName, key.(string)).WithError(err).Warnln("failed to get gateway from informer index")
@@ -114,7 +119,9 @@ func (c *Controller) processNextItem() bool {
 
 	ctx := newGatewayContext(gw, c)
 
+	start := time.Now()
 	err = ctx.operate()
+	controllerscommon.RecordReconcile(controllerName, start, err)
 	if err != nil {
 		if err := common.GenerateK8sEvent(c.k8sClient,
 			fmt.Sprintf("controller failed to operate on gateway %s", gw.Name),
@@ -17,8 +17,11 @@ limitations under the License.
 package gateway
 
 import (
+	"path"
+
 	"github.com/argoproj/argo-events/common"
 	controllerscommon "github.com/argoproj/argo-events/controllers/common"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
 	"github.com/pkg/errors"
 	appv1 "k8s.io/api/apps/v1"
@@ -95,6 +98,26 @@ func (ctx *gatewayContext) buildDeploymentResource() (*appv1.Deployment, error)
 		},
 	}
 
+	if ctx.gateway.Spec.EventProtocol != nil && ctx.gateway.Spec.EventProtocol.Type == apicommon.HTTP {
+		queueDir := common.DefaultGatewayQueueDir
+		queueVolume := corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		}
+		if queue := ctx.gateway.Spec.Queue; queue != nil {
+			if queue.Dir != "" {
+				queueDir = queue.Dir
+			}
+			if queue.Volume != nil {
+				queueVolume = *queue.Volume.DeepCopy()
+			}
+		}
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  common.EnvVarGatewayQueueDir,
+			Value: queueDir,
+		})
+		mountQueueVolume(&deployment.Spec.Template.Spec, queueDir, queueVolume)
+	}
+
 	for i, container := range deployment.Spec.Template.Spec.Containers {
 		container.Env = append(container.Env, envVars...)
 		deployment.Spec.Template.Spec.Containers[i] = container
@@ -107,6 +130,30 @@ func (ctx *gatewayContext) buildDeploymentResource() (*appv1.Deployment, error)
 	return deployment, nil
 }
 
+// mountQueueVolume mounts the volume of the event queues at given directory in the containers of the pod,
+// unless the pod template already mounts a volume there
+func mountQueueVolume(podSpec *corev1.PodSpec, dir string, source corev1.VolumeSource) {
+	for _, container := range podSpec.Containers {
+		for _, mount := range container.VolumeMounts {
+			if path.Clean(mount.MountPath) == path.Clean(dir) {
+				return
+			}
+		}
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         common.GatewayQueueVolumeName,
+		VolumeSource: source,
+	})
+	for i, container := range podSpec.Containers {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      common.GatewayQueueVolumeName,
+			MountPath: dir,
+		})
+		podSpec.Containers[i] = container
+	}
+}
+
 // createGatewayResources creates gateway deployment and service
 func (ctx *gatewayContext) createGatewayResources() error {
 	if ctx.gateway.Status.Resources == nil {
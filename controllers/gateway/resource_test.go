@@ -148,7 +148,23 @@ func TestResource_BuildDeploymentResource(t *testing.T) {
 		assert.Equal(t, container.Env[3].Value, ctx.controller.Config.InstanceID)
 		assert.Equal(t, container.Env[4].Name, common.EnvVarGatewayServerPort)
 		assert.Equal(t, container.Env[4].Value, ctx.gateway.Spec.ProcessorPort)
+		assert.Equal(t, container.Env[5].Name, common.EnvVarGatewayQueueDir)
+		assert.Equal(t, container.Env[5].Value, common.DefaultGatewayQueueDir)
+		assert.Equal(t, []corev1.VolumeMount{
+			{
+				Name:      common.GatewayQueueVolumeName,
+				MountPath: common.DefaultGatewayQueueDir,
+			},
+		}, container.VolumeMounts)
 	}
+	assert.Equal(t, []corev1.Volume{
+		{
+			Name: common.GatewayQueueVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}, deployment.Spec.Template.Spec.Volumes)
 
 	newDeployment, err := controller.k8sClient.AppsV1().Deployments(deployment.Namespace).Create(deployment)
 	assert.Nil(t, err)
@@ -157,6 +173,41 @@ func TestResource_BuildDeploymentResource(t *testing.T) {
 	assert.NotNil(t, newDeployment.Annotations[common.AnnotationResourceSpecHash])
 }
 
+func TestResource_BuildDeploymentResourceQueue(t *testing.T) {
+	controller := newController()
+	ctx := newGatewayContext(gatewayObj, controller)
+	ctx.gateway.Spec.Queue = &v1alpha1.EventQueue{
+		Dir: "/var/lib/argo-events/queue",
+		Volume: &corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: "webhook-gateway-queue",
+			},
+		},
+	}
+
+	deployment, err := ctx.buildDeploymentResource()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(deployment.Spec.Template.Spec.Volumes))
+	assert.Equal(t, "webhook-gateway-queue", deployment.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		assert.Equal(t, "/var/lib/argo-events/queue", container.Env[5].Value)
+		assert.Equal(t, "/var/lib/argo-events/queue", container.VolumeMounts[0].MountPath)
+	}
+
+	// the pod template already mounts a volume at the queue directory
+	ctx.gateway.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
+		{
+			Name:      "queue",
+			MountPath: "/var/lib/argo-events/queue/",
+		},
+	}
+	deployment, err = ctx.buildDeploymentResource()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(deployment.Spec.Template.Spec.Volumes))
+	assert.Equal(t, 1, len(deployment.Spec.Template.Spec.Containers[0].VolumeMounts))
+	assert.Equal(t, 0, len(deployment.Spec.Template.Spec.Containers[1].VolumeMounts))
+}
+
 func TestResource_CreateGatewayResource(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -246,7 +297,7 @@ func TestResource_CreateGatewayResource(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			controller := newController()
-			ctx := newGatewayContext(gatewayObj.DeepCopy(), controller)
+			ctx := newGatewayContext(gatewayObj, controller)
 			test.updateFunc(ctx)
 			err := ctx.createGatewayResources()
 			assert.Nil(t, err)
@@ -257,7 +308,7 @@ func TestResource_CreateGatewayResource(t *testing.T) {
 
 func TestResource_UpdateGatewayResource(t *testing.T) {
 	controller := newController()
-	ctx := newGatewayContext(gatewayObj.DeepCopy(), controller)
+	ctx := newGatewayContext(gatewayObj, controller)
 	err := ctx.createGatewayResources()
 	assert.Nil(t, err)
 
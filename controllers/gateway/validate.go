@@ -17,6 +17,8 @@ limitations under the License.
 package gateway
 
 import (
+	"path"
+
 	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
 	"github.com/pkg/errors"
@@ -37,6 +39,9 @@ func Validate(gatewayObj *v1alpha1.Gateway) error {
 	if gatewayObj.Spec.ProcessorPort == "" {
 		return errors.New("gateway processor port is not specified")
 	}
+	if gatewayObj.Spec.EventProtocol == nil {
+		return errors.New("gateway event protocol is not specified")
+	}
 
 	switch gatewayObj.Spec.EventProtocol.Type {
 	case apicommon.HTTP:
@@ -62,5 +67,14 @@ func Validate(gatewayObj *v1alpha1.Gateway) error {
 	default:
 		return errors.New("unknown gateway type")
 	}
+
+	if queue := gatewayObj.Spec.Queue; queue != nil {
+		if queue.Dir != "" && !path.IsAbs(queue.Dir) {
+			return errors.New("event queue directory must be an absolute path")
+		}
+		if queue.MaxEvents < 0 {
+			return errors.New("event queue max events can't be negative")
+		}
+	}
 	return nil
 }
@@ -24,6 +24,7 @@ import (
 
 	base "github.com/argoproj/argo-events"
 	"github.com/argoproj/argo-events/common"
+	controllerscommon "github.com/argoproj/argo-events/controllers/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	clientset "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned"
@@ -40,6 +41,8 @@ const (
 	sensorResyncPeriod   = 20 * time.Minute
 	rateLimiterBaseDelay = 5 * time.Second
 	rateLimiterMaxDelay  = 1000 * time.Second
+	// controllerName is the name of the controller in the metrics
+	controllerName = "sensor-controller"
 )
 
 // ControllerConfig contain the configuration settings for the controller
@@ -96,6 +99,8 @@ func (controller *Controller) processNextItem() bool {
 	}
 	defer controller.queue.Done(key)
 
+	controllerscommon.RecordQueueDepth(controllerName, controller.queue.Len())
+
 	obj, exists, err := controller.informer.GetIndexer().GetByKey(key.(string))
 	if err != nil {
 		controller.logger.WithField(common.LabelSensorName, key.(string)).WithError(err).Warnln("failed to get sensor from informer index")
@@ -115,7 +120,9 @@ func (controller *Controller) processNextItem() bool {
 
 	ctx := newSensorContext(s, controller)
 
+	start := time.Now()
 	err = ctx.operate()
+	controllerscommon.RecordReconcile(controllerName, start, err)
 	if err != nil {
 		if err := common.GenerateK8sEvent(controller.k8sClient,
 			fmt.Sprintf("failed to operate on sensor %s", s.Name),
@@ -58,6 +58,20 @@ func (ctx *sensorContext) generateServiceSpec() *corev1.Service {
 	serviceSpec.ObjectMeta.Labels[common.LabelSensorName] = ctx.sensor.Name
 	serviceSpec.ObjectMeta.Labels[LabelControllerInstanceID] = ctx.controller.Config.InstanceID
 
+	// expose the replay endpoint next to the event port. The ports of a multi-port service must be named.
+	if replay := ctx.sensor.Spec.Replay; replay != nil {
+		port := replay.Port
+		if port == 0 {
+			port = common.SensorReplayPort
+		}
+		serviceSpec.Spec.Ports[0].Name = "events"
+		serviceSpec.Spec.Ports = append(serviceSpec.Spec.Ports, corev1.ServicePort{
+			Name:       "replay",
+			Port:       port,
+			TargetPort: intstr.FromInt(int(port)),
+		})
+	}
+
 	return serviceSpec
 }
 
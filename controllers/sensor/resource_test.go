@@ -84,6 +84,27 @@ func TestResource_BuildService(t *testing.T) {
 	assert.NotEmpty(t, service.Annotations[common.AnnotationResourceSpecHash])
 }
 
+func TestResource_BuildServiceWithReplay(t *testing.T) {
+	controller := getController()
+	sensorCopy := sensorObj.DeepCopy()
+	sensorCopy.Spec.Replay = &v1alpha1.ReplayServer{
+		Token: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "replay-token",
+			},
+			Key: "token",
+		},
+	}
+
+	opctx := newSensorContext(sensorCopy, controller)
+	service, err := opctx.serviceBuilder()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(service.Spec.Ports))
+	assert.Equal(t, "events", service.Spec.Ports[0].Name)
+	assert.Equal(t, "replay", service.Spec.Ports[1].Name)
+	assert.Equal(t, int32(common.SensorReplayPort), service.Spec.Ports[1].Port)
+}
+
 func TestResource_BuildServiceWithLabelsAnnotations(t *testing.T) {
 	controller := getController()
 	sensorCopy := sensorObj.DeepCopy()
@@ -18,11 +18,13 @@ package sensor
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Knetic/govaluate"
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/templates"
 	pc "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 )
@@ -45,6 +47,9 @@ func ValidateSensor(s *v1alpha1.Sensor) error {
 	if len(s.Spec.Template.Spec.Containers) > 1 {
 		return fmt.Errorf("sensor pod specification can't have more than one container")
 	}
+	if s.Spec.EventProtocol == nil {
+		return fmt.Errorf("event protocol is not defined")
+	}
 	switch s.Spec.EventProtocol.Type {
 	case pc.HTTP:
 		if s.Spec.EventProtocol.Http.Port == "" {
@@ -85,6 +90,182 @@ func ValidateSensor(s *v1alpha1.Sensor) error {
 		}
 	}
 
+	if s.Spec.Correlation != nil {
+		if err := validateCorrelation(s.Spec.Correlation); err != nil {
+			return fmt.Errorf("sensor correlation is invalid. err: %+v", err)
+		}
+	}
+	for _, group := range s.Spec.DependencyGroups {
+		if group.Correlation != nil {
+			if err := validateCorrelation(group.Correlation); err != nil {
+				return fmt.Errorf("correlation of group %s is invalid. err: %+v", group.Name, err)
+			}
+			if group.Correlation.Sets != nil {
+				return fmt.Errorf("correlation of group %s can't track multiple sets of events", group.Name)
+			}
+		}
+	}
+
+	if s.Spec.TriggerWorkers < 0 {
+		return fmt.Errorf("number of trigger workers can't be negative")
+	}
+
+	if s.Spec.StateStore != nil {
+		if err := validateStateStore(s.Spec.StateStore); err != nil {
+			return fmt.Errorf("state store is invalid. err: %+v", err)
+		}
+	}
+
+	if s.Spec.DeadLetter != nil {
+		if err := validateDeadLetterSink(s.Spec.DeadLetter); err != nil {
+			return fmt.Errorf("dead-letter sink is invalid. err: %+v", err)
+		}
+	}
+
+	if s.Spec.Replay != nil {
+		if err := validateReplayServer(s); err != nil {
+			return fmt.Errorf("replay endpoint is invalid. err: %+v", err)
+		}
+	}
+
+	return nil
+}
+
+// validateReplayServer validates the admin endpoint that re-injects past events.
+// Its port must differ from the ports of the sensor server and the metrics server.
+func validateReplayServer(s *v1alpha1.Sensor) error {
+	replay := s.Spec.Replay
+	if replay.Port < 0 || replay.Port > 65535 {
+		return fmt.Errorf("port %d is out of range", replay.Port)
+	}
+	port := int(replay.Port)
+	if port == 0 {
+		port = common.SensorReplayPort
+	}
+	serverPort := common.SensorServerPort
+	if s.Spec.Port != nil {
+		serverPort = *s.Spec.Port
+	}
+	if port == serverPort {
+		return fmt.Errorf("port %d is used by the sensor server", port)
+	}
+	if strconv.Itoa(port) == sensorMetricsPort(s) {
+		return fmt.Errorf("port %d is used by the metrics server", port)
+	}
+	if replay.Token == nil {
+		return fmt.Errorf("token must be specified")
+	}
+	return nil
+}
+
+// sensorMetricsPort returns the port of the metrics server of the sensor, as set by the env of its container
+func sensorMetricsPort(s *v1alpha1.Sensor) string {
+	if s.Spec.Template != nil {
+		for _, container := range s.Spec.Template.Spec.Containers {
+			for _, env := range container.Env {
+				if env.Name == common.EnvVarMetricsPort {
+					return env.Value
+				}
+			}
+		}
+	}
+	return common.DefaultMetricsPort
+}
+
+// validateDeadLetterSink validates the destination of the dead-letter records
+func validateDeadLetterSink(sink *v1alpha1.DeadLetterSink) error {
+	sinks := 0
+	if sink.File != nil {
+		sinks++
+		if sink.File.Path == "" {
+			return fmt.Errorf("path of the file must be specified")
+		}
+	}
+	if sink.ConfigMap != nil {
+		sinks++
+		if sink.ConfigMap.MaxRecords < 0 {
+			return fmt.Errorf("max records can't be negative")
+		}
+	}
+	if sink.NATS != nil {
+		sinks++
+		if sink.NATS.URL == "" || sink.NATS.Subject == "" {
+			return fmt.Errorf("url and subject of nats must be specified")
+		}
+	}
+	if sink.HTTP != nil {
+		sinks++
+		if sink.HTTP.URL == "" {
+			return fmt.Errorf("url of the http endpoint must be specified")
+		}
+		if sink.HTTP.Timeout < 0 {
+			return fmt.Errorf("timeout of the http endpoint can't be negative")
+		}
+	}
+	if sinks != 1 {
+		return fmt.Errorf("exactly one of file, configMap, nats or http must be specified")
+	}
+	return nil
+}
+
+// validateStateStore validates the store of the events of the dependencies
+func validateStateStore(stateStore *v1alpha1.StateStore) error {
+	stores := 0
+	if stateStore.ConfigMap != nil {
+		stores++
+	}
+	if stateStore.BoltDB != nil {
+		stores++
+		if stateStore.BoltDB.Path == "" {
+			return fmt.Errorf("path of the boltdb database must be specified")
+		}
+	}
+	if stateStore.Redis != nil {
+		stores++
+		if stateStore.Redis.Addr == "" {
+			return fmt.Errorf("address of the redis server must be specified")
+		}
+		if stateStore.Redis.Password != nil && (stateStore.Redis.Password.Name == "" || stateStore.Redis.Password.Key == "") {
+			return fmt.Errorf("name and key of the redis password secret must be specified")
+		}
+	}
+	if stores != 1 {
+		return fmt.Errorf("exactly one of configMap, boltDB or redis must be specified")
+	}
+	return nil
+}
+
+// validateCorrelation validates the correlation of the events
+func validateCorrelation(correlation *v1alpha1.Correlation) error {
+	if correlation.Window == "" && correlation.Key == "" {
+		return fmt.Errorf("either window or key must be specified")
+	}
+	if correlation.Window != "" {
+		window, err := time.ParseDuration(correlation.Window)
+		if err != nil {
+			return fmt.Errorf("failed to parse the window. err: %+v", err)
+		}
+		if window <= 0 {
+			return fmt.Errorf("window must be positive")
+		}
+	}
+	if correlation.Sets != nil {
+		if correlation.Key == "" {
+			return fmt.Errorf("key must be specified to track multiple sets of events")
+		}
+		if correlation.Sets.MaxSets < 0 {
+			return fmt.Errorf("max sets can't be negative")
+		}
+		if correlation.Sets.TTL != "" {
+			ttl, err := time.ParseDuration(correlation.Sets.TTL)
+			if err != nil {
+				return fmt.Errorf("failed to parse the ttl of the sets. err: %+v", err)
+			}
+			if ttl <= 0 {
+				return fmt.Errorf("ttl of the sets must be positive")
+			}
+		}
+	}
 	return nil
 }
 
@@ -101,6 +282,15 @@ func validateTriggers(triggers []v1alpha1.Trigger) error {
 		if err := validateTriggerParameters(&trigger); err != nil {
 			return err
 		}
+		if trigger.Timeout != "" {
+			timeout, err := time.ParseDuration(trigger.Timeout)
+			if err != nil {
+				return fmt.Errorf("failed to parse the timeout of the trigger %s. err: %+v", trigger.Template.Name, err)
+			}
+			if timeout <= 0 {
+				return fmt.Errorf("timeout of the trigger %s must be positive", trigger.Template.Name)
+			}
+		}
 	}
 	return nil
 }
@@ -113,14 +303,160 @@ func validateTriggerTemplate(template *v1alpha1.TriggerTemplate) error {
 	if template.Name == "" {
 		return fmt.Errorf("trigger must define a name")
 	}
+	if template.Switch != nil && template.Switch.All != nil && template.Switch.Any != nil {
+		return fmt.Errorf("trigger condition can't have both any and all condition")
+	}
+	kinds := 0
+	if template.HTTP != nil {
+		kinds++
+		if err := validateHTTPTrigger(template.HTTP); err != nil {
+			return fmt.Errorf("trigger '%s' is invalid. err: %+v", template.Name, err)
+		}
+	}
+	if template.Kafka != nil {
+		kinds++
+		if err := validateKafkaTrigger(template.Kafka); err != nil {
+			return fmt.Errorf("trigger '%s' is invalid. err: %+v", template.Name, err)
+		}
+	}
+	if template.NATS != nil {
+		kinds++
+		if err := validateNATSTrigger(template.NATS); err != nil {
+			return fmt.Errorf("trigger '%s' is invalid. err: %+v", template.Name, err)
+		}
+	}
+	if template.AMQP != nil {
+		kinds++
+		if err := validateAMQPTrigger(template.AMQP); err != nil {
+			return fmt.Errorf("trigger '%s' is invalid. err: %+v", template.Name, err)
+		}
+	}
+	if template.Slack != nil {
+		kinds++
+		if err := validateSlackTrigger(template.Slack); err != nil {
+			return fmt.Errorf("trigger '%s' is invalid. err: %+v", template.Name, err)
+		}
+	}
+	resource := template.Source != nil || template.GroupVersionResource != nil
+	if resource {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("trigger '%s' must define exactly one of a resource, http, kafka, nats, amqp or slack", template.Name)
+	}
+	if !resource {
+		return nil
+	}
 	if template.Source == nil {
 		return fmt.Errorf("trigger '%s' does not contain an absolute action", template.Name)
 	}
 	if template.GroupVersionResource == nil {
 		return fmt.Errorf("must provide group, version and resource for the resource")
 	}
-	if template.Switch != nil && template.Switch.All != nil && template.Switch.Any != nil {
-		return fmt.Errorf("trigger condition can't have both any and all condition")
+	switch template.Operation {
+	case "", v1alpha1.Create, v1alpha1.Update, v1alpha1.Apply, v1alpha1.Delete, v1alpha1.MergePatch, v1alpha1.StrategicMergePatch:
+	case v1alpha1.JSONPatch:
+		if template.Patch == nil {
+			return fmt.Errorf("trigger '%s' must define a patch for the json-patch operation", template.Name)
+		}
+	default:
+		return fmt.Errorf("trigger '%s' has an unknown operation %s", template.Name, template.Operation)
+	}
+	return nil
+}
+
+// validateHTTPTrigger validates the http trigger
+func validateHTTPTrigger(trigger *v1alpha1.HTTPTrigger) error {
+	if trigger.URL == "" {
+		return fmt.Errorf("url can't be empty")
+	}
+	if trigger.BasicAuth != nil && (trigger.BasicAuth.Username == nil || trigger.BasicAuth.Password == nil) {
+		return fmt.Errorf("basic auth must refer to both username and password")
+	}
+	if trigger.Timeout < 0 {
+		return fmt.Errorf("timeout can't be negative")
+	}
+	for i, parameter := range trigger.Payload {
+		if err := validateTriggerParameter(&parameter); err != nil {
+			return fmt.Errorf("payload parameter index: %d. err: %+v", i, err)
+		}
+	}
+	return nil
+}
+
+// validateKafkaTrigger validates the kafka trigger
+func validateKafkaTrigger(trigger *v1alpha1.KafkaTrigger) error {
+	if len(trigger.Brokers) == 0 {
+		return fmt.Errorf("brokers can't be empty")
+	}
+	if trigger.Topic == "" {
+		return fmt.Errorf("topic can't be empty")
+	}
+	if trigger.Timeout < 0 {
+		return fmt.Errorf("timeout can't be negative")
+	}
+	if trigger.PartitioningKey != nil && trigger.PartitioningKey.Event == "" {
+		return fmt.Errorf("partitioning key must refer to an event dependency")
+	}
+	for i, parameter := range trigger.Payload {
+		if err := validateTriggerParameter(&parameter); err != nil {
+			return fmt.Errorf("payload parameter index: %d. err: %+v", i, err)
+		}
+	}
+	return nil
+}
+
+// validateNATSTrigger validates the nats trigger
+func validateNATSTrigger(trigger *v1alpha1.NATSTrigger) error {
+	if trigger.URL == "" {
+		return fmt.Errorf("url can't be empty")
+	}
+	if trigger.Subject == "" {
+		return fmt.Errorf("subject can't be empty")
+	}
+	for i, parameter := range trigger.Payload {
+		if err := validateTriggerParameter(&parameter); err != nil {
+			return fmt.Errorf("payload parameter index: %d. err: %+v", i, err)
+		}
+	}
+	return nil
+}
+
+// validateAMQPTrigger validates the amqp trigger
+func validateAMQPTrigger(trigger *v1alpha1.AMQPTrigger) error {
+	if trigger.URL == "" {
+		return fmt.Errorf("url can't be empty")
+	}
+	if trigger.ExchangeName == "" {
+		return fmt.Errorf("exchange name can't be empty")
+	}
+	if trigger.ExchangeType == "" {
+		return fmt.Errorf("exchange type can't be empty")
+	}
+	for i, parameter := range trigger.Payload {
+		if err := validateTriggerParameter(&parameter); err != nil {
+			return fmt.Errorf("payload parameter index: %d. err: %+v", i, err)
+		}
+	}
+	return nil
+}
+
+// validateSlackTrigger validates the slack trigger
+func validateSlackTrigger(trigger *v1alpha1.SlackTrigger) error {
+	if trigger.Token == nil {
+		return fmt.Errorf("token can't be empty")
+	}
+	if trigger.Channel == "" {
+		return fmt.Errorf("channel can't be empty")
+	}
+	if trigger.Text == "" && trigger.Blocks == "" {
+		return fmt.Errorf("either text or blocks must be specified")
+	}
+	if _, err := templates.Parse("text", trigger.Text); err != nil {
+		return fmt.Errorf("failed to parse the text template. err: %+v", err)
+	}
+	if _, err := templates.Parse("blocks", trigger.Blocks); err != nil {
+		return fmt.Errorf("failed to parse the blocks template. err: %+v", err)
 	}
 	return nil
 }
@@ -141,6 +477,11 @@ func validateTriggerParameters(trigger *v1alpha1.Trigger) error {
 			}
 		}
 	}
+	for i, parameter := range trigger.PatchParameters {
+		if err := validateTriggerParameter(&parameter); err != nil {
+			return fmt.Errorf("patch parameter index: %d. err: %+v", i, err)
+		}
+	}
 	return nil
 }
 
@@ -149,7 +490,20 @@ func validateTriggerParameter(parameter *v1alpha1.TriggerParameter) error {
 	if parameter.Src == nil {
 		return fmt.Errorf("parameter source can't be empty")
 	}
-	if parameter.Src.Event == "" {
+	if parameter.Src.Template != "" && parameter.Src.Expression != "" {
+		return fmt.Errorf("parameter source can't have both a template and an expression")
+	}
+	if parameter.Src.Template != "" {
+		if _, err := templates.Parse("parameter", parameter.Src.Template); err != nil {
+			return fmt.Errorf("failed to parse the parameter source template. err: %+v", err)
+		}
+	}
+	if parameter.Src.Expression != "" {
+		if _, err := templates.NewExpression(parameter.Src.Expression); err != nil {
+			return fmt.Errorf("failed to parse the parameter source expression. err: %+v", err)
+		}
+	}
+	if parameter.Src.Event == "" && parameter.Src.Template == "" && parameter.Src.Expression == "" {
 		return fmt.Errorf("parameter source event can't be empty")
 	}
 	if parameter.Dest == "" {
@@ -18,10 +18,13 @@ package sensor
 
 import (
 	"fmt"
+	"github.com/argoproj/argo-events/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	"github.com/ghodss/yaml"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 )
 
@@ -42,3 +45,72 @@ func TestValidateSensor(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+func TestValidateTriggerTemplate(t *testing.T) {
+	template := &v1alpha1.TriggerTemplate{
+		Name: "fake-trigger",
+		HTTP: &v1alpha1.HTTPTrigger{
+			URL: "http://fake.com",
+		},
+	}
+	assert.Nil(t, validateTriggerTemplate(template))
+
+	// a template can only define one kind of trigger
+	template.Kafka = &v1alpha1.KafkaTrigger{
+		Brokers: []string{"kafka:9092"},
+		Topic:   "fake-topic",
+	}
+	err := validateTriggerTemplate(template)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "exactly one")
+
+	template.Kafka = nil
+	template.GroupVersionResource = &metav1.GroupVersionResource{
+		Group:    "apps",
+		Version:  "v1",
+		Resource: "deployments",
+	}
+	assert.NotNil(t, validateTriggerTemplate(template))
+
+	template.HTTP = nil
+	template.GroupVersionResource = nil
+	assert.NotNil(t, validateTriggerTemplate(template))
+}
+
+func TestValidateReplayServer(t *testing.T) {
+	newSensor := func(port int32) *v1alpha1.Sensor {
+		return &v1alpha1.Sensor{
+			Spec: v1alpha1.SensorSpec{
+				Replay: &v1alpha1.ReplayServer{
+					Port:  port,
+					Token: &corev1.SecretKeySelector{Key: "token"},
+				},
+			},
+		}
+	}
+
+	assert.Nil(t, validateReplayServer(newSensor(0)))
+	assert.NotNil(t, validateReplayServer(newSensor(-1)))
+	assert.NotNil(t, validateReplayServer(newSensor(common.SensorServerPort)))
+	assert.NotNil(t, validateReplayServer(newSensor(9090)))
+
+	// the replay port is compared to the port the sensor server listens on
+	sensor := newSensor(13000)
+	port := 13000
+	sensor.Spec.Port = &port
+	assert.NotNil(t, validateReplayServer(sensor))
+	sensor.Spec.Replay.Port = common.SensorServerPort
+	assert.Nil(t, validateReplayServer(sensor))
+
+	// and to the port of the metrics server
+	sensor.Spec.Template = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Env: []corev1.EnvVar{{Name: common.EnvVarMetricsPort, Value: "12000"}}},
+			},
+		},
+	}
+	assert.NotNil(t, validateReplayServer(sensor))
+	sensor.Spec.Replay.Port = 9090
+	assert.Nil(t, validateReplayServer(sensor))
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
@@ -52,6 +53,16 @@ func main() {
 		panic(fmt.Errorf("failed to connect to server on port %s", serverPort))
 	}
 
+	// export the spans of the event deliveries
+	flushSpans, err := tracing.ConfigureExporter(ctx.name)
+	if err != nil {
+		panic(err)
+	}
+	go tracing.FlushOnTermination(flushSpans)
+
+	// expose gateway client metrics
+	go common.StartMetricsServer(common.GetMetricsPort(), ctx.logger)
+
 	// handle gateway status updates
 	go func() {
 		for status := range ctx.statusCh {
@@ -59,6 +70,10 @@ func main() {
 		}
 	}()
 
+	// replay the events that were not delivered to sensors before a restart and report the queue depths
+	ctx.RestorePendingEvents()
+	go ctx.ReportPendingEvents(context.Background())
+
 	// watch updates to gateway resource
 	if _, err := ctx.WatchGatewayUpdates(context.Background()); err != nil {
 		panic(err)
@@ -18,13 +18,23 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	"github.com/argoproj/argo-events/gateways"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
 	cloudevents "github.com/cloudevents/sdk-go"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 // dispatchEvent dispatches event to gateway transformer for further processing
@@ -32,51 +42,168 @@ func (gatewayContext *GatewayContext) dispatchEvent(gatewayEvent *gateways.Event
 	logger := gatewayContext.logger.WithField(common.LabelEventSource, gatewayEvent.Name)
 	logger.Infoln("dispatching event to subscribers")
 
+	eventsReceived.WithLabelValues(gatewayContext.name, gatewayEvent.Name).Inc()
+
+	// continue the trace started by the gateway server
+	_, span := tracing.StartSpanWithTraceParent(context.Background(), "gateway.dispatch_event", gatewayEvent.TraceParent)
+	span.AddAttributes(trace.StringAttribute(common.LabelEventSource, gatewayEvent.Name))
+	defer span.End()
+
 	cloudEvent, err := gatewayContext.transformEvent(gatewayEvent)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return err
 	}
+	// sensors continue the trace from the event extension
+	cloudEvent.SetExtension(tracing.TraceParentExtension, tracing.TraceParent(span.SpanContext()))
+
+	switch gatewayContext.gateway.Spec.EventProtocol.Type {
+	case apicommon.HTTP:
+		if gatewayEvent.Id != "" {
+			err = gatewayContext.deliverEventOverHttp(cloudEvent, logger)
+			break
+		}
+		gatewayContext.dispatchEventOverHttp(cloudEvent, logger)
+	case apicommon.NATS:
+		err = gatewayContext.dispatchEventOverNats(cloudEvent, logger)
+	default:
+		err = fmt.Errorf("unknown dispatch mechanism %s", gatewayContext.gateway.Spec.EventProtocol.Type)
+	}
+	tracing.RecordError(span, err)
+	return err
+}
 
+// dispatchEventOverHttp queues the event for each sensor watcher and signals the delivery routines.
+// The event is written to the watcher's queue before any delivery attempt, so a sensor that is down receives it once it is back.
+func (gatewayContext *GatewayContext) dispatchEventOverHttp(cloudEvent *cloudevents.Event, logger *logrus.Entry) {
 	completeSuccess := true
 
 	for _, sensor := range gatewayContext.gateway.Spec.Watchers.Sensors {
-		namespace := gatewayContext.namespace
-		if sensor.Namespace != "" {
-			namespace = sensor.Namespace
-		}
+		target := gatewayContext.sensorTarget(sensor)
 
-		target := fmt.Sprintf("http://%s:%s%s", common.ServiceDNSName(sensor.Name, namespace), gatewayContext.gateway.Spec.EventProtocol.Http.Port, common.SensorServiceEndpoint)
-
-		t, err := cloudevents.NewHTTPTransport(
-			cloudevents.WithTarget(target),
-			cloudevents.WithEncoding(cloudevents.HTTPBinaryV02),
-		)
-		if err != nil {
-			logger.WithError(err).WithField("target", target).Warnln("failed to create a transport")
-			completeSuccess = false
-			continue
-		}
-
-		client, err := cloudevents.NewClient(t)
+		queue, err := gatewayContext.getEventQueue(sensor)
 		if err != nil {
-			logger.WithError(err).WithField("target", target).Warnln("failed to create a client")
-			completeSuccess = false
+			logger.WithError(err).WithField("target", target).Warnln("failed to get the event queue, sending the event without retries")
+			err = gatewayContext.sendEventToSensor(target, cloudEvent)
+			gatewayContext.recordDispatch(target, err)
+			if err != nil {
+				logger.WithError(err).WithField("target", target).Warnln("failed to send the event")
+				completeSuccess = false
+			}
 			continue
 		}
 
-		if _, _, err := client.Send(context.Background(), *cloudEvent); err != nil {
-			logger.WithError(err).WithField("target", target).Warnln("failed to send the event")
-			completeSuccess = false
+		if err := queue.push(cloudEvent); err != nil {
+			logger.WithError(err).WithField("target", target).Warnln("failed to queue the event, sending the event without retries")
+			err := gatewayContext.sendEventToSensor(target, cloudEvent)
+			gatewayContext.recordDispatch(target, err)
+			if err != nil {
+				logger.WithError(err).WithField("target", target).Warnln("failed to send the event")
+				completeSuccess = false
+			}
 			continue
 		}
+		queue.notify()
 	}
 
-	response := "dispatched event to all subscribers"
+	response := "queued event for all subscribers"
 	if !completeSuccess {
 		response = fmt.Sprintf("%s.%s", response, " although some of the dispatch operations failed, check logs for more info")
 	}
 
 	logger.Infoln(response)
+}
+
+// deliverEventOverHttp sends the event to each sensor watcher and waits for the deliveries.
+// The event is not queued. The event source that waits for the delivery reports the failure to its caller, which retries instead.
+func (gatewayContext *GatewayContext) deliverEventOverHttp(cloudEvent *cloudevents.Event, logger *logrus.Entry) error {
+	if gatewayContext.gateway.Spec.Watchers == nil {
+		return nil
+	}
+
+	backoff := gatewayContext.deliveryBackoff()
+	sensors := gatewayContext.gateway.Spec.Watchers.Sensors
+	errs := make([]error, len(sensors))
+
+	var wg sync.WaitGroup
+	for i, sensor := range sensors {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			errs[i] = wait.ExponentialBackoff(backoff, func() (bool, error) {
+				if err := gatewayContext.sendEventToSensor(target, cloudEvent); err != nil {
+					logger.WithError(err).WithField("target", target).Debugln("failed to deliver the event, retrying...")
+					return false, nil
+				}
+				return true, nil
+			})
+			gatewayContext.recordDispatch(target, errs[i])
+		}(i, gatewayContext.sensorTarget(sensor))
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, gatewayContext.watcherKey(sensors[i]))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to deliver the event to sensors %s", strings.Join(failed, ", "))
+	}
+
+	logger.Infoln("delivered event to all subscribers")
+	return nil
+}
+
+// sensorTarget returns the url of the http server of a sensor watcher
+func (gatewayContext *GatewayContext) sensorTarget(sensor v1alpha1.SensorNotificationWatcher) string {
+	return fmt.Sprintf("http://%s:%s%s", common.ServiceDNSName(sensor.Name, gatewayContext.watcherNamespace(sensor)), gatewayContext.gateway.Spec.EventProtocol.Http.Port, common.SensorServiceEndpoint)
+}
+
+// sendEventToSensor sends the event to the target using the cloudevents http transport
+func (gatewayContext *GatewayContext) sendEventToSensor(target string, cloudEvent *cloudevents.Event) error {
+	t, err := cloudevents.NewHTTPTransport(
+		cloudevents.WithTarget(target),
+		cloudevents.WithEncoding(cloudevents.HTTPBinaryV02),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a transport")
+	}
+
+	client, err := cloudevents.NewClient(t)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a client")
+	}
+
+	if _, _, err := client.Send(context.Background(), *cloudEvent); err != nil {
+		return errors.Wrap(err, "failed to send the event")
+	}
+	return nil
+}
+
+// dispatchEventOverNats publishes the event in structured JSON encoding on the gateway's NATS subject.
+// Sensors subscribe to the subject of each gateway they depend on, so the watchers list is not consulted.
+func (gatewayContext *GatewayContext) dispatchEventOverNats(cloudEvent *cloudevents.Event, logger *logrus.Entry) error {
+	payload, err := json.Marshal(cloudEvent)
+	if err != nil {
+		return err
+	}
+
+	subject := common.DefaultNatsSubject(gatewayContext.name)
+
+	switch gatewayContext.gateway.Spec.EventProtocol.Nats.Type {
+	case apicommon.Streaming:
+		err = gatewayContext.natsStreamingConn.Publish(subject, payload)
+	default:
+		err = gatewayContext.natsConn.Publish(subject, payload)
+	}
+	gatewayContext.recordDispatch(subject, err)
+	if err != nil {
+		return err
+	}
+
+	logger.WithField(common.LabelNatsSubject, subject).Infoln("published event on nats subject")
 	return nil
 }
 
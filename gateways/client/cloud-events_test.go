@@ -17,11 +17,21 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	"github.com/argoproj/argo-events/gateways"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	cloudevents "github.com/cloudevents/sdk-go"
+	natstest "github.com/nats-io/gnatsd/test"
+	"github.com/nats-io/go-nats"
 	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -51,3 +61,58 @@ func TestTransformEvent(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, string(data), "{\"name\": \"hello\"}")
 }
+
+func TestDispatchEventOverNats(t *testing.T) {
+	opts := natstest.DefaultTestOptions
+	opts.Port = 14223
+	server := natstest.RunServer(&opts)
+	defer server.Shutdown()
+
+	gc := getGatewayContext()
+	gc.name = "fake-gateway"
+	gc.gateway.Spec.EventProtocol = &apicommon.EventProtocol{
+		Type: apicommon.NATS,
+		Nats: apicommon.Nats{
+			URL:  "nats://localhost:14223",
+			Type: apicommon.Standard,
+		},
+	}
+
+	var err error
+	gc.natsConn, err = nats.Connect("nats://localhost:14223")
+	assert.Nil(t, err)
+	defer gc.natsConn.Close()
+
+	received := make(chan *nats.Msg, 1)
+	sub, err := gc.natsConn.ChanSubscribe(common.DefaultNatsSubject("fake-gateway"), received)
+	assert.Nil(t, err)
+	defer sub.Unsubscribe()
+
+	_, span := trace.StartSpan(context.Background(), "gateway.receive_event")
+
+	err = gc.dispatchEvent(&gateways.Event{
+		Name:        "first-webhook",
+		Payload:     []byte(`{"hello": "world"}`),
+		TraceParent: tracing.TraceParent(span.SpanContext()),
+	})
+	assert.Nil(t, err)
+
+	select {
+	case msg := <-received:
+		event := cloudevents.Event{}
+		err := json.Unmarshal(msg.Data, &event)
+		assert.Nil(t, err)
+		assert.Equal(t, "fake-gateway", event.Source())
+		assert.Equal(t, "first-webhook", event.Subject())
+
+		// the trace continues in the event extension
+		var traceParent string
+		err = event.ExtensionAs(tracing.TraceParentExtension, &traceParent)
+		assert.Nil(t, err)
+		sc, err := tracing.ParseTraceParent(traceParent)
+		assert.Nil(t, err)
+		assert.Equal(t, span.SpanContext().TraceID, sc.TraceID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the event over nats")
+	}
+}
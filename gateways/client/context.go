@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/argoproj/argo-events/common"
 	"github.com/argoproj/argo-events/gateways"
@@ -69,6 +70,12 @@ type GatewayContext struct {
 	natsStreamingConn snats.Conn
 	// sensorHttpPort is the http server running in sensor that listens to event. Only used if dispatch protocol is HTTP
 	sensorHttpPort string
+	// queueDir is the directory where the queues of undelivered events are stored. Only used if dispatch protocol is HTTP
+	queueDir string
+	// eventQueues holds the queue of undelivered events for each sensor watcher
+	eventQueues map[string]*eventQueue
+	// queueLock synchronizes eventQueues
+	queueLock sync.Mutex
 }
 
 // EventSourceContext contains information of a event source for gateway to run.
@@ -108,6 +115,10 @@ func NewGatewayContext() *GatewayContext {
 	if !ok {
 		panic("server port is not provided")
 	}
+	queueDir, ok := os.LookupEnv(common.EnvVarGatewayQueueDir)
+	if !ok {
+		queueDir = common.DefaultGatewayQueueDir
+	}
 
 	clientset := kubernetes.NewForConfigOrDie(restConfig)
 	gatewayClient := gwclientset.NewForConfigOrDie(restConfig)
@@ -135,6 +146,8 @@ func NewGatewayContext() *GatewayContext {
 		controllerInstanceID: controllerInstanceID,
 		serverPort:           serverPort,
 		statusCh:             make(chan EventSourceStatus),
+		queueDir:             queueDir,
+		eventQueues:          make(map[string]*eventQueue),
 	}
 
 	switch gateway.Spec.EventProtocol.Type {
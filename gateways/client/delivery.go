@@ -0,0 +1,212 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// pendingEventsReplayPeriod is the period after which the pending events are replayed to sensors that were unreachable
+const pendingEventsReplayPeriod = 30 * time.Second
+
+// watcherNamespace returns the namespace of a sensor watcher, defaulting to the gateway namespace
+func (gatewayContext *GatewayContext) watcherNamespace(sensor v1alpha1.SensorNotificationWatcher) string {
+	if sensor.Namespace != "" {
+		return sensor.Namespace
+	}
+	return gatewayContext.namespace
+}
+
+// watcherKey returns the key of a sensor watcher within the event queues and the gateway status
+func (gatewayContext *GatewayContext) watcherKey(sensor v1alpha1.SensorNotificationWatcher) string {
+	return fmt.Sprintf("%s/%s", gatewayContext.watcherNamespace(sensor), sensor.Name)
+}
+
+// getEventQueue returns the event queue of a sensor watcher.
+// If the queue doesn't exist yet, it is restored from disk and a routine delivering its events is started.
+func (gatewayContext *GatewayContext) getEventQueue(sensor v1alpha1.SensorNotificationWatcher) (*eventQueue, error) {
+	gatewayContext.queueLock.Lock()
+	defer gatewayContext.queueLock.Unlock()
+
+	key := gatewayContext.watcherKey(sensor)
+	if queue, ok := gatewayContext.eventQueues[key]; ok {
+		return queue, nil
+	}
+
+	queue, err := newEventQueue(filepath.Join(gatewayContext.queueDir, fmt.Sprintf("%s_%s.queue", gatewayContext.watcherNamespace(sensor), sensor.Name)), gatewayContext.queueMaxEvents())
+	if err != nil {
+		return nil, err
+	}
+	gatewayContext.eventQueues[key] = queue
+
+	go gatewayContext.deliverPendingEvents(sensor, queue)
+	if queue.len() > 0 {
+		gatewayContext.logger.WithField("target", gatewayContext.sensorTarget(sensor)).WithField("pending-events", queue.len()).Infoln("restored pending events")
+		queue.notify()
+	}
+	return queue, nil
+}
+
+// RestorePendingEvents restores the event queues of the sensor watchers so the events left undelivered before a restart are replayed.
+// The events are only queued when they are dispatched over HTTP.
+func (gatewayContext *GatewayContext) RestorePendingEvents() {
+	if gatewayContext.gateway.Spec.EventProtocol.Type != apicommon.HTTP || gatewayContext.gateway.Spec.Watchers == nil {
+		return
+	}
+	for _, sensor := range gatewayContext.gateway.Spec.Watchers.Sensors {
+		if _, err := gatewayContext.getEventQueue(sensor); err != nil {
+			gatewayContext.logger.WithError(err).WithField(common.LabelSensorName, sensor.Name).Errorln("failed to restore the pending events")
+		}
+	}
+}
+
+// deliverPendingEvents delivers the events of the queue whenever new events are queued or the replay period elapses, until the queue is dropped.
+// The target is resolved on each delivery so it follows the updates of the gateway.
+func (gatewayContext *GatewayContext) deliverPendingEvents(sensor v1alpha1.SensorNotificationWatcher, queue *eventQueue) {
+	ticker := time.NewTicker(pendingEventsReplayPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-queue.notifyCh:
+		case <-ticker.C:
+		case <-queue.doneCh:
+			return
+		}
+		gatewayContext.flushEventQueue(gatewayContext.sensorTarget(sensor), queue)
+	}
+}
+
+// dropRemovedEventQueues drops the event queues of the sensor watchers that are no longer watching the gateway, along with their pending events
+func (gatewayContext *GatewayContext) dropRemovedEventQueues() {
+	watchers := make(map[string]bool)
+	if gatewayContext.gateway.Spec.Watchers != nil {
+		for _, sensor := range gatewayContext.gateway.Spec.Watchers.Sensors {
+			watchers[gatewayContext.watcherKey(sensor)] = true
+		}
+	}
+
+	gatewayContext.queueLock.Lock()
+	defer gatewayContext.queueLock.Unlock()
+
+	for key, queue := range gatewayContext.eventQueues {
+		if watchers[key] {
+			continue
+		}
+		delete(gatewayContext.eventQueues, key)
+		logger := gatewayContext.logger.WithField("watcher", key).WithField("pending-events", queue.len())
+		if err := queue.drop(); err != nil {
+			logger.WithError(err).Errorln("failed to drop the event queue of the removed sensor watcher")
+			continue
+		}
+		logger.Warnln("sensor watcher is removed, dropped its pending events")
+	}
+}
+
+// deliveryBackoff returns the backoff of the delivery of an event to a sensor
+func (gatewayContext *GatewayContext) deliveryBackoff() wait.Backoff {
+	if gatewayContext.gateway.Spec.DeliveryBackoff != nil {
+		return *gatewayContext.gateway.Spec.DeliveryBackoff
+	}
+	return common.DefaultRetry
+}
+
+// queueMaxEvents returns the maximum number of pending events in the queue of a sensor watcher
+func (gatewayContext *GatewayContext) queueMaxEvents() int {
+	if queue := gatewayContext.gateway.Spec.Queue; queue != nil && queue.MaxEvents > 0 {
+		return int(queue.MaxEvents)
+	}
+	return common.DefaultGatewayQueueMaxEvents
+}
+
+// flushEventQueue sends the pending events to the target in order.
+// An event is removed from the queue only after the sensor has accepted it. If the backoff is exhausted,
+// the remaining events stay in the queue until the next replay.
+func (gatewayContext *GatewayContext) flushEventQueue(target string, queue *eventQueue) {
+	logger := gatewayContext.logger.WithField("target", target)
+
+	backoff := gatewayContext.deliveryBackoff()
+
+	for event := queue.peek(); event != nil; event = queue.peek() {
+		err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+			// the delivery is abandoned once the queue is dropped
+			select {
+			case <-queue.doneCh:
+				return false, errEventQueueDropped
+			default:
+			}
+			if err := gatewayContext.sendEventToSensor(target, event); err != nil {
+				logger.WithError(err).Debugln("failed to deliver the event, retrying...")
+				return false, nil
+			}
+			return true, nil
+		})
+		if err == errEventQueueDropped {
+			return
+		}
+		gatewayContext.recordDispatch(target, err)
+		if err != nil {
+			logger.WithField("pending-events", queue.len()).Warnln("sensor is unreachable, pending events will be replayed later")
+			return
+		}
+		if err := queue.pop(); err != nil {
+			if err != errEventQueueDropped {
+				logger.WithError(err).Errorln("failed to remove the delivered event from the queue")
+			}
+			return
+		}
+		logger.WithField("event-id", event.ID()).Infoln("event delivered")
+	}
+}
+
+// pendingEvents returns the depth of each event queue
+func (gatewayContext *GatewayContext) pendingEvents() map[string]int32 {
+	gatewayContext.queueLock.Lock()
+	defer gatewayContext.queueLock.Unlock()
+
+	depths := make(map[string]int32, len(gatewayContext.eventQueues))
+	for key, queue := range gatewayContext.eventQueues {
+		depths[key] = int32(queue.len())
+	}
+	return depths
+}
+
+// ReportPendingEvents periodically reports the depth of the event queues to the gateway status, only when it changes
+func (gatewayContext *GatewayContext) ReportPendingEvents(ctx context.Context) {
+	lastDepths := make(map[string]int32)
+	wait.Until(func() {
+		depths := gatewayContext.pendingEvents()
+		if reflect.DeepEqual(depths, lastDepths) {
+			return
+		}
+		lastDepths = depths
+		gatewayContext.statusCh <- EventSourceStatus{
+			Phase:         v1alpha1.NodePhasePendingEvents,
+			Message:       "pending_events_updated",
+			PendingEvents: depths,
+		}
+	}, pendingEventsReplayPeriod, ctx.Done())
+}
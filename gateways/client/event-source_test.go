@@ -26,20 +26,26 @@ import (
 
 	"github.com/argoproj/argo-events/common"
 	"github.com/argoproj/argo-events/gateways"
+	gatewayserver "github.com/argoproj/argo-events/gateways/server"
 	"github.com/argoproj/argo-events/gateways/server/common/webhook"
 	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	esv1alpha1 "github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
 	"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
 	gwfake "github.com/argoproj/argo-events/pkg/client/gateway/clientset/versioned/fake"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
 func getGatewayContext() *GatewayContext {
 	return &GatewayContext{
 		logger:     common.NewArgoEventsLogger(),
+		namespace:  "fake-namespace",
 		serverPort: "20000",
 		statusCh:   make(chan EventSourceStatus),
 		gateway: &v1alpha1.Gateway{
@@ -61,6 +67,7 @@ func getGatewayContext() *GatewayContext {
 			},
 		},
 		eventSourceContexts: make(map[string]*EventSourceContext),
+		eventQueues:         make(map[string]*eventQueue),
 		k8sClient:           fake.NewSimpleClientset(),
 		gatewayClient:       gwfake.NewSimpleClientset(),
 	}
@@ -112,10 +119,116 @@ func (listener *testEventListener) ValidateEventSource(ctx context.Context, even
 
 func getGatewayServer() *grpc.Server {
 	srv := grpc.NewServer()
-	gateways.RegisterEventingServer(srv, &testEventListener{})
+	gateways.RegisterEventingServer(srv, gatewayserver.NewEventingServer(&testEventListener{}))
 	return srv
 }
 
+// legacyEventingServer is a gateway server that predates the acknowledgements of the events
+type legacyEventingServer struct {
+	gateways.EventingServer
+}
+
+func (server *legacyEventingServer) StartEventSourceWithAck(stream gateways.Eventing_StartEventSourceWithAckServer) error {
+	return status.Error(codes.Unimplemented, "method StartEventSourceWithAck not implemented")
+}
+
+func TestActivateEventSourcesWithoutAck(t *testing.T) {
+	gatewayContext := getGatewayContext()
+	gatewayContext.name = "legacy-gateway"
+	received := eventsReceived.WithLabelValues(gatewayContext.name, "first-webhook")
+	before := testutil.ToFloat64(received)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", gatewayContext.serverPort))
+	assert.Nil(t, err)
+	server := grpc.NewServer()
+	gateways.RegisterEventingServer(server, &legacyEventingServer{gatewayserver.NewEventingServer(&testEventListener{})})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	failures := make(chan EventSourceStatus, 10)
+	go func() {
+		for status := range gatewayContext.statusCh {
+			if status.Phase == v1alpha1.NodePhaseError {
+				failures <- status
+			}
+		}
+	}()
+
+	contexts := gatewayContext.initEventSourceContexts(getEventSource().DeepCopy())
+	var keys []string
+	for key := range contexts {
+		keys = append(keys, key)
+	}
+	gatewayContext.activateEventSources(contexts, keys)
+
+	// the event of the stream without acknowledgements is received
+	err = wait.PollImmediate(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+		return testutil.ToFloat64(received) == before+1, nil
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, failures)
+	gatewayContext.deactivateEventSources(keys)
+}
+
+// fakeAckClient records the acknowledgements sent to the gateway server
+type fakeAckClient struct {
+	grpc.ClientStream
+	acks chan *gateways.EventAck
+}
+
+func (client *fakeAckClient) Send(request *gateways.EventSourceRequest) error {
+	client.acks <- request.Ack
+	return nil
+}
+
+func (client *fakeAckClient) Recv() (*gateways.Event, error) {
+	return nil, nil
+}
+
+func TestDeliverAckedEvents(t *testing.T) {
+	gatewayContext := getGatewayContext()
+	ctx, cancel := context.WithCancel(context.Background())
+	eventSource := &EventSourceContext{
+		source: &gateways.EventSource{Id: "1", Name: "first-webhook"},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	client := &fakeAckClient{acks: make(chan *gateways.EventAck, 10)}
+
+	events := make(chan *gateways.Event, 10)
+	for i := 0; i < 5; i++ {
+		events <- &gateways.Event{
+			Name:    "first-webhook",
+			Payload: []byte(`{"hello": "world"}`),
+			Id:      fmt.Sprintf("%d", i),
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		gatewayContext.deliverAckedEvents(eventSource, client, events, make(chan struct{}), gatewayContext.logger.WithField(common.LabelEventSource, "first-webhook"))
+		close(done)
+	}()
+
+	// the events are acknowledged in the order they are received
+	for i := 0; i < 5; i++ {
+		select {
+		case ack := <-client.acks:
+			assert.Equal(t, fmt.Sprintf("%d", i), ack.Id)
+			assert.Empty(t, ack.Error)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event is not acknowledged")
+		}
+	}
+
+	// the delivery stops with the event source
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery did not stop")
+	}
+}
+
 func TestInitEventSourceContexts(t *testing.T) {
 	gatewayContext := getGatewayContext()
 	eventSource := getEventSource().DeepCopy()
@@ -28,10 +28,20 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 	"io"
 )
 
+// ackedEventsBufferSize is the number of events that wait for their delivery and acknowledgement before the stream stops being read
+const ackedEventsBufferSize = 100
+
+// eventReceiver receives the events of an event source from the gateway server
+type eventReceiver interface {
+	Recv() (*gateways.Event, error)
+}
+
 // populateEventSourceContexts sets up the contexts for event sources
 func (gatewayContext *GatewayContext) populateEventSourceContexts(name string, value interface{}, eventSourceContexts map[string]*EventSourceContext) {
 	body, err := yaml.Marshal(value)
@@ -179,7 +189,16 @@ func (gatewayContext *GatewayContext) activateEventSources(eventSources map[stri
 			}
 
 			// listen to events from gateway server
-			eventStream, err := eventSource.client.StartEventSource(eventSource.ctx, eventSource.source)
+			eventStream, err := eventSource.client.StartEventSourceWithAck(eventSource.ctx)
+			if err == nil {
+				err = eventStream.Send(&gateways.EventSourceRequest{
+					EventSource: eventSource.source,
+				})
+				if err == io.EOF {
+					// the stream was closed by the gateway server, its error is returned by the first receive
+					err = nil
+				}
+			}
 			if err != nil {
 				logger.WithError(err).Errorln("error occurred while starting event source")
 				gatewayContext.statusCh <- EventSourceStatus{
@@ -191,9 +210,28 @@ func (gatewayContext *GatewayContext) activateEventSources(eventSources map[stri
 				return
 			}
 
+			// the events that wait for an acknowledgement are delivered in order by a single routine,
+			// which stops once the event source is stopped or its stream ends
+			ackedEvents := make(chan *gateways.Event, ackedEventsBufferSize)
+			streamDone := make(chan struct{})
+			defer close(streamDone)
+			go gatewayContext.deliverAckedEvents(eventSource, eventStream, ackedEvents, streamDone, logger)
+
+			// the gateway servers that predate the acknowledgements only serve the stream without them
+			var events eventReceiver = eventStream
+			fallback := true
+
 			logger.Infoln("listening to events from gateway server...")
 			for {
-				event, err := eventStream.Recv()
+				event, err := events.Recv()
+				if err != nil && fallback && status.Code(err) == codes.Unimplemented {
+					logger.Infoln("gateway server doesn't acknowledge the events, starting the event source without acknowledgements...")
+					if events, err = eventSource.client.StartEventSource(eventSource.ctx, eventSource.source); err == nil {
+						fallback = false
+						continue
+					}
+				}
+				fallback = false
 				if err != nil {
 					if err == io.EOF {
 						logger.Infoln("event source has stopped")
@@ -215,26 +253,66 @@ func (gatewayContext *GatewayContext) activateEventSources(eventSources map[stri
 					}
 					return
 				}
-				err = gatewayContext.dispatchEvent(event)
-				if err != nil {
-					// escalate error through a K8s event
-					labels := map[string]string{
-						common.LabelEventType:       string(common.EscalationEventType),
-						common.LabelEventSourceName: eventSource.source.Name,
-						common.LabelResourceName:    gatewayContext.name,
-						common.LabelEventSourceID:   eventSource.source.Id,
-						common.LabelOperation:       "dispatch_event_to_watchers",
-					}
-					if err := common.GenerateK8sEvent(gatewayContext.k8sClient, fmt.Sprintf("failed to dispatch event to watchers"), common.EscalationEventType, "event dispatch failed", gatewayContext.name, gatewayContext.namespace, gatewayContext.controllerInstanceID, gateway.Kind, labels); err != nil {
-						logger.WithError(err).Errorln("failed to create K8s event to escalate event dispatch failure")
+
+				// the event source waits for the delivery of the event, deliver it without blocking the stream
+				if event.Id != "" {
+					select {
+					case ackedEvents <- event:
+					case <-eventSource.ctx.Done():
+						return
 					}
-					logger.WithError(err).Errorln("failed to dispatch event to watchers")
+					continue
+				}
+
+				if err := gatewayContext.dispatchEvent(event); err != nil {
+					gatewayContext.escalateDispatchFailure(eventSource, err, logger)
 				}
 			}
 		}()
 	}
 }
 
+// deliverAckedEvents dispatches the events in the order they are received and acknowledges each of them on the stream,
+// until the event source is stopped or its stream ends
+func (gatewayContext *GatewayContext) deliverAckedEvents(eventSource *EventSourceContext, eventStream gateways.Eventing_StartEventSourceWithAckClient, events <-chan *gateways.Event, streamDone <-chan struct{}, logger *logrus.Entry) {
+	for {
+		var event *gateways.Event
+		select {
+		case event = <-events:
+		case <-streamDone:
+			return
+		case <-eventSource.ctx.Done():
+			return
+		}
+
+		ack := &gateways.EventAck{
+			Id: event.Id,
+		}
+		if err := gatewayContext.dispatchEvent(event); err != nil {
+			gatewayContext.escalateDispatchFailure(eventSource, err, logger)
+			ack.Error = err.Error()
+		}
+		if err := eventStream.Send(&gateways.EventSourceRequest{Ack: ack}); err != nil {
+			logger.WithError(err).Errorln("failed to acknowledge the event")
+		}
+	}
+}
+
+// escalateDispatchFailure escalates the failure to dispatch an event through a K8s event
+func (gatewayContext *GatewayContext) escalateDispatchFailure(eventSource *EventSourceContext, err error, logger *logrus.Entry) {
+	labels := map[string]string{
+		common.LabelEventType:       string(common.EscalationEventType),
+		common.LabelEventSourceName: eventSource.source.Name,
+		common.LabelResourceName:    gatewayContext.name,
+		common.LabelEventSourceID:   eventSource.source.Id,
+		common.LabelOperation:       "dispatch_event_to_watchers",
+	}
+	if err := common.GenerateK8sEvent(gatewayContext.k8sClient, fmt.Sprintf("failed to dispatch event to watchers"), common.EscalationEventType, "event dispatch failed", gatewayContext.name, gatewayContext.namespace, gatewayContext.controllerInstanceID, gateway.Kind, labels); err != nil {
+		logger.WithError(err).Errorln("failed to create K8s event to escalate event dispatch failure")
+	}
+	logger.WithError(err).Errorln("failed to dispatch event to watchers")
+}
+
 // deactivateEventSources inactivate an existing event sources
 func (gatewayContext *GatewayContext) deactivateEventSources(eventSourceNames []string) {
 	for _, eventSourceName := range eventSourceNames {
@@ -0,0 +1,53 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/argoproj/argo-events/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// eventsReceived counts the events received from each event source
+	eventsReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "gateway",
+		Name:      "events_received_total",
+		Help:      "Number of events received from the event sources",
+	}, []string{common.MetricLabelGatewayName, common.MetricLabelEventSourceName})
+
+	// eventsDispatched counts the successful and failed deliveries to each sensor watcher
+	eventsDispatched = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "gateway",
+		Name:      "events_dispatched_total",
+		Help:      "Number of event deliveries to the sensor watchers, by outcome",
+	}, []string{common.MetricLabelGatewayName, common.MetricLabelWatcher, common.MetricLabelStatus})
+)
+
+func init() {
+	prometheus.MustRegister(eventsReceived, eventsDispatched)
+}
+
+// recordDispatch records the outcome of a delivery to a sensor watcher
+func (gatewayContext *GatewayContext) recordDispatch(watcher string, err error) {
+	status := common.MetricStatusSuccess
+	if err != nil {
+		status = common.MetricStatusFailure
+	}
+	eventsDispatched.WithLabelValues(gatewayContext.name, watcher, status).Inc()
+}
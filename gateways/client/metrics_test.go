@@ -0,0 +1,98 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/gateways"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestEventsReceivedMetric(t *testing.T) {
+	gc := getGatewayContext()
+	gc.name = "fake-gateway"
+	received := eventsReceived.WithLabelValues(gc.name, "metrics-webhook")
+	before := testutil.ToFloat64(received)
+
+	err := gc.dispatchEvent(&gateways.Event{
+		Name:    "metrics-webhook",
+		Payload: []byte(`{"hello": "world"}`),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(received))
+}
+
+func TestEventsDispatchedMetric(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event-queue")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var lock sync.Mutex
+	available := false
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if !available {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	gc := getGatewayContext()
+	gc.name = "fake-gateway"
+	gc.gateway.Spec.DeliveryBackoff = &wait.Backoff{
+		Duration: 10 * time.Millisecond,
+		Factor:   1,
+		Steps:    2,
+	}
+	success := eventsDispatched.WithLabelValues(gc.name, server.URL, common.MetricStatusSuccess)
+	failure := eventsDispatched.WithLabelValues(gc.name, server.URL, common.MetricStatusFailure)
+	successBefore, failureBefore := testutil.ToFloat64(success), testutil.ToFloat64(failure)
+
+	queue, err := newEventQueue(filepath.Join(dir, "fake-namespace_fake-sensor.queue"), 0)
+	assert.Nil(t, err)
+	for _, id := range []string{"1", "2"} {
+		err := queue.push(newTestCloudEvent(id))
+		assert.Nil(t, err)
+	}
+
+	// sensor is down, the failed delivery is recorded once
+	gc.flushEventQueue(server.URL, queue)
+	assert.Equal(t, successBefore, testutil.ToFloat64(success))
+	assert.Equal(t, failureBefore+1, testutil.ToFloat64(failure))
+
+	// sensor is back, every delivered event is recorded
+	lock.Lock()
+	available = true
+	lock.Unlock()
+	gc.flushEventQueue(server.URL, queue)
+	assert.Equal(t, successBefore+2, testutil.ToFloat64(success))
+	assert.Equal(t, failureBefore+1, testutil.ToFloat64(failure))
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/pkg/errors"
+)
+
+// popRecord is the record appended to the file when the oldest pending event is delivered
+const popRecord = "pop"
+
+// compactionThreshold is the number of pop records after which the file is rewritten with the pending events only
+const compactionThreshold = 1000
+
+// errEventQueueFull is returned when an event is pushed to a queue that holds the maximum number of pending events
+var errEventQueueFull = errors.New("event queue is full")
+
+// errEventQueueDropped is returned when an event is pushed to or popped from a queue that is dropped
+var errEventQueueDropped = errors.New("event queue is dropped")
+
+// eventQueue is a write-ahead queue of events that are yet to be delivered to a sensor watcher.
+// Every event is appended to a file before the delivery is attempted, so pending events survive a restart of the gateway client.
+// Delivered events are recorded by appending a pop record, and the file is compacted once the pop records outnumber the pending events.
+type eventQueue struct {
+	// lock synchronizes the access to events and the file
+	lock sync.Mutex
+	// path of the file that backs the queue
+	path string
+	// maxEvents is the maximum number of pending events
+	maxEvents int
+	// events are the pending events in the order they were received
+	events []*cloudevents.Event
+	// pops is the number of pop records in the file since the last compaction
+	pops int
+	// notifyCh signals the delivery routine that new events are queued
+	notifyCh chan struct{}
+	// doneCh is closed once the queue is dropped, to stop the delivery routine
+	doneCh chan struct{}
+}
+
+// newEventQueue returns a queue backed by the file at given path, restoring the events already stored in it
+func newEventQueue(path string, maxEvents int) (*eventQueue, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	queue := &eventQueue{
+		path:      path,
+		maxEvents: maxEvents,
+		notifyCh:  make(chan struct{}, 1),
+		doneCh:    make(chan struct{}),
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return queue, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if scanner.Text() == popRecord {
+			if len(queue.events) > 0 {
+				queue.events = queue.events[1:]
+			}
+			queue.pops++
+			continue
+		}
+		event := &cloudevents.Event{}
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+			return nil, fmt.Errorf("failed to restore a pending event from %s. err: %+v", path, err)
+		}
+		queue.events = append(queue.events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// push writes the event to the file and then adds it to the queue.
+// It returns errEventQueueFull if the queue already holds the maximum number of pending events.
+func (queue *eventQueue) push(event *cloudevents.Event) error {
+	record, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	if queue.isDropped() {
+		return errEventQueueDropped
+	}
+	if queue.maxEvents > 0 && len(queue.events) >= queue.maxEvents {
+		return errEventQueueFull
+	}
+	if err := queue.append(record); err != nil {
+		return err
+	}
+
+	queue.events = append(queue.events, event)
+	return nil
+}
+
+// peek returns the oldest pending event without removing it
+func (queue *eventQueue) peek() *cloudevents.Event {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	if len(queue.events) == 0 {
+		return nil
+	}
+	return queue.events[0]
+}
+
+// pop removes the oldest pending event by appending a pop record to the file.
+// The file is compacted once the queue is empty or the pop records outnumber the pending events.
+func (queue *eventQueue) pop() error {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	if queue.isDropped() {
+		return errEventQueueDropped
+	}
+	if len(queue.events) == 0 {
+		return nil
+	}
+	if err := queue.append([]byte(popRecord)); err != nil {
+		return err
+	}
+	queue.events[0] = nil
+	queue.events = queue.events[1:]
+	queue.pops++
+
+	if len(queue.events) == 0 || (queue.pops >= compactionThreshold && queue.pops >= len(queue.events)) {
+		return queue.compact()
+	}
+	return nil
+}
+
+// append writes a record to the file. must be called with the lock held.
+func (queue *eventQueue) append(record []byte) error {
+	file, err := os.OpenFile(queue.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(record, '\n')); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// compact rewrites the file with the events that are still pending. must be called with the lock held.
+func (queue *eventQueue) compact() error {
+	tmpPath := fmt.Sprintf("%s.tmp", queue.path)
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, event := range queue.events {
+		record, err := json.Marshal(event)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := writer.Write(append(record, '\n')); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, queue.path); err != nil {
+		return err
+	}
+	queue.pops = 0
+	return nil
+}
+
+// len returns the number of pending events
+func (queue *eventQueue) len() int {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	return len(queue.events)
+}
+
+// notify signals the delivery routine without blocking
+func (queue *eventQueue) notify() {
+	select {
+	case queue.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// drop discards the pending events and removes the file, then stops the delivery routine
+func (queue *eventQueue) drop() error {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	if queue.isDropped() {
+		return nil
+	}
+	close(queue.doneCh)
+	queue.events = nil
+	if err := os.Remove(queue.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// isDropped returns true if the queue is dropped. must be called with the lock held.
+func (queue *eventQueue) isDropped() bool {
+	select {
+	case <-queue.doneCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/gateways"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1"
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func newTestCloudEvent(id string) *cloudevents.Event {
+	event := cloudevents.NewEvent(cloudevents.VersionV03)
+	event.SetID(id)
+	event.SetType("webhook")
+	event.SetSource("fake-gateway")
+	event.SetSubject("first-webhook")
+	event.SetDataContentType("application/json")
+	event.SetTime(time.Now())
+	return &event
+}
+
+func TestEventQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event-queue")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "fake-namespace_fake-sensor.queue")
+	queue, err := newEventQueue(path, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, queue.len())
+	assert.Nil(t, queue.peek())
+
+	for _, id := range []string{"1", "2", "3"} {
+		err := queue.push(newTestCloudEvent(id))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 3, queue.len())
+	assert.Equal(t, "1", queue.peek().ID())
+
+	// the queue is full
+	err = queue.push(newTestCloudEvent("4"))
+	assert.Equal(t, errEventQueueFull, err)
+
+	err = queue.pop()
+	assert.Nil(t, err)
+	assert.Equal(t, "2", queue.peek().ID())
+
+	// a new queue on the same file restores the pending events in order
+	restored, err := newEventQueue(path, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, restored.len())
+	assert.Equal(t, "2", restored.peek().ID())
+
+	// the file is compacted once the queue is empty
+	assert.Nil(t, restored.pop())
+	assert.Nil(t, restored.pop())
+	assert.Equal(t, 0, restored.len())
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), info.Size())
+}
+
+func TestEventQueueCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event-queue")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "fake-namespace_fake-sensor.queue")
+	queue, err := newEventQueue(path, 0)
+	assert.Nil(t, err)
+
+	for i := 0; i < 2*compactionThreshold; i++ {
+		err := queue.push(newTestCloudEvent(strconv.Itoa(i)))
+		assert.Nil(t, err)
+	}
+	for i := 0; i < compactionThreshold-1; i++ {
+		assert.Nil(t, queue.pop())
+	}
+	assert.Equal(t, compactionThreshold-1, queue.pops)
+
+	// the pop records reach the number of pending events
+	assert.Nil(t, queue.pop())
+	assert.Equal(t, 0, queue.pops)
+
+	restored, err := newEventQueue(path, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, compactionThreshold, restored.len())
+	assert.Equal(t, strconv.Itoa(compactionThreshold), restored.peek().ID())
+	assert.Equal(t, 0, restored.pops)
+}
+
+func TestFlushEventQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event-queue")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var lock sync.Mutex
+	available := false
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if !available {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, request.Header.Get("ce-id"))
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	gc := getGatewayContext()
+	gc.gateway.Spec.DeliveryBackoff = &wait.Backoff{
+		Duration: 10 * time.Millisecond,
+		Factor:   1,
+		Steps:    2,
+	}
+
+	queue, err := newEventQueue(filepath.Join(dir, "fake-namespace_fake-sensor.queue"), 0)
+	assert.Nil(t, err)
+	for _, id := range []string{"1", "2"} {
+		err := queue.push(newTestCloudEvent(id))
+		assert.Nil(t, err)
+	}
+
+	// sensor is down, events must stay in the queue
+	gc.flushEventQueue(server.URL, queue)
+	assert.Equal(t, 2, queue.len())
+
+	// sensor is back, pending events are replayed in order
+	lock.Lock()
+	available = true
+	lock.Unlock()
+	gc.flushEventQueue(server.URL, queue)
+	assert.Equal(t, 0, queue.len())
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"1", "2"}, received)
+}
+
+func TestDispatchEventOverHttpQueuesEvent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event-queue")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	gc := getGatewayContext()
+	gc.queueDir = dir
+	gc.gateway.Spec.Watchers.Sensors = append(gc.gateway.Spec.Watchers.Sensors, v1alpha1.SensorNotificationWatcher{
+		Name: "fake-sensor",
+	})
+
+	err = gc.dispatchEvent(&gateways.Event{
+		Name:    "first-webhook",
+		Payload: []byte(`{"hello": "world"}`),
+	})
+	assert.Nil(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "fake-namespace_fake-sensor.queue"))
+	assert.Nil(t, err)
+	assert.Contains(t, gc.pendingEvents(), "fake-namespace/fake-sensor")
+}
+
+func TestDropRemovedEventQueues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event-queue")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	gc := getGatewayContext()
+	gc.queueDir = dir
+	sensor := v1alpha1.SensorNotificationWatcher{
+		Name: "fake-sensor",
+	}
+	gc.gateway.Spec.Watchers.Sensors = append(gc.gateway.Spec.Watchers.Sensors, sensor)
+
+	queue, err := gc.getEventQueue(sensor)
+	assert.Nil(t, err)
+	assert.Nil(t, queue.push(newTestCloudEvent("1")))
+
+	// the queue is kept while the sensor watches the gateway
+	gc.dropRemovedEventQueues()
+	assert.Contains(t, gc.pendingEvents(), "fake-namespace/fake-sensor")
+
+	gc.gateway.Spec.Watchers.Sensors = gc.gateway.Spec.Watchers.Sensors[:len(gc.gateway.Spec.Watchers.Sensors)-1]
+	gc.dropRemovedEventQueues()
+	assert.NotContains(t, gc.pendingEvents(), "fake-namespace/fake-sensor")
+	_, err = os.Stat(filepath.Join(dir, "fake-namespace_fake-sensor.queue"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, errEventQueueDropped, queue.push(newTestCloudEvent("2")))
+}
+
+func TestRestorePendingEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event-queue")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	gc := getGatewayContext()
+	gc.queueDir = dir
+	gc.gateway.Spec.Watchers.Sensors = append(gc.gateway.Spec.Watchers.Sensors, v1alpha1.SensorNotificationWatcher{
+		Name: "fake-sensor",
+	})
+
+	// the events are not queued when they are dispatched over nats
+	gc.gateway.Spec.EventProtocol.Type = apicommon.NATS
+	gc.RestorePendingEvents()
+	assert.Empty(t, gc.pendingEvents())
+
+	gc.gateway.Spec.EventProtocol.Type = apicommon.HTTP
+	gc.RestorePendingEvents()
+	assert.Contains(t, gc.pendingEvents(), "fake-namespace/fake-sensor")
+}
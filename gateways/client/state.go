@@ -38,6 +38,8 @@ type EventSourceStatus struct {
 	Phase v1alpha1.NodePhase
 	// Gateway reference
 	Gateway *v1alpha1.Gateway
+	// PendingEvents is the depth of the undelivered event queue of each sensor watcher
+	PendingEvents map[string]int32
 }
 
 // markGatewayNodePhase marks the node with a phase, returns the node
@@ -110,7 +112,7 @@ func (gatewayContext *GatewayContext) initializeNode(nodeID string, nodeName str
 // UpdateGatewayState updates gateway resource nodes state
 func (gatewayContext *GatewayContext) UpdateGatewayState(status *EventSourceStatus) {
 	logger := gatewayContext.logger
-	if status.Phase != v1alpha1.NodePhaseResourceUpdate {
+	if status.Phase != v1alpha1.NodePhaseResourceUpdate && status.Phase != v1alpha1.NodePhasePendingEvents {
 		logger = logger.WithField(common.LabelEventSource, status.Name).Logger
 	}
 
@@ -126,6 +128,11 @@ func (gatewayContext *GatewayContext) UpdateGatewayState(status *EventSourceStat
 
 	case v1alpha1.NodePhaseResourceUpdate:
 		gatewayContext.gateway = status.Gateway
+		gatewayContext.dropRemovedEventQueues()
+
+	case v1alpha1.NodePhasePendingEvents:
+		gatewayContext.gateway.Status.PendingEvents = status.PendingEvents
+		gatewayContext.updated = true
 
 	case v1alpha1.NodePhaseRemove:
 		delete(gatewayContext.gateway.Status.Nodes, status.Id)
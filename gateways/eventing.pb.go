@@ -97,7 +97,11 @@ type Event struct {
 	// The event source name.
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// The event payload.
-	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// The trace context of the event in the W3C traceparent format.
+	TraceParent string `protobuf:"bytes,3,opt,name=traceParent,proto3" json:"traceParent,omitempty"`
+	// ID of the event. Set only if the gateway server waits for the acknowledgement of the event.
+	Id                   string   `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -142,6 +146,122 @@ func (m *Event) GetPayload() []byte {
 	return nil
 }
 
+func (m *Event) GetTraceParent() string {
+	if m != nil {
+		return m.TraceParent
+	}
+	return ""
+}
+
+func (m *Event) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+//*
+// Represents a request on the stream of an event source with acknowledgements
+type EventSourceRequest struct {
+	// The event source to start. Set only on the first request.
+	EventSource *EventSource `protobuf:"bytes,1,opt,name=eventSource,proto3" json:"eventSource,omitempty"`
+	// The acknowledgement of an event.
+	Ack                  *EventAck `protobuf:"bytes,2,opt,name=ack,proto3" json:"ack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *EventSourceRequest) Reset()         { *m = EventSourceRequest{} }
+func (m *EventSourceRequest) String() string { return proto.CompactTextString(m) }
+func (*EventSourceRequest) ProtoMessage()    {}
+func (*EventSourceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2abcc01b0da84106, []int{2}
+}
+
+func (m *EventSourceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventSourceRequest.Unmarshal(m, b)
+}
+func (m *EventSourceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventSourceRequest.Marshal(b, m, deterministic)
+}
+func (m *EventSourceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventSourceRequest.Merge(m, src)
+}
+func (m *EventSourceRequest) XXX_Size() int {
+	return xxx_messageInfo_EventSourceRequest.Size(m)
+}
+func (m *EventSourceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventSourceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventSourceRequest proto.InternalMessageInfo
+
+func (m *EventSourceRequest) GetEventSource() *EventSource {
+	if m != nil {
+		return m.EventSource
+	}
+	return nil
+}
+
+func (m *EventSourceRequest) GetAck() *EventAck {
+	if m != nil {
+		return m.Ack
+	}
+	return nil
+}
+
+//*
+// Represents the acknowledgement of an event by the gateway client
+type EventAck struct {
+	// ID of the event.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The error that occurred while dispatching the event. Empty if the event was dispatched.
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventAck) Reset()         { *m = EventAck{} }
+func (m *EventAck) String() string { return proto.CompactTextString(m) }
+func (*EventAck) ProtoMessage()    {}
+func (*EventAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2abcc01b0da84106, []int{3}
+}
+
+func (m *EventAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventAck.Unmarshal(m, b)
+}
+func (m *EventAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventAck.Marshal(b, m, deterministic)
+}
+func (m *EventAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventAck.Merge(m, src)
+}
+func (m *EventAck) XXX_Size() int {
+	return xxx_messageInfo_EventAck.Size(m)
+}
+func (m *EventAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventAck proto.InternalMessageInfo
+
+func (m *EventAck) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *EventAck) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 //*
 // Represents if an event source is valid or not
 type ValidEventSource struct {
@@ -158,7 +278,7 @@ func (m *ValidEventSource) Reset()         { *m = ValidEventSource{} }
 func (m *ValidEventSource) String() string { return proto.CompactTextString(m) }
 func (*ValidEventSource) ProtoMessage()    {}
 func (*ValidEventSource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2abcc01b0da84106, []int{2}
+	return fileDescriptor_2abcc01b0da84106, []int{4}
 }
 
 func (m *ValidEventSource) XXX_Unmarshal(b []byte) error {
@@ -196,29 +316,37 @@ func (m *ValidEventSource) GetReason() string {
 func init() {
 	proto.RegisterType((*EventSource)(nil), "gateways.EventSource")
 	proto.RegisterType((*Event)(nil), "gateways.Event")
+	proto.RegisterType((*EventSourceRequest)(nil), "gateways.EventSourceRequest")
+	proto.RegisterType((*EventAck)(nil), "gateways.EventAck")
 	proto.RegisterType((*ValidEventSource)(nil), "gateways.ValidEventSource")
 }
 
 func init() { proto.RegisterFile("eventing.proto", fileDescriptor_2abcc01b0da84106) }
 
 var fileDescriptor_2abcc01b0da84106 = []byte{
-	// 241 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x90, 0x4d, 0x4b, 0xc3, 0x40,
-	0x10, 0x86, 0xd9, 0xd8, 0x8f, 0x38, 0x96, 0x5a, 0xc6, 0x0f, 0x96, 0x9e, 0x4a, 0x4e, 0x3d, 0x05,
-	0x51, 0xbc, 0x79, 0xb4, 0xe0, 0x39, 0x05, 0x2f, 0x9e, 0x46, 0x33, 0x94, 0x85, 0xb8, 0x1b, 0x36,
-	0xdb, 0x4a, 0xfe, 0x86, 0xbf, 0x58, 0x32, 0x4d, 0x74, 0xe9, 0xc9, 0xdb, 0xbc, 0xcf, 0x0e, 0xcf,
-	0xce, 0x0c, 0xcc, 0xf9, 0xc0, 0x36, 0x18, 0xbb, 0xcb, 0x6b, 0xef, 0x82, 0xc3, 0x74, 0x47, 0x81,
-	0xbf, 0xa8, 0x6d, 0xb2, 0x37, 0xb8, 0xd8, 0x74, 0x6f, 0x5b, 0xb7, 0xf7, 0x1f, 0x8c, 0x73, 0x48,
-	0x4c, 0xa9, 0xd5, 0x4a, 0xad, 0xcf, 0x8b, 0xc4, 0x94, 0x88, 0x30, 0xb2, 0xf4, 0xc9, 0x3a, 0x11,
-	0x22, 0x35, 0x5e, 0xc3, 0xf8, 0x40, 0xd5, 0x9e, 0xf5, 0xd9, 0x4a, 0xad, 0x67, 0xc5, 0x31, 0x74,
-	0x9d, 0xa1, 0xad, 0x59, 0x8f, 0x8e, 0x9d, 0x5d, 0x9d, 0x3d, 0xc2, 0x58, 0xe4, 0xbf, 0x1a, 0x15,
-	0x69, 0x34, 0x4c, 0x6b, 0x6a, 0x2b, 0x47, 0xa5, 0xd8, 0x67, 0xc5, 0x10, 0xb3, 0x67, 0x58, 0xbc,
-	0x52, 0x65, 0xca, 0x78, 0x30, 0x0d, 0x53, 0xd3, 0x08, 0x15, 0x49, 0x5a, 0x0c, 0x11, 0x6f, 0x61,
-	0xe2, 0x99, 0x1a, 0x67, 0xfb, 0x21, 0xfb, 0x74, 0xff, 0xad, 0x20, 0xdd, 0xf4, 0x6b, 0xe3, 0x13,
-	0x2c, 0xb6, 0x81, 0x7c, 0x88, 0x95, 0x37, 0xf9, 0x70, 0x85, 0x3c, 0xc2, 0xcb, 0xcb, 0x13, 0x7c,
-	0xa7, 0xf0, 0x05, 0xae, 0xe4, 0x2f, 0x0a, 0xfc, 0x0f, 0xc1, 0xf2, 0x0f, 0x9f, 0xae, 0xf1, 0x3e,
-	0x91, 0xfb, 0x3f, 0xfc, 0x04, 0x00, 0x00, 0xff, 0xff, 0x1a, 0xc0, 0xda, 0xf1, 0x91, 0x01, 0x00,
-	0x00,
+	// 340 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x4d, 0x4f, 0xc2, 0x40,
+	0x10, 0xcd, 0xf2, 0xcd, 0x94, 0x20, 0x19, 0x51, 0x1b, 0xe2, 0x81, 0x34, 0x1e, 0x38, 0x11, 0x82,
+	0x07, 0x2f, 0x5e, 0x4c, 0x34, 0xf1, 0xe0, 0xc1, 0x94, 0x44, 0x0f, 0x9e, 0xc6, 0x76, 0x52, 0x37,
+	0x60, 0x8b, 0xdb, 0x05, 0xc3, 0xbf, 0xf5, 0xa7, 0x98, 0x6e, 0x5b, 0xd9, 0x14, 0x0e, 0xde, 0xf6,
+	0xbd, 0x99, 0x79, 0xf3, 0xe6, 0x65, 0xa1, 0xcf, 0x5b, 0x8e, 0xb5, 0x8c, 0xa3, 0xe9, 0x5a, 0x25,
+	0x3a, 0xc1, 0x4e, 0x44, 0x9a, 0xbf, 0x69, 0x97, 0x7a, 0x6f, 0xe0, 0x3c, 0x64, 0xb5, 0x45, 0xb2,
+	0x51, 0x01, 0x63, 0x1f, 0x6a, 0x32, 0x74, 0xc5, 0x58, 0x4c, 0xba, 0x7e, 0x4d, 0x86, 0x88, 0xd0,
+	0x88, 0xe9, 0x93, 0xdd, 0x9a, 0x61, 0xcc, 0x1b, 0x87, 0xd0, 0xdc, 0xd2, 0x6a, 0xc3, 0x6e, 0x7d,
+	0x2c, 0x26, 0x3d, 0x3f, 0x07, 0x59, 0xa7, 0xde, 0xad, 0xd9, 0x6d, 0xe4, 0x9d, 0xd9, 0xdb, 0x8b,
+	0xa0, 0x69, 0xc4, 0xff, 0x64, 0x84, 0x25, 0xe3, 0x42, 0x7b, 0x4d, 0xbb, 0x55, 0x42, 0xa1, 0x51,
+	0xef, 0xf9, 0x25, 0xc4, 0x31, 0x38, 0x5a, 0x51, 0xc0, 0xcf, 0xa4, 0x38, 0xd6, 0x66, 0x4d, 0xd7,
+	0xb7, 0xa9, 0xc2, 0x66, 0xa3, 0xb4, 0xe9, 0xa5, 0x80, 0xd6, 0x15, 0x3e, 0x7f, 0x6d, 0x38, 0xd5,
+	0x78, 0x03, 0x0e, 0xef, 0x59, 0xb3, 0xdc, 0x99, 0x9f, 0x4d, 0xcb, 0xdb, 0xa7, 0xf6, 0x88, 0xdd,
+	0x89, 0x57, 0x50, 0xa7, 0x60, 0x69, 0x6c, 0x39, 0x73, 0xac, 0x0c, 0xdc, 0x05, 0x4b, 0x3f, 0x2b,
+	0x7b, 0x33, 0xe8, 0x94, 0xc4, 0x41, 0x6e, 0x43, 0x68, 0xb2, 0x52, 0x89, 0x2a, 0x82, 0xcb, 0x81,
+	0x77, 0x0f, 0x83, 0x17, 0x5a, 0xc9, 0xd0, 0x4e, 0xdc, 0x85, 0xb6, 0x4c, 0x0d, 0x6b, 0xc6, 0x3b,
+	0x7e, 0x09, 0xf1, 0x1c, 0x5a, 0x8a, 0x29, 0x4d, 0xe2, 0x42, 0xa4, 0x40, 0xf3, 0x1f, 0x51, 0x2c,
+	0x96, 0x71, 0x84, 0xb7, 0x30, 0x58, 0x68, 0x52, 0xda, 0x96, 0x3c, 0x7e, 0xe2, 0xe8, 0xa4, 0x42,
+	0xcf, 0x04, 0x3e, 0xc1, 0x45, 0x75, 0xfa, 0x55, 0xea, 0x8f, 0xec, 0xa2, 0xcb, 0xe3, 0x39, 0xe5,
+	0xd1, 0x1e, 0x68, 0x4d, 0xc4, 0x4c, 0xe0, 0x23, 0x9c, 0x1a, 0xe7, 0xa4, 0xf9, 0x1f, 0x76, 0x46,
+	0x7b, 0xba, 0x1a, 0xca, 0x7b, 0xcb, 0x7c, 0xd3, 0xeb, 0xdf, 0x01, 0x00, 0x9e, 0x44, 0x80, 0x8d,
+	0xb8, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -235,6 +363,9 @@ const _ = grpc.SupportPackageIsVersion4
 type EventingClient interface {
 	// StartEventSource starts an event source and returns stream of events.
 	StartEventSource(ctx context.Context, in *EventSource, opts ...grpc.CallOption) (Eventing_StartEventSourceClient, error)
+	// StartEventSourceWithAck starts an event source and returns stream of events.
+	// The first request carries the event source, the following requests acknowledge the events that carry an id.
+	StartEventSourceWithAck(ctx context.Context, opts ...grpc.CallOption) (Eventing_StartEventSourceWithAckClient, error)
 	// ValidateEventSource validates an event source.
 	ValidateEventSource(ctx context.Context, in *EventSource, opts ...grpc.CallOption) (*ValidEventSource, error)
 }
@@ -279,6 +410,37 @@ func (x *eventingStartEventSourceClient) Recv() (*Event, error) {
 	return m, nil
 }
 
+func (c *eventingClient) StartEventSourceWithAck(ctx context.Context, opts ...grpc.CallOption) (Eventing_StartEventSourceWithAckClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Eventing_serviceDesc.Streams[1], "/gateways.Eventing/StartEventSourceWithAck", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventingStartEventSourceWithAckClient{stream}
+	return x, nil
+}
+
+type Eventing_StartEventSourceWithAckClient interface {
+	Send(*EventSourceRequest) error
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type eventingStartEventSourceWithAckClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventingStartEventSourceWithAckClient) Send(m *EventSourceRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *eventingStartEventSourceWithAckClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *eventingClient) ValidateEventSource(ctx context.Context, in *EventSource, opts ...grpc.CallOption) (*ValidEventSource, error) {
 	out := new(ValidEventSource)
 	err := c.cc.Invoke(ctx, "/gateways.Eventing/ValidateEventSource", in, out, opts...)
@@ -292,6 +454,9 @@ func (c *eventingClient) ValidateEventSource(ctx context.Context, in *EventSourc
 type EventingServer interface {
 	// StartEventSource starts an event source and returns stream of events.
 	StartEventSource(*EventSource, Eventing_StartEventSourceServer) error
+	// StartEventSourceWithAck starts an event source and returns stream of events.
+	// The first request carries the event source, the following requests acknowledge the events that carry an id.
+	StartEventSourceWithAck(Eventing_StartEventSourceWithAckServer) error
 	// ValidateEventSource validates an event source.
 	ValidateEventSource(context.Context, *EventSource) (*ValidEventSource, error)
 }
@@ -321,6 +486,32 @@ func (x *eventingStartEventSourceServer) Send(m *Event) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Eventing_StartEventSourceWithAck_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EventingServer).StartEventSourceWithAck(&eventingStartEventSourceWithAckServer{stream})
+}
+
+type Eventing_StartEventSourceWithAckServer interface {
+	Send(*Event) error
+	Recv() (*EventSourceRequest, error)
+	grpc.ServerStream
+}
+
+type eventingStartEventSourceWithAckServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventingStartEventSourceWithAckServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *eventingStartEventSourceWithAckServer) Recv() (*EventSourceRequest, error) {
+	m := new(EventSourceRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Eventing_ValidateEventSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(EventSource)
 	if err := dec(in); err != nil {
@@ -354,6 +545,12 @@ var _Eventing_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Eventing_StartEventSource_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StartEventSourceWithAck",
+			Handler:       _Eventing_StartEventSourceWithAck_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "eventing.proto",
 }
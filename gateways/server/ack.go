@@ -0,0 +1,130 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/argoproj/argo-events/gateways"
+)
+
+// EventListener listens to events from an event source
+type EventListener interface {
+	// StartEventSource starts the event source and streams its events
+	StartEventSource(eventSource *gateways.EventSource, eventStream gateways.Eventing_StartEventSourceServer) error
+	// ValidateEventSource validates the event source
+	ValidateEventSource(ctx context.Context, eventSource *gateways.EventSource) (*gateways.ValidEventSource, error)
+}
+
+// Acknowledger is implemented by the event streams on which the gateway client acknowledges the events
+type Acknowledger interface {
+	// ExpectAck registers an event ID to wait the acknowledgement for. The error of the dispatch, nil if the event
+	// was dispatched, is sent on ackCh once the client acknowledges the event. ackCh must be buffered.
+	ExpectAck(id string, ackCh chan<- error)
+}
+
+// eventingServer serves the event listener of a gateway
+type eventingServer struct {
+	EventListener
+}
+
+// NewEventingServer returns the Eventing service of an event listener
+func NewEventingServer(listener EventListener) gateways.EventingServer {
+	return &eventingServer{listener}
+}
+
+// StartEventSourceWithAck starts the event source carried by the first request and routes the acknowledgements
+// carried by the following requests to the events waiting for them.
+func (server *eventingServer) StartEventSourceWithAck(stream gateways.Eventing_StartEventSourceWithAckServer) error {
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if request.EventSource == nil {
+		return errors.New("first request must carry the event source")
+	}
+
+	eventStream := &ackStream{
+		Eventing_StartEventSourceWithAckServer: stream,
+		pending:                                make(map[string]chan<- error),
+	}
+	go eventStream.receiveAcks()
+
+	return server.StartEventSource(request.EventSource, eventStream)
+}
+
+// ackStream is the event stream of an event source started with acknowledgements
+type ackStream struct {
+	gateways.Eventing_StartEventSourceWithAckServer
+	// lock synchronizes pending and closed
+	lock sync.Mutex
+	// pending acknowledgements keyed by the event ID
+	pending map[string]chan<- error
+	// closed is set once the client stops sending acknowledgements
+	closed bool
+}
+
+// ExpectAck registers an event ID to wait the acknowledgement for
+func (stream *ackStream) ExpectAck(id string, ackCh chan<- error) {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+
+	if stream.closed {
+		ackCh <- errors.New("gateway client stopped acknowledging the events")
+		return
+	}
+	stream.pending[id] = ackCh
+}
+
+// receiveAcks delivers the acknowledgements from the client until the stream is closed.
+// The events still waiting for an acknowledgement then fail.
+func (stream *ackStream) receiveAcks() {
+	for {
+		request, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		if request.Ack == nil {
+			continue
+		}
+
+		stream.lock.Lock()
+		ackCh, ok := stream.pending[request.Ack.Id]
+		delete(stream.pending, request.Ack.Id)
+		stream.lock.Unlock()
+
+		if !ok {
+			continue
+		}
+		if request.Ack.Error != "" {
+			ackCh <- errors.New(request.Ack.Error)
+			continue
+		}
+		ackCh <- nil
+	}
+
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+
+	stream.closed = true
+	for id, ackCh := range stream.pending {
+		ackCh <- errors.New("gateway client stopped acknowledging the events")
+		delete(stream.pending, id)
+	}
+}
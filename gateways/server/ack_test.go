@@ -0,0 +1,211 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/gateways"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAckStream struct {
+	FakeGRPCStream
+	requestCh chan *gateways.EventSourceRequest
+	sentCh    chan *gateways.Event
+}
+
+func (f *fakeAckStream) Send(event *gateways.Event) error {
+	f.sentCh <- event
+	return nil
+}
+
+func (f *fakeAckStream) Recv() (*gateways.EventSourceRequest, error) {
+	request, ok := <-f.requestCh
+	if !ok {
+		return nil, io.EOF
+	}
+	return request, nil
+}
+
+type fakeAckListener struct {
+	ackCh chan error
+}
+
+func (listener *fakeAckListener) StartEventSource(eventSource *gateways.EventSource, eventStream gateways.Eventing_StartEventSourceServer) error {
+	eventStream.(Acknowledger).ExpectAck("1", listener.ackCh)
+	if err := eventStream.Send(&gateways.Event{Name: eventSource.Name, Id: "1"}); err != nil {
+		return err
+	}
+	<-eventStream.Context().Done()
+	return nil
+}
+
+func (listener *fakeAckListener) ValidateEventSource(ctx context.Context, eventSource *gateways.EventSource) (*gateways.ValidEventSource, error) {
+	return &gateways.ValidEventSource{IsValid: true}, nil
+}
+
+func TestStartEventSourceWithAck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &fakeAckStream{
+		FakeGRPCStream: FakeGRPCStream{Ctx: ctx},
+		requestCh:      make(chan *gateways.EventSourceRequest, 1),
+		sentCh:         make(chan *gateways.Event, 1),
+	}
+	listener := &fakeAckListener{
+		ackCh: make(chan error, 1),
+	}
+
+	stream.requestCh <- &gateways.EventSourceRequest{
+		EventSource: &gateways.EventSource{Name: "fake"},
+	}
+	go NewEventingServer(listener).StartEventSourceWithAck(stream)
+
+	event := <-stream.sentCh
+	assert.Equal(t, "fake", event.Name)
+	assert.Equal(t, "1", event.Id)
+
+	// acknowledgements of unknown events are ignored
+	stream.requestCh <- &gateways.EventSourceRequest{Ack: &gateways.EventAck{Id: "2"}}
+	stream.requestCh <- &gateways.EventSourceRequest{Ack: &gateways.EventAck{Id: "1", Error: "sensor is down"}}
+
+	select {
+	case err := <-listener.ackCh:
+		assert.EqualError(t, err, "sensor is down")
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not acknowledged")
+	}
+}
+
+func TestAckStream_Closed(t *testing.T) {
+	stream := &ackStream{
+		Eventing_StartEventSourceWithAckServer: &fakeAckStream{
+			requestCh: make(chan *gateways.EventSourceRequest),
+		},
+		pending: make(map[string]chan<- error),
+	}
+
+	ackCh := make(chan error, 1)
+	stream.ExpectAck("1", ackCh)
+	close(stream.Eventing_StartEventSourceWithAckServer.(*fakeAckStream).requestCh)
+	stream.receiveAcks()
+	assert.NotNil(t, <-ackCh)
+
+	// events sent after the client stopped acknowledging fail right away
+	stream.ExpectAck("2", ackCh)
+	assert.NotNil(t, <-ackCh)
+}
+
+func TestStartEventSourceWithAck_NoEventSource(t *testing.T) {
+	stream := &fakeAckStream{
+		requestCh: make(chan *gateways.EventSourceRequest, 1),
+	}
+	stream.requestCh <- &gateways.EventSourceRequest{}
+	assert.NotNil(t, NewEventingServer(&fakeAckListener{}).StartEventSourceWithAck(stream))
+}
+
+func TestHandleMessagesFromEventSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &fakeAckStream{
+		FakeGRPCStream: FakeGRPCStream{Ctx: ctx},
+		requestCh:      make(chan *gateways.EventSourceRequest),
+		sentCh:         make(chan *gateways.Event, 1),
+	}
+	eventStream := &ackStream{
+		Eventing_StartEventSourceWithAckServer: stream,
+		pending:                                make(map[string]chan<- error),
+	}
+	go eventStream.receiveAcks()
+
+	messageCh := make(chan *Message)
+	go HandleMessagesFromEventSource("fake", eventStream, messageCh, make(chan error), make(chan struct{}, 1), common.NewArgoEventsLogger())
+
+	ackCh := make(chan error, 1)
+	messageCh <- &Message{
+		Data: []byte("hello"),
+		Ack: func(err error) {
+			ackCh <- err
+		},
+	}
+
+	event := <-stream.sentCh
+	assert.Equal(t, "hello", string(event.Payload))
+	assert.NotEmpty(t, event.Id)
+
+	// the message is not acknowledged until the client acknowledges the event
+	select {
+	case <-ackCh:
+		t.Fatal("message was acknowledged before the event")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	stream.requestCh <- &gateways.EventSourceRequest{Ack: &gateways.EventAck{Id: event.Id}}
+	select {
+	case err := <-ackCh:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not acknowledged")
+	}
+
+	// the messages still waiting for an acknowledgement fail once the client goes away
+	messageCh <- &Message{
+		Data: []byte("hello"),
+		Ack: func(err error) {
+			ackCh <- err
+		},
+	}
+	<-stream.sentCh
+	close(stream.requestCh)
+	select {
+	case err := <-ackCh:
+		assert.NotNil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not failed")
+	}
+}
+
+func TestHandleMessagesFromEventSource_NoAck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &FakeGRPCStream{Ctx: ctx}
+	messageCh := make(chan *Message)
+	go HandleMessagesFromEventSource("fake", stream, messageCh, make(chan error), make(chan struct{}, 1), common.NewArgoEventsLogger())
+
+	ackCh := make(chan error, 1)
+	messageCh <- &Message{
+		Data: []byte("hello"),
+		Ack: func(err error) {
+			ackCh <- err
+		},
+	}
+	select {
+	case err := <-ackCh:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not acknowledged")
+	}
+	assert.Equal(t, "hello", string(stream.SentData.Payload))
+}
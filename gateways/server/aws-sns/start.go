@@ -106,7 +106,11 @@ func (router *Router) HandleRoute(writer http.ResponseWriter, request *http.Requ
 
 	case messageTypeNotification:
 		logger.Infoln("dispatching notification on route's data channel")
-		route.DataCh <- body
+		if err := route.Dispatch(body); err != nil {
+			logger.WithError(err).Errorln("failed to deliver the notification")
+			webhook.SendDispatchError(writer, err)
+			return
+		}
 	}
 
 	logger.Info("request has been successfully processed")
@@ -67,5 +67,8 @@ func validate(snsEventSource *v1alpha1.SNSEventSource) error {
 	if snsEventSource.Region == "" {
 		return fmt.Errorf("must specify region")
 	}
+	if err := webhook.ValidateNoAuth(snsEventSource.Webhook); err != nil {
+		return err
+	}
 	return webhook.ValidateWebhookContext(snsEventSource.Webhook)
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package aws_sqs
 
 import (
+	"context"
+
 	"github.com/argoproj/argo-events/common"
 	"github.com/argoproj/argo-events/gateways"
 	"github.com/argoproj/argo-events/gateways/server"
@@ -46,16 +48,20 @@ func (listener *EventListener) StartEventSource(eventSource *gateways.EventSourc
 	log := listener.Logger.WithField(common.LabelEventSource, eventSource.Name)
 	log.Info("started processing the event source...")
 
-	dataCh := make(chan []byte)
+	ctx := eventStream.Context()
+
+	messageCh := make(chan *server.Message)
 	errorCh := make(chan error)
 	doneCh := make(chan struct{}, 1)
 
-	go listener.listenEvents(eventSource, dataCh, errorCh, doneCh)
-	return server.HandleEventsFromEventSource(eventSource.Name, eventStream, dataCh, errorCh, doneCh, listener.Logger)
+	go listener.listenEvents(ctx, eventSource, messageCh, errorCh, doneCh)
+	return server.HandleMessagesFromEventSource(eventSource.Name, eventStream, messageCh, errorCh, doneCh, listener.Logger)
 }
 
 // listenEvents fires an event when interval completes and item is processed from queue.
-func (listener *EventListener) listenEvents(eventSource *gateways.EventSource, dataCh chan []byte, errorCh chan error, doneCh chan struct{}) {
+// A message is deleted from the queue only once the gateway client acknowledges it, otherwise it is made visible again.
+// The listener stops once the event source is stopped or the stream to the gateway client is closed, even while a message is pending.
+func (listener *EventListener) listenEvents(ctx context.Context, eventSource *gateways.EventSource, messageCh chan *server.Message, errorCh chan error, doneCh chan struct{}) {
 	var sqsEventSource *v1alpha1.SQSEventSource
 	if err := yaml.Unmarshal(eventSource.Value, &sqsEventSource); err != nil {
 		errorCh <- err
@@ -88,6 +94,9 @@ func (listener *EventListener) listenEvents(eventSource *gateways.EventSource, d
 		case <-doneCh:
 			return
 
+		case <-ctx.Done():
+			return
+
 		default:
 			msg, err := sqsClient.ReceiveMessage(&sqslib.ReceiveMessageInput{
 				QueueUrl:            queueURL.QueueUrl,
@@ -106,16 +115,56 @@ func (listener *EventListener) listenEvents(eventSource *gateways.EventSource, d
 					"message":               *msg.Messages[0].Body,
 				}).Debugln("message from queue")
 
-				dataCh <- []byte(*msg.Messages[0].Body)
-
-				if _, err := sqsClient.DeleteMessage(&sqslib.DeleteMessageInput{
-					QueueUrl:      queueURL.QueueUrl,
-					ReceiptHandle: msg.Messages[0].ReceiptHandle,
-				}); err != nil {
-					errorCh <- err
+				// process one message at a time, the next message is received once this one is acknowledged
+				// a message left unacknowledged is redelivered once its visibility timeout expires
+				ackCh := make(chan struct{})
+				message := &server.Message{
+					Data: []byte(*msg.Messages[0].Body),
+					Ack: func(err error) {
+						defer close(ackCh)
+						listener.commitMessage(sqsClient, queueURL.QueueUrl, msg.Messages[0].ReceiptHandle, err, eventSource.Name)
+					},
+				}
+				select {
+				case messageCh <- message:
+				case <-doneCh:
+					return
+				case <-ctx.Done():
+					return
+				}
+				select {
+				case <-ackCh:
+				case <-doneCh:
+					return
+				case <-ctx.Done():
 					return
 				}
 			}
 		}
 	}
 }
+
+// commitMessage deletes a dispatched message from the queue.
+// A message that failed to dispatch is made visible again right away, so it is redelivered.
+func (listener *EventListener) commitMessage(sqsClient *sqslib.SQS, queueURL *string, receiptHandle *string, dispatchErr error, eventSourceName string) {
+	logger := listener.Logger.WithField(common.LabelEventSource, eventSourceName)
+
+	if dispatchErr != nil {
+		logger.WithError(dispatchErr).Warnln("message was not dispatched, returning it to the queue")
+		if _, err := sqsClient.ChangeMessageVisibility(&sqslib.ChangeMessageVisibilityInput{
+			QueueUrl:          queueURL,
+			ReceiptHandle:     receiptHandle,
+			VisibilityTimeout: aws.Int64(0),
+		}); err != nil {
+			logger.WithError(err).Errorln("failed to return the message to the queue, it will be redelivered once its visibility timeout expires")
+		}
+		return
+	}
+
+	if _, err := sqsClient.DeleteMessage(&sqslib.DeleteMessageInput{
+		QueueUrl:      queueURL,
+		ReceiptHandle: receiptHandle,
+	}); err != nil {
+		logger.WithError(err).Errorln("failed to delete the message from the queue, it will be redelivered once its visibility timeout expires")
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// possible HMAC algorithms
+const (
+	HMACSHA1   = "sha1"
+	HMACSHA256 = "sha256"
+	HMACSHA512 = "sha512"
+)
+
+// possible encodings of the HMAC signature
+const (
+	EncodingHex    = "hex"
+	EncodingBase64 = "base64"
+)
+
+// DefaultSignatureHeader is the header that carries the HMAC signature if none is configured
+const DefaultSignatureHeader = "X-Signature"
+
+// Auth holds the authentication and verification of the requests on a route
+type Auth struct {
+	// HMAC verifies the signature of the request body
+	// +optional
+	HMAC *HMACAuth `json:"hmac,omitempty" protobuf:"bytes,1,opt,name=hmac"`
+	// BearerToken refers to the secret holding the token the requests must carry in the Authorization header
+	// +optional
+	BearerToken *corev1.SecretKeySelector `json:"bearerToken,omitempty" protobuf:"bytes,2,opt,name=bearerToken"`
+	// BasicAuth refers to the secrets holding the username and password of the requests
+	// +optional
+	BasicAuth *BasicAuth `json:"basicAuth,omitempty" protobuf:"bytes,3,opt,name=basicAuth"`
+	// +listType=allowedSourceIPs
+	// AllowedSourceIPs lists the IP addresses and CIDR blocks the requests are accepted from
+	// +optional
+	AllowedSourceIPs []string `json:"allowedSourceIPs,omitempty" protobuf:"bytes,4,rep,name=allowedSourceIPs"`
+	// TrustForwardedFor takes the source IP of the request from the X-Forwarded-For header.
+	// Only enable it behind a proxy that appends to the header.
+	// +optional
+	TrustForwardedFor bool `json:"trustForwardedFor,omitempty" protobuf:"varint,5,opt,name=trustForwardedFor"`
+	// TrustedProxies is the number of proxies in front of the gateway that append to the X-Forwarded-For header.
+	// The source IP is the entry the farthest of them appended, counting from the right, as the client controls the entries on its left.
+	// Defaults to 1.
+	// +optional
+	TrustedProxies int32 `json:"trustedProxies,omitempty" protobuf:"varint,6,opt,name=trustedProxies"`
+}
+
+// HMACAuth verifies the HMAC signature of the request body
+type HMACAuth struct {
+	// Secret refers to the secret holding the key of the HMAC
+	Secret *corev1.SecretKeySelector `json:"secret" protobuf:"bytes,1,name=secret"`
+	// Header carrying the signature. Defaults to X-Signature.
+	// +optional
+	Header string `json:"header,omitempty" protobuf:"bytes,2,opt,name=header"`
+	// Algorithm of the HMAC, one of sha1, sha256 or sha512. Defaults to sha256.
+	// +optional
+	Algorithm string `json:"algorithm,omitempty" protobuf:"bytes,3,opt,name=algorithm"`
+	// Prefix of the signature in the header, e.g. "sha256=" for GitHub style signatures
+	// +optional
+	Prefix string `json:"prefix,omitempty" protobuf:"bytes,4,opt,name=prefix"`
+	// Encoding of the signature, either hex or base64. Defaults to hex.
+	// +optional
+	Encoding string `json:"encoding,omitempty" protobuf:"bytes,5,opt,name=encoding"`
+}
+
+// BasicAuth refers to the credentials of the HTTP basic authentication
+type BasicAuth struct {
+	// Username refers to the secret holding the username
+	Username *corev1.SecretKeySelector `json:"username" protobuf:"bytes,1,name=username"`
+	// Password refers to the secret holding the password
+	Password *corev1.SecretKeySelector `json:"password" protobuf:"bytes,2,name=password"`
+}
+
+// Credentials hold the secrets the requests are verified against
+type Credentials struct {
+	// HMACKey is the key of the HMAC signature
+	HMACKey string
+	// BearerToken is the expected bearer token
+	BearerToken string
+	// Username is the expected basic auth username
+	Username string
+	// Password is the expected basic auth password
+	Password string
+}
+
+// AuthError is the error of a rejected request along with the http status code to respond with
+type AuthError struct {
+	// Code is the http status code, either 401 or 403
+	Code int
+	// Reason the request was rejected
+	Reason string
+}
+
+func (err *AuthError) Error() string {
+	return err.Reason
+}
+
+func unauthorized(format string, args ...interface{}) *AuthError {
+	return &AuthError{Code: http.StatusUnauthorized, Reason: fmt.Sprintf(format, args...)}
+}
+
+func forbidden(format string, args ...interface{}) *AuthError {
+	return &AuthError{Code: http.StatusForbidden, Reason: fmt.Sprintf(format, args...)}
+}
+
+// ValidateAuth validates the authentication of a route
+func ValidateAuth(auth *Auth) error {
+	if auth == nil {
+		return nil
+	}
+	if auth.BearerToken != nil && auth.BasicAuth != nil {
+		return fmt.Errorf("only one of bearer token and basic auth can be specified")
+	}
+	if auth.BasicAuth != nil && (auth.BasicAuth.Username == nil || auth.BasicAuth.Password == nil) {
+		return fmt.Errorf("basic auth must specify both the username and password")
+	}
+	if auth.HMAC != nil {
+		if auth.HMAC.Secret == nil {
+			return fmt.Errorf("hmac secret must be specified")
+		}
+		if _, err := newHash(auth.HMAC.Algorithm); err != nil {
+			return err
+		}
+		switch auth.HMAC.Encoding {
+		case "", EncodingHex, EncodingBase64:
+		default:
+			return fmt.Errorf("unknown hmac signature encoding %s", auth.HMAC.Encoding)
+		}
+	}
+	if auth.TrustedProxies < 0 {
+		return fmt.Errorf("trusted proxies can't be negative")
+	}
+	for _, source := range auth.AllowedSourceIPs {
+		if _, _, err := net.ParseCIDR(source); err != nil && net.ParseIP(source) == nil {
+			return fmt.Errorf("allowed source %s is neither an IP address nor a CIDR block", source)
+		}
+	}
+	return nil
+}
+
+// VerifySource rejects the request with 403 if it doesn't come from an allowed source
+func (auth *Auth) VerifySource(request *http.Request) *AuthError {
+	if auth == nil || len(auth.AllowedSourceIPs) == 0 {
+		return nil
+	}
+	ip := sourceIP(request, auth.TrustForwardedFor, int(auth.TrustedProxies))
+	if ip == nil {
+		return forbidden("source of the request is unknown")
+	}
+	for _, source := range auth.AllowedSourceIPs {
+		if _, network, err := net.ParseCIDR(source); err == nil {
+			if network.Contains(ip) {
+				return nil
+			}
+			continue
+		}
+		if allowed := net.ParseIP(source); allowed != nil && allowed.Equal(ip) {
+			return nil
+		}
+	}
+	return forbidden("source %s is not allowed", ip)
+}
+
+// VerifyRequest checks the credentials and the signature of the request. Missing credentials are rejected with 401,
+// wrong ones with 403.
+func (auth *Auth) VerifyRequest(request *http.Request, body []byte, credentials *Credentials) *AuthError {
+	if auth == nil {
+		return nil
+	}
+	if auth.BearerToken != nil {
+		header := request.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			return unauthorized("bearer token is missing")
+		}
+		if !equal(strings.TrimPrefix(header, "Bearer "), credentials.BearerToken) {
+			return forbidden("bearer token is invalid")
+		}
+	}
+	if auth.BasicAuth != nil {
+		username, password, ok := request.BasicAuth()
+		if !ok {
+			return unauthorized("basic auth credentials are missing")
+		}
+		// check both so the response doesn't tell which one is wrong
+		validUsername := equal(username, credentials.Username)
+		validPassword := equal(password, credentials.Password)
+		if !validUsername || !validPassword {
+			return forbidden("basic auth credentials are invalid")
+		}
+	}
+	if auth.HMAC != nil {
+		return auth.HMAC.verify(request, body, credentials.HMACKey)
+	}
+	return nil
+}
+
+// Challenge returns the WWW-Authenticate header value of a 401 response, if any
+func (auth *Auth) Challenge() string {
+	switch {
+	case auth == nil:
+		return ""
+	case auth.BearerToken != nil:
+		return "Bearer"
+	case auth.BasicAuth != nil:
+		return `Basic realm="argo-events"`
+	default:
+		return ""
+	}
+}
+
+// verify checks the signature of the body against the HMAC of the key
+func (hmacAuth *HMACAuth) verify(request *http.Request, body []byte, key string) *AuthError {
+	header := hmacAuth.Header
+	if header == "" {
+		header = DefaultSignatureHeader
+	}
+	value := request.Header.Get(header)
+	if value == "" {
+		return unauthorized("signature header %s is missing", header)
+	}
+	if !strings.HasPrefix(value, hmacAuth.Prefix) {
+		return forbidden("signature is invalid")
+	}
+	value = strings.TrimPrefix(value, hmacAuth.Prefix)
+
+	var signature []byte
+	var err error
+	if hmacAuth.Encoding == EncodingBase64 {
+		signature, err = base64.StdEncoding.DecodeString(value)
+	} else {
+		signature, err = hex.DecodeString(value)
+	}
+	if err != nil {
+		return forbidden("signature is invalid")
+	}
+
+	hashFunc, err := newHash(hmacAuth.Algorithm)
+	if err != nil {
+		return forbidden("%s", err)
+	}
+	mac := hmac.New(hashFunc, []byte(key))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return forbidden("signature is invalid")
+	}
+	return nil
+}
+
+// newHash returns the hash function of the HMAC algorithm
+func newHash(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case HMACSHA1:
+		return sha1.New, nil
+	case "", HMACSHA256:
+		return sha256.New, nil
+	case HMACSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unknown hmac algorithm %s", algorithm)
+	}
+}
+
+// sourceIP returns the IP address the request came from. If the X-Forwarded-For header is trusted, the source is the entry
+// appended by the farthest of the trusted proxies, i.e. the entry at trustedProxies from the right, nil if the header is shorter.
+func sourceIP(request *http.Request, trustForwardedFor bool, trustedProxies int) net.IP {
+	if trustForwardedFor {
+		if values := request.Header["X-Forwarded-For"]; len(values) > 0 {
+			if trustedProxies <= 0 {
+				trustedProxies = 1
+			}
+			// the proxies append to the last header if the request has several
+			entries := strings.Split(strings.Join(values, ","), ",")
+			if len(entries) < trustedProxies {
+				return nil
+			}
+			return net.ParseIP(strings.TrimSpace(entries[len(entries)-trustedProxies]))
+		}
+	}
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// equal compares the secrets in constant time
+func equal(actual, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func secretKeySelector(name, key string) *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	assert.Nil(t, ValidateAuth(nil))
+	assert.Nil(t, ValidateAuth(&Auth{
+		HMAC:             &HMACAuth{Secret: secretKeySelector("webhook", "hmac"), Algorithm: HMACSHA512, Encoding: EncodingBase64},
+		BearerToken:      secretKeySelector("webhook", "token"),
+		AllowedSourceIPs: []string{"10.0.0.0/8", "192.168.1.1", "::1"},
+	}))
+
+	assert.NotNil(t, ValidateAuth(&Auth{
+		BearerToken: secretKeySelector("webhook", "token"),
+		BasicAuth:   &BasicAuth{Username: secretKeySelector("webhook", "username"), Password: secretKeySelector("webhook", "password")},
+	}))
+	assert.NotNil(t, ValidateAuth(&Auth{BasicAuth: &BasicAuth{Username: secretKeySelector("webhook", "username")}}))
+	assert.NotNil(t, ValidateAuth(&Auth{HMAC: &HMACAuth{}}))
+	assert.NotNil(t, ValidateAuth(&Auth{HMAC: &HMACAuth{Secret: secretKeySelector("webhook", "hmac"), Algorithm: "md5"}}))
+	assert.NotNil(t, ValidateAuth(&Auth{HMAC: &HMACAuth{Secret: secretKeySelector("webhook", "hmac"), Encoding: "base32"}}))
+	assert.NotNil(t, ValidateAuth(&Auth{AllowedSourceIPs: []string{"10.0.0.0/33"}}))
+	assert.NotNil(t, ValidateAuth(&Auth{TrustForwardedFor: true, TrustedProxies: -1}))
+}
+
+func TestValidateNoAuth(t *testing.T) {
+	assert.Nil(t, ValidateNoAuth(nil))
+	assert.Nil(t, ValidateNoAuth(&Context{}))
+	assert.NotNil(t, ValidateNoAuth(&Context{Auth: &Auth{BearerToken: secretKeySelector("webhook", "token")}}))
+}
+
+func TestVerifySource(t *testing.T) {
+	var auth *Auth
+	request := httptest.NewRequest(http.MethodPost, "/example", nil)
+	assert.Nil(t, auth.VerifySource(request))
+
+	auth = &Auth{AllowedSourceIPs: []string{"10.0.0.0/8", "192.168.1.1"}}
+	request.RemoteAddr = "10.1.2.3:41234"
+	assert.Nil(t, auth.VerifySource(request))
+	request.RemoteAddr = "192.168.1.1:41234"
+	assert.Nil(t, auth.VerifySource(request))
+	request.RemoteAddr = "192.168.1.2:41234"
+	err := auth.VerifySource(request)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+
+	// the forwarded header is ignored unless trusted
+	request.RemoteAddr = "172.17.0.5:41234"
+	request.Header.Set("X-Forwarded-For", "192.168.1.2, 10.1.2.3")
+	assert.NotNil(t, auth.VerifySource(request))
+	auth.TrustForwardedFor = true
+	assert.Nil(t, auth.VerifySource(request))
+
+	// a client spoofing the header through the proxy only controls the entries left of the one the proxy appends
+	request.Header.Set("X-Forwarded-For", "10.1.2.3, 192.168.1.2")
+	err = auth.VerifySource(request)
+	assert.NotNil(t, err)
+	assert.Equal(t, "source 192.168.1.2 is not allowed", err.Reason)
+	request.Header["X-Forwarded-For"] = []string{"10.1.2.3", "192.168.1.2"}
+	assert.NotNil(t, auth.VerifySource(request))
+
+	// behind two proxies, the source is the entry the farthest proxy appended
+	auth.TrustedProxies = 2
+	request.Header.Set("X-Forwarded-For", "192.168.1.2, 10.1.2.3, 172.17.0.1")
+	assert.Nil(t, auth.VerifySource(request))
+	request.Header.Set("X-Forwarded-For", "10.1.2.3, 192.168.1.2, 172.17.0.1")
+	assert.NotNil(t, auth.VerifySource(request))
+	request.Header.Set("X-Forwarded-For", "10.1.2.3")
+	assert.NotNil(t, auth.VerifySource(request))
+}
+
+func TestVerifyRequest(t *testing.T) {
+	body := []byte(`{"hello": "world"}`)
+	credentials := &Credentials{
+		HMACKey:     "hmac-key",
+		BearerToken: "token",
+		Username:    "user",
+		Password:    "password",
+	}
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/example", strings.NewReader(string(body)))
+	}
+
+	var auth *Auth
+	assert.Nil(t, auth.VerifyRequest(newRequest(), body, credentials))
+
+	// bearer token
+	auth = &Auth{BearerToken: secretKeySelector("webhook", "token")}
+	assert.Equal(t, "Bearer", auth.Challenge())
+	request := newRequest()
+	err := auth.VerifyRequest(request, body, credentials)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.Code)
+	request.Header.Set("Authorization", "Bearer wrong")
+	err = auth.VerifyRequest(request, body, credentials)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+	request.Header.Set("Authorization", "Bearer token")
+	assert.Nil(t, auth.VerifyRequest(request, body, credentials))
+
+	// basic auth
+	auth = &Auth{BasicAuth: &BasicAuth{Username: secretKeySelector("webhook", "username"), Password: secretKeySelector("webhook", "password")}}
+	request = newRequest()
+	err = auth.VerifyRequest(request, body, credentials)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.Code)
+	request.SetBasicAuth("user", "wrong")
+	err = auth.VerifyRequest(request, body, credentials)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+	request.SetBasicAuth("user", "password")
+	assert.Nil(t, auth.VerifyRequest(request, body, credentials))
+
+	// hmac with the defaults and a github style prefix
+	mac := hmac.New(sha256.New, []byte("hmac-key"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	auth = &Auth{HMAC: &HMACAuth{Secret: secretKeySelector("webhook", "hmac"), Header: "X-Hub-Signature-256", Prefix: "sha256="}}
+	assert.Equal(t, "", auth.Challenge())
+	request = newRequest()
+	err = auth.VerifyRequest(request, body, credentials)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.Code)
+	request.Header.Set("X-Hub-Signature-256", signature)
+	err = auth.VerifyRequest(request, body, credentials)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+	request.Header.Set("X-Hub-Signature-256", "sha256="+signature)
+	assert.Nil(t, auth.VerifyRequest(request, body, credentials))
+	err = auth.VerifyRequest(request, []byte("tampered"), credentials)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, err.Code)
+
+	// hmac with sha1 and base64 encoding
+	mac = hmac.New(sha1.New, []byte("hmac-key"))
+	mac.Write(body)
+	auth = &Auth{HMAC: &HMACAuth{Secret: secretKeySelector("webhook", "hmac"), Algorithm: HMACSHA1, Encoding: EncodingBase64}}
+	request = newRequest()
+	request.Header.Set(DefaultSignatureHeader, base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	assert.Nil(t, auth.VerifyRequest(request, body, credentials))
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// Limits caps the requests accepted on a route
+type Limits struct {
+	// RequestsPerSecond is the rate at which the token bucket of the route refills.
+	// Requests are rejected with 429 when the bucket is empty. No rate limit applies if it is not set.
+	// +optional
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty" protobuf:"fixed64,1,opt,name=requestsPerSecond"`
+	// Burst is the size of the token bucket. Defaults to the requests per second rounded up.
+	// +optional
+	Burst int32 `json:"burst,omitempty" protobuf:"varint,2,opt,name=burst"`
+	// MaxBodySize is the maximum size in bytes of the request body. Larger requests are rejected with 413.
+	// +optional
+	MaxBodySize int64 `json:"maxBodySize,omitempty" protobuf:"varint,3,opt,name=maxBodySize"`
+	// MaxConcurrentRequests is the maximum number of requests the route processes at once.
+	// Requests beyond it are rejected with 429.
+	// +optional
+	MaxConcurrentRequests int32 `json:"maxConcurrentRequests,omitempty" protobuf:"varint,4,opt,name=maxConcurrentRequests"`
+}
+
+// ValidateLimits validates the limits of a route
+func ValidateLimits(limits *Limits) error {
+	if limits == nil {
+		return nil
+	}
+	if limits.RequestsPerSecond < 0 {
+		return fmt.Errorf("requests per second can't be negative")
+	}
+	if limits.Burst < 0 {
+		return fmt.Errorf("burst can't be negative")
+	}
+	if limits.Burst > 0 && limits.RequestsPerSecond == 0 {
+		return fmt.Errorf("burst requires requests per second")
+	}
+	if limits.MaxBodySize < 0 {
+		return fmt.Errorf("max body size can't be negative")
+	}
+	if limits.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("max concurrent requests can't be negative")
+	}
+	return nil
+}
+
+// limiter enforces the limits of a route in front of its handler
+type limiter struct {
+	// bucket is the token bucket of the rate limit, nil if the rate is unlimited
+	bucket *rate.Limiter
+	// slots hold a token per request in process, nil if the concurrency is unlimited
+	slots chan struct{}
+	// maxBodySize is the maximum size of the request body, 0 if unlimited
+	maxBodySize int64
+}
+
+// newLimiter returns the limiter of the limits
+func newLimiter(limits *Limits) *limiter {
+	l := &limiter{}
+	if limits == nil {
+		return l
+	}
+	if limits.RequestsPerSecond > 0 {
+		burst := int(limits.Burst)
+		if burst == 0 {
+			burst = int(math.Ceil(limits.RequestsPerSecond))
+		}
+		l.bucket = rate.NewLimiter(rate.Limit(limits.RequestsPerSecond), burst)
+	}
+	if limits.MaxConcurrentRequests > 0 {
+		l.slots = make(chan struct{}, limits.MaxConcurrentRequests)
+	}
+	l.maxBodySize = limits.MaxBodySize
+	return l
+}
+
+// wrap returns a handler that rejects the requests beyond the limits and hands the others to handle
+func (l *limiter) wrap(handle http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if l.bucket != nil && !l.bucket.Allow() {
+			sendLimitResponse(writer, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		if l.slots != nil {
+			select {
+			case l.slots <- struct{}{}:
+				defer func() {
+					<-l.slots
+				}()
+			default:
+				sendLimitResponse(writer, http.StatusTooManyRequests, "too many concurrent requests")
+				return
+			}
+		}
+
+		if l.maxBodySize > 0 && request.Body != nil {
+			if request.ContentLength > l.maxBodySize {
+				sendLimitResponse(writer, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+			// read one byte past the limit to tell bodies of unknown length that are too large
+			body, err := ioutil.ReadAll(io.LimitReader(request.Body, l.maxBodySize+1))
+			if err != nil {
+				sendLimitResponse(writer, http.StatusBadRequest, err.Error())
+				return
+			}
+			if int64(len(body)) > l.maxBodySize {
+				sendLimitResponse(writer, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+			request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		handle(writer, request)
+	}
+}
+
+// sendLimitResponse rejects a request that hit a limit of the route
+func sendLimitResponse(writer http.ResponseWriter, status int, response string) {
+	if status == http.StatusTooManyRequests {
+		writer.Header().Set("Retry-After", "1")
+	}
+	writer.WriteHeader(status)
+	writer.Write([]byte(response))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLimits(t *testing.T) {
+	assert.Nil(t, ValidateLimits(nil))
+	assert.Nil(t, ValidateLimits(&Limits{RequestsPerSecond: 0.5, Burst: 2, MaxBodySize: 1024, MaxConcurrentRequests: 10}))
+	assert.NotNil(t, ValidateLimits(&Limits{RequestsPerSecond: -1}))
+	assert.NotNil(t, ValidateLimits(&Limits{Burst: 2}))
+	assert.NotNil(t, ValidateLimits(&Limits{MaxBodySize: -1}))
+	assert.NotNil(t, ValidateLimits(&Limits{MaxConcurrentRequests: -1}))
+}
+
+func TestLimiter_RateLimit(t *testing.T) {
+	handler := newLimiter(&Limits{RequestsPerSecond: 0.001, Burst: 2}).wrap(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	for i := 0; i < 2; i++ {
+		writer := httptest.NewRecorder()
+		handler(writer, httptest.NewRequest(http.MethodPost, "/fake", nil))
+		assert.Equal(t, http.StatusOK, writer.Code)
+	}
+
+	writer := httptest.NewRecorder()
+	handler(writer, httptest.NewRequest(http.MethodPost, "/fake", nil))
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.Equal(t, "1", writer.Header().Get("Retry-After"))
+}
+
+func TestLimiter_MaxConcurrentRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := newLimiter(&Limits{MaxConcurrentRequests: 1}).wrap(func(writer http.ResponseWriter, request *http.Request) {
+		started <- struct{}{}
+		<-release
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	first := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		handler(first, httptest.NewRequest(http.MethodPost, "/fake", nil))
+	}()
+	<-started
+
+	writer := httptest.NewRecorder()
+	handler(writer, httptest.NewRequest(http.MethodPost, "/fake", nil))
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	// the slot is free again once the first request is done
+	go func() {
+		<-started
+	}()
+	writer = httptest.NewRecorder()
+	handler(writer, httptest.NewRequest(http.MethodPost, "/fake", nil))
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestLimiter_MaxBodySize(t *testing.T) {
+	handler := newLimiter(&Limits{MaxBodySize: 5}).wrap(func(writer http.ResponseWriter, request *http.Request) {
+		body, err := ioutil.ReadAll(request.Body)
+		assert.Nil(t, err)
+		writer.WriteHeader(http.StatusOK)
+		writer.Write(body)
+	})
+
+	writer := httptest.NewRecorder()
+	handler(writer, httptest.NewRequest(http.MethodPost, "/fake", strings.NewReader("hello")))
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "hello", writer.Body.String())
+
+	writer = httptest.NewRecorder()
+	handler(writer, httptest.NewRequest(http.MethodPost, "/fake", strings.NewReader("hello world")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, writer.Code)
+
+	// a body of unknown length is measured while reading it
+	request := httptest.NewRequest(http.MethodPost, "/fake", strings.NewReader("hello world"))
+	request.ContentLength = -1
+	writer = httptest.NewRecorder()
+	handler(writer, request)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, writer.Code)
+}
+
+func TestLimiter_NoLimits(t *testing.T) {
+	handler := newLimiter(nil).wrap(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+	for i := 0; i < 10; i++ {
+		writer := httptest.NewRecorder()
+		handler(writer, httptest.NewRequest(http.MethodPost, "/fake", strings.NewReader("hello")))
+		assert.Equal(t, http.StatusOK, writer.Code)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// ServerShutdownTimeout is the time a http server waits for the in-flight requests to drain
+// once the last route on its port is removed.
+const ServerShutdownTimeout = 10 * time.Second
+
+// server is a http server listening on a port along with the routes registered with it
+type server struct {
+	httpServer *http.Server
+	// certPath and keyPath are the TLS settings the server was started with
+	certPath string
+	keyPath  string
+	// handler is the mux/router built from the currently registered routes
+	handler *mux.Router
+	// registrations keyed by the route key
+	registrations map[string]*registration
+	// stopped is closed once the server is shut down and has released the port
+	stopped chan struct{}
+}
+
+// registration is a route registered with a server
+type registration struct {
+	router Router
+	// handle is the handler of the route wrapped with its limits.
+	// It lives as long as the registration so that the limits are not reset when the mux/router is rebuilt.
+	handle http.HandlerFunc
+}
+
+// newServer returns a server for the port and TLS settings of the route
+func newServer(route *Route) *server {
+	srv := &server{
+		certPath:      route.Context.ServerCertPath,
+		keyPath:       route.Context.ServerKeyPath,
+		handler:       mux.NewRouter(),
+		registrations: make(map[string]*registration),
+		stopped:       make(chan struct{}),
+	}
+	srv.httpServer = &http.Server{
+		Addr:    fmt.Sprintf(":%s", route.Context.Port),
+		Handler: srv,
+	}
+	return srv
+}
+
+// routeKey returns the key of a route. A route is identified by its port, endpoint and method.
+func routeKey(route *Route) string {
+	return fmt.Sprintf("%s %s %s", route.Context.Port, route.Context.Method, route.Context.Endpoint)
+}
+
+// ServeHTTP dispatches the request to the current mux/router of the server
+func (srv *server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	Lock.Lock()
+	handler := srv.handler
+	Lock.Unlock()
+	handler.ServeHTTP(writer, request)
+}
+
+// serve listens on the port of the server until it is shut down. It returns the error of the server if it fails otherwise,
+// e.g. if the port is already in use.
+func (srv *server) serve(log *logrus.Entry) error {
+	var err error
+	if srv.certPath == "" || srv.keyPath == "" {
+		err = srv.httpServer.ListenAndServe()
+	} else {
+		err = srv.httpServer.ListenAndServeTLS(srv.certPath, srv.keyPath)
+	}
+	if err == http.ErrServerClosed {
+		log.Info("http server stopped")
+		return nil
+	}
+	log.WithError(err).Errorln("failed to listen and serve")
+	return err
+}
+
+// shutdown stops the server after draining the in-flight requests
+func (srv *server) shutdown(log *logrus.Entry) {
+	defer close(srv.stopped)
+	ctx, cancel := context.WithTimeout(context.Background(), ServerShutdownTimeout)
+	defer cancel()
+	if err := srv.httpServer.Shutdown(ctx); err != nil {
+		log.WithError(err).Warnln("in-flight requests did not drain, closing the http server")
+		srv.httpServer.Close()
+	}
+}
+
+// register adds the route to the server. A route with the same key is replaced.
+// The caller must hold the lock.
+func (srv *server) register(router Router) {
+	route := router.GetRoute()
+	srv.registrations[routeKey(route)] = &registration{
+		router: router,
+		handle: newLimiter(route.Context.Limits).wrap(router.HandleRoute),
+	}
+	srv.rebuild()
+}
+
+// unregister removes the route from the server and reports whether it was registered.
+// A route that has been replaced by another route with the same key is left alone.
+// The caller must hold the lock.
+func (srv *server) unregister(router Router) bool {
+	key := routeKey(router.GetRoute())
+	if reg, ok := srv.registrations[key]; !ok || reg.router != router {
+		return false
+	}
+	delete(srv.registrations, key)
+	srv.rebuild()
+	return true
+}
+
+// rebuild replaces the mux/router of the server with one that only serves the registered routes.
+// gorilla/mux can't remove a route, so the mux/router is built afresh on every change.
+func (srv *server) rebuild() {
+	keys := make([]string, 0, len(srv.registrations))
+	for key := range srv.registrations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	handler := mux.NewRouter()
+	for _, key := range keys {
+		reg := srv.registrations[key]
+		route := reg.router.GetRoute()
+		handler.HandleFunc(route.Context.Endpoint, reg.handle).Methods(route.Context.Method)
+	}
+	srv.handler = handler
+}
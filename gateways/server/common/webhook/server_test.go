@@ -0,0 +1,234 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/gateways"
+	"github.com/stretchr/testify/assert"
+)
+
+type statusRouter struct {
+	FakeRouter
+	status int
+}
+
+func (s *statusRouter) HandleRoute(writer http.ResponseWriter, request *http.Request) {
+	writer.WriteHeader(s.status)
+}
+
+func newStatusRouter(port, endpoint, method string, status int) *statusRouter {
+	route := NewRoute(&Context{
+		Endpoint: endpoint,
+		Method:   method,
+		Port:     port,
+	}, common.NewArgoEventsLogger(), &gateways.EventSource{
+		Name: fmt.Sprintf("%s-%s", method, endpoint),
+	})
+	return &statusRouter{
+		FakeRouter: FakeRouter{route: route},
+		status:     status,
+	}
+}
+
+func freePort(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	return fmt.Sprintf("%d", listener.Addr().(*net.TCPAddr).Port)
+}
+
+func serve(controller *Controller, port, endpoint, method string) int {
+	Lock.Lock()
+	srv := controller.servers[port]
+	Lock.Unlock()
+	writer := httptest.NewRecorder()
+	srv.ServeHTTP(writer, httptest.NewRequest(method, endpoint, nil))
+	return writer.Code
+}
+
+func TestStopRoute(t *testing.T) {
+	controller := NewController()
+	port := freePort(t)
+
+	first := newStatusRouter(port, "/first", http.MethodPost, http.StatusOK)
+	second := newStatusRouter(port, "/second", http.MethodPost, http.StatusAccepted)
+	startServer(first, controller)
+	startServer(second, controller)
+	assert.Len(t, controller.ActiveRoutes, 2)
+	assert.Equal(t, http.StatusOK, serve(controller, port, "/first", http.MethodPost))
+	assert.Equal(t, http.StatusAccepted, serve(controller, port, "/second", http.MethodPost))
+
+	first.route.Active = true
+	stopRoute(first, controller)
+	assert.False(t, first.route.Active)
+	assert.Len(t, controller.ActiveRoutes, 1)
+	assert.Equal(t, http.StatusNotFound, serve(controller, port, "/first", http.MethodPost))
+	assert.Equal(t, http.StatusAccepted, serve(controller, port, "/second", http.MethodPost))
+
+	// the same endpoint can be registered again with a new method
+	first = newStatusRouter(port, "/first", http.MethodPut, http.StatusCreated)
+	startServer(first, controller)
+	assert.Equal(t, http.StatusCreated, serve(controller, port, "/first", http.MethodPut))
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(controller, port, "/first", http.MethodPost))
+
+	stopRoute(first, controller)
+	stopRoute(second, controller)
+	assert.Empty(t, controller.ActiveRoutes)
+	assert.Empty(t, controller.servers)
+	assert.Empty(t, controller.ActiveServerHandlers)
+}
+
+func TestStopRoute_Replaced(t *testing.T) {
+	controller := NewController()
+	port := freePort(t)
+
+	first := newStatusRouter(port, "/fake", http.MethodPost, http.StatusOK)
+	second := newStatusRouter(port, "/fake", http.MethodPost, http.StatusAccepted)
+	startServer(first, controller)
+	startServer(second, controller)
+	assert.Equal(t, http.StatusAccepted, serve(controller, port, "/fake", http.MethodPost))
+
+	// stopping the replaced route leaves the route that replaced it alone
+	stopRoute(first, controller)
+	assert.Len(t, controller.ActiveRoutes, 1)
+	assert.Equal(t, http.StatusAccepted, serve(controller, port, "/fake", http.MethodPost))
+
+	stopRoute(second, controller)
+	assert.Empty(t, controller.servers)
+}
+
+func TestStopRoute_Shutdown(t *testing.T) {
+	controller := NewController()
+	port := freePort(t)
+	url := fmt.Sprintf("http://127.0.0.1:%s/fake", port)
+
+	router := newStatusRouter(port, "/fake", http.MethodPost, http.StatusOK)
+	startServer(router, controller)
+	assert.Equal(t, http.StatusOK, post(t, url))
+
+	Lock.Lock()
+	srv := controller.servers[port]
+	Lock.Unlock()
+	stopRoute(router, controller)
+	select {
+	case <-srv.stopped:
+	case <-time.After(ServerShutdownTimeout):
+		t.Fatal("http server did not stop")
+	}
+	_, err := http.Post(url, "application/json", nil)
+	assert.NotNil(t, err)
+
+	// the port is served again by a fresh server
+	router = newStatusRouter(port, "/fake", http.MethodPost, http.StatusAccepted)
+	startServer(router, controller)
+	assert.Equal(t, http.StatusAccepted, post(t, url))
+	stopRoute(router, controller)
+}
+
+func TestProcessRouteStatus_Draining(t *testing.T) {
+	controller := NewController()
+	go ProcessRouteStatus(controller)
+	port := freePort(t)
+
+	// a server still drains the port
+	stopping := &server{stopped: make(chan struct{})}
+	Lock.Lock()
+	controller.stoppingServers[port] = stopping
+	Lock.Unlock()
+
+	activated := make(chan struct{})
+	router := newStatusRouter(port, "/fake", http.MethodPost, http.StatusOK)
+	go func() {
+		activateRoute(router, controller)
+		close(activated)
+	}()
+
+	// the routes on the other ports are activated meanwhile
+	other := newStatusRouter(freePort(t), "/other", http.MethodPost, http.StatusOK)
+	otherActivated := make(chan struct{})
+	go func() {
+		activateRoute(other, controller)
+		close(otherActivated)
+	}()
+	select {
+	case <-otherActivated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("route on another port is not activated")
+	}
+	select {
+	case <-activated:
+		t.Fatal("route is activated before the previous server released the port")
+	default:
+	}
+
+	close(stopping.stopped)
+	select {
+	case <-activated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("route is not activated once the previous server released the port")
+	}
+	assert.Equal(t, http.StatusOK, post(t, fmt.Sprintf("http://127.0.0.1:%s/fake", port)))
+	stopRoute(router, controller)
+	stopRoute(other, controller)
+}
+
+func TestStartServer_ListenError(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	port := fmt.Sprintf("%d", listener.Addr().(*net.TCPAddr).Port)
+
+	controller := NewController()
+	router := newStatusRouter(port, "/fake", http.MethodPost, http.StatusOK)
+	assert.Nil(t, startServer(router, controller))
+
+	// the route fails and the server is removed, so the port can be served again
+	select {
+	case err := <-router.route.failCh:
+		assert.NotNil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("route did not fail")
+	}
+	Lock.Lock()
+	defer Lock.Unlock()
+	assert.Empty(t, controller.servers)
+	assert.Empty(t, controller.ActiveServerHandlers)
+	assert.Empty(t, controller.ActiveRoutes)
+}
+
+func post(t *testing.T, url string) int {
+	var err error
+	for i := 0; i < 50; i++ {
+		var response *http.Response
+		response, err = http.Post(url, "application/json", nil)
+		if err == nil {
+			response.Body.Close()
+			return response.StatusCode
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Nil(t, err)
+	return 0
+}
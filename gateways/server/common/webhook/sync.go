@@ -0,0 +1,124 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"errors"
+)
+
+// DefaultSyncResponseTimeout is the time a synchronous route waits for the delivery of an event if no timeout is set
+const DefaultSyncResponseTimeout = 30 * time.Second
+
+// ErrDeliveryTimeout is returned when the delivery of an event is not acknowledged in time
+var ErrDeliveryTimeout = errors.New("timed out waiting for the delivery of the event")
+
+// ErrRouteInactive is returned when the data is dispatched to a route that no longer consumes it
+var ErrRouteInactive = errors.New("route is inactive")
+
+// SyncResponse holds the response of a route to the requests until their events are delivered to the sensors
+type SyncResponse struct {
+	// Timeout is the time to wait for the delivery of the event, e.g. 10s. Defaults to 30s.
+	// +optional
+	Timeout string `json:"timeout,omitempty" protobuf:"bytes,1,opt,name=timeout"`
+}
+
+// ValidateSyncResponse validates the synchronous response of a route
+func ValidateSyncResponse(syncResponse *SyncResponse) error {
+	if syncResponse == nil || syncResponse.Timeout == "" {
+		return nil
+	}
+	timeout, err := time.ParseDuration(syncResponse.Timeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse the sync response timeout %s. err: %+v", syncResponse.Timeout, err)
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("sync response timeout must be positive")
+	}
+	return nil
+}
+
+// timeout returns the time to wait for the delivery of an event
+func (syncResponse *SyncResponse) timeout() time.Duration {
+	if timeout, err := time.ParseDuration(syncResponse.Timeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return DefaultSyncResponseTimeout
+}
+
+// event is an event received on a synchronous route
+type event struct {
+	data []byte
+	// ackCh receives the result of the delivery of the event
+	ackCh chan error
+}
+
+// Dispatch hands the data received on the route over to the gateway client.
+// It returns ErrRouteInactive if the route stops before it takes the data.
+// If the route responds synchronously, Dispatch waits until the gateway client acknowledges
+// the delivery of the event to the sensors and returns the error of the delivery, if any.
+func (route *Route) Dispatch(data []byte) error {
+	if route.Context.SyncResponse == nil {
+		select {
+		case route.DataCh <- data:
+			return nil
+		case <-route.doneCh:
+			return ErrRouteInactive
+		}
+	}
+
+	timer := time.NewTimer(route.Context.SyncResponse.timeout())
+	defer timer.Stop()
+
+	e := &event{
+		data:  data,
+		ackCh: make(chan error, 1),
+	}
+
+	select {
+	case route.eventCh <- e:
+	case <-route.doneCh:
+		return ErrRouteInactive
+	case <-timer.C:
+		return ErrDeliveryTimeout
+	}
+
+	select {
+	case err := <-e.ackCh:
+		return err
+	case <-timer.C:
+		return ErrDeliveryTimeout
+	}
+}
+
+// SendDispatchError responds to a request whose event was not delivered.
+// It responds with 504 if the delivery timed out, with 503 if the route is inactive and with 502 otherwise,
+// so the caller can retry the request.
+func SendDispatchError(writer http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	switch err {
+	case ErrDeliveryTimeout:
+		status = http.StatusGatewayTimeout
+	case ErrRouteInactive:
+		status = http.StatusServiceUnavailable
+	}
+	writer.WriteHeader(status)
+	writer.Write([]byte(err.Error()))
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/gateways"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+type fakeAckStream struct {
+	ctx    context.Context
+	sentCh chan *gateways.Event
+	acks   map[string]chan<- error
+}
+
+func (f *fakeAckStream) Send(event *gateways.Event) error {
+	f.sentCh <- event
+	return nil
+}
+
+func (f *fakeAckStream) ExpectAck(id string, ackCh chan<- error) {
+	f.acks[id] = ackCh
+}
+
+func (f *fakeAckStream) SetHeader(metadata.MD) error  { return nil }
+func (f *fakeAckStream) SendHeader(metadata.MD) error { return nil }
+func (f *fakeAckStream) SetTrailer(metadata.MD)       {}
+func (f *fakeAckStream) Context() context.Context     { return f.ctx }
+func (f *fakeAckStream) SendMsg(m interface{}) error  { return nil }
+func (f *fakeAckStream) RecvMsg(m interface{}) error  { return nil }
+
+func newSyncRoute(timeout string) *Route {
+	route := GetFakeRoute()
+	route.Context = &Context{
+		Endpoint:     "/fake",
+		Method:       http.MethodPost,
+		Port:         "12000",
+		SyncResponse: &SyncResponse{Timeout: timeout},
+	}
+	return route
+}
+
+func TestValidateSyncResponse(t *testing.T) {
+	assert.Nil(t, ValidateSyncResponse(nil))
+	assert.Nil(t, ValidateSyncResponse(&SyncResponse{}))
+	assert.Nil(t, ValidateSyncResponse(&SyncResponse{Timeout: "10s"}))
+	assert.NotNil(t, ValidateSyncResponse(&SyncResponse{Timeout: "ten seconds"}))
+	assert.NotNil(t, ValidateSyncResponse(&SyncResponse{Timeout: "-1s"}))
+}
+
+func TestRoute_Dispatch(t *testing.T) {
+	route := GetFakeRoute()
+	go func() {
+		assert.Equal(t, "hello", string(<-route.DataCh))
+	}()
+	assert.Nil(t, route.Dispatch([]byte("hello")))
+}
+
+func TestRoute_DispatchTimeout(t *testing.T) {
+	route := newSyncRoute("10ms")
+	assert.Equal(t, ErrDeliveryTimeout, route.Dispatch([]byte("hello")))
+}
+
+func TestRoute_DispatchWithAck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &fakeAckStream{
+		ctx:    ctx,
+		sentCh: make(chan *gateways.Event),
+		acks:   make(map[string]chan<- error),
+	}
+	router := &FakeRouter{route: newSyncRoute("10s")}
+	go manageRouteStream(router, NewController(), stream)
+
+	go func() {
+		event := <-stream.sentCh
+		assert.Equal(t, "hello", string(event.Payload))
+		assert.NotEmpty(t, event.Id)
+		stream.acks[event.Id] <- nil
+	}()
+	assert.Nil(t, router.route.Dispatch([]byte("hello")))
+
+	go func() {
+		event := <-stream.sentCh
+		stream.acks[event.Id] <- errors.New("sensor is down")
+	}()
+	assert.EqualError(t, router.route.Dispatch([]byte("hello")), "sensor is down")
+}
+
+func TestSendDispatchError(t *testing.T) {
+	writer := httptest.NewRecorder()
+	SendDispatchError(writer, ErrDeliveryTimeout)
+	assert.Equal(t, http.StatusGatewayTimeout, writer.Code)
+
+	writer = httptest.NewRecorder()
+	SendDispatchError(writer, errors.New("sensor is down"))
+	assert.Equal(t, http.StatusBadGateway, writer.Code)
+
+	writer = httptest.NewRecorder()
+	SendDispatchError(writer, ErrRouteInactive)
+	assert.Equal(t, http.StatusServiceUnavailable, writer.Code)
+}
+
+func TestManageRoute_StreamFailure(t *testing.T) {
+	controller := NewController()
+	router := &FakeRouter{route: GetFakeRoute()}
+	go func() {
+		<-controller.RouteActivateChan
+		router.route.StartCh <- struct{}{}
+	}()
+
+	stream := &failingStream{fakeAckStream{ctx: context.Background()}}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ManageRoute(router, controller, stream)
+	}()
+
+	// the first event fails the stream, the route is then inactivated and stops taking data
+	go router.route.Dispatch([]byte("hello"))
+	select {
+	case deactivated := <-controller.RouteDeactivateChan:
+		assert.Equal(t, router, deactivated)
+	case <-time.After(5 * time.Second):
+		t.Fatal("route is not inactivated")
+	}
+	assert.EqualError(t, <-errCh, "stream is closed")
+	assert.Equal(t, ErrRouteInactive, router.route.Dispatch([]byte("hello")))
+}
+
+type failingStream struct {
+	fakeAckStream
+}
+
+func (f *failingStream) Send(event *gateways.Event) error {
+	return errors.New("stream is closed")
+}
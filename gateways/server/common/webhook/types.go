@@ -26,7 +26,7 @@ import (
 )
 
 var (
-	// Mutex synchronizes ActiveServerHandlers
+	// Mutex synchronizes ActiveServerHandlers, ActiveRoutes and the servers of the controllers
 	Lock sync.Mutex
 )
 
@@ -57,9 +57,12 @@ type Route struct {
 	Active bool
 	// data channel to receive data on this endpoint
 	DataCh chan []byte
-	// initialized indicates whether the route has been initialized and there exist a http router
-	// to process incoming requests
-	initialized bool
+	// eventCh receives the events that wait for their delivery on a synchronous route
+	eventCh chan *event
+	// failCh receives the error of the http server the route is registered with if the server fails to listen and serve
+	failCh chan error
+	// doneCh is closed once the route stops consuming the data and events dispatched to it
+	doneCh chan struct{}
 }
 
 // Controller controls the active servers and endpoints
@@ -72,6 +75,10 @@ type Controller struct {
 	RouteActivateChan chan Router
 	// RouteDeactivateChan handles inactivation of routes
 	RouteDeactivateChan chan Router
+	// servers keeps track of the running http servers by port
+	servers map[string]*server
+	// stoppingServers keeps track of the http servers that are draining by port
+	stoppingServers map[string]*server
 }
 
 // Context holds a general purpose REST API context
@@ -89,4 +96,15 @@ type Context struct {
 	ServerCertPath string `json:"serverCertPath,omitempty" protobuf:"bytes,4,opt,name=serverCertPath"`
 	// ServerKeyPath refers the file that contains private key
 	ServerKeyPath string `json:"serverKeyPath,omitempty" protobuf:"bytes,5,opt,name=serverKeyPath"`
+	// Auth holds the authentication and verification of the incoming requests.
+	// It is only supported by the generic webhook event source, the other event sources reject it.
+	// +optional
+	Auth *Auth `json:"auth,omitempty" protobuf:"bytes,7,opt,name=auth"`
+	// Limits caps the rate, size and concurrency of the incoming requests
+	// +optional
+	Limits *Limits `json:"limits,omitempty" protobuf:"bytes,8,opt,name=limits"`
+	// SyncResponse holds the response to a request until its event is delivered to the sensors.
+	// The route responds with 5xx if the delivery fails or times out.
+	// +optional
+	SyncResponse *SyncResponse `json:"syncResponse,omitempty" protobuf:"bytes,9,opt,name=syncResponse"`
 }
@@ -38,6 +38,21 @@ func ValidateWebhookContext(context *Context) error {
 			return fmt.Errorf("failed to parse server port %s. err: %+v", context.Port, err)
 		}
 	}
+	if err := ValidateLimits(context.Limits); err != nil {
+		return err
+	}
+	if err := ValidateSyncResponse(context.SyncResponse); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateNoAuth validates that the webhook context of an event source other than the generic webhook doesn't set the authentication,
+// as only the generic webhook event source enforces it
+func ValidateNoAuth(context *Context) error {
+	if context != nil && context.Auth != nil {
+		return fmt.Errorf("auth is only supported by the webhook event source")
+	}
 	return nil
 }
 
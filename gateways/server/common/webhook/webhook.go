@@ -17,12 +17,17 @@ limitations under the License.
 package webhook
 
 import (
+	"errors"
 	"fmt"
+
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	"github.com/argoproj/argo-events/gateways"
+	gatewayserver "github.com/argoproj/argo-events/gateways/server"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
-	"net/http"
+	"go.opencensus.io/trace"
 )
 
 // NewController returns a webhook controller
@@ -32,6 +37,8 @@ func NewController() *Controller {
 		ActiveServerHandlers: make(map[string]*mux.Router),
 		RouteActivateChan:    make(chan Router),
 		RouteDeactivateChan:  make(chan Router),
+		servers:              make(map[string]*server),
+		stoppingServers:      make(map[string]*server),
 	}
 }
 
@@ -44,6 +51,9 @@ func NewRoute(hookContext *Context, logger *logrus.Logger, eventSource *gateways
 		Active:      false,
 		DataCh:      make(chan []byte),
 		StartCh:     make(chan struct{}),
+		eventCh:     make(chan *event),
+		failCh:      make(chan error, 1),
+		doneCh:      make(chan struct{}),
 	}
 }
 
@@ -53,52 +63,139 @@ func ProcessRouteStatus(ctrl *Controller) {
 		select {
 		case router := <-ctrl.RouteActivateChan:
 			// start server if it has not been started on this port
-			startServer(router, ctrl)
+			if stopping := startServer(router, ctrl); stopping != nil {
+				// the activation is queued again once the previous server on the port has released it
+				go func(router Router) {
+					<-stopping.stopped
+					ctrl.RouteActivateChan <- router
+				}(router)
+				continue
+			}
 			// to allow route process incoming requests
 			router.GetRoute().StartCh <- struct{}{}
 
 		case router := <-ctrl.RouteDeactivateChan:
-			router.GetRoute().Active = false
+			// unregister the route and stop the server if no other route is left on the port
+			stopRoute(router, ctrl)
 		}
 	}
 }
 
-// starts a http server
-func startServer(router Router, controller *Controller) {
-	// start a http server only if no other configuration previously started the server on given port
-	Lock.Lock()
+// starts a http server if no server is running on the port of the route and registers the route with it.
+// If the previous server on the port is still draining, the route is not registered and that server is returned instead.
+func startServer(router Router, controller *Controller) *server {
 	route := router.GetRoute()
-	if _, ok := controller.ActiveServerHandlers[route.Context.Port]; !ok {
-		handler := mux.NewRouter()
-		server := &http.Server{
-			Addr:    fmt.Sprintf(":%s", route.Context.Port),
-			Handler: handler,
-		}
+	port := route.Context.Port
+
+	log := route.Logger.WithFields(
+		map[string]interface{}{
+			common.LabelEventSource: route.EventSource.Name,
+			common.LabelPort:        port,
+			common.LabelEndpoint:    route.Context.Endpoint,
+		})
 
-		controller.ActiveServerHandlers[route.Context.Port] = handler
+	Lock.Lock()
+	defer Lock.Unlock()
+
+	// the previous server on the port must drain and release the port first
+	if stopping, ok := controller.stoppingServers[port]; ok {
+		select {
+		case <-stopping.stopped:
+			delete(controller.stoppingServers, port)
+		default:
+			log.Info("waiting for the previous http server on the port to stop...")
+			return stopping
+		}
+	}
 
-		// start http server
+	// start a http server only if no other configuration previously started the server on given port
+	srv, ok := controller.servers[port]
+	if !ok {
+		srv = newServer(route)
+		controller.servers[port] = srv
 		go func() {
-			var err error
-			if route.Context.ServerCertPath == "" || route.Context.ServerKeyPath == "" {
-				err = server.ListenAndServe()
-			} else {
-				err = server.ListenAndServeTLS(route.Context.ServerCertPath, route.Context.ServerKeyPath)
-			}
-			route.Logger.WithField(common.LabelEventSource, route.EventSource.Name).WithError(err).Error("http server stopped")
-			if err != nil {
-				route.Logger.WithError(err).WithField("port", route.Context.Port).Errorln("failed to listen and serve")
+			if err := srv.serve(log); err != nil {
+				failServer(controller, port, srv, err)
 			}
 		}()
+	} else if srv.certPath != route.Context.ServerCertPath || srv.keyPath != route.Context.ServerKeyPath {
+		log.Warnln("http server on the port was started with different TLS settings, the route will be served with those")
 	}
 
-	// if route is not previously initialized, then assign a router against it
-	if !route.initialized {
-		handler := controller.ActiveServerHandlers[route.Context.Port]
-		handler.HandleFunc(route.Context.Endpoint, router.HandleRoute).Methods(route.Context.Method)
+	key := routeKey(route)
+	if _, ok := srv.registrations[key]; ok {
+		log.WithField(common.LabelHTTPMethod, route.Context.Method).Warnln("replacing the route registered with the same endpoint and method")
 	}
+	srv.register(router)
+	controller.ActiveRoutes[key] = route
+	controller.ActiveServerHandlers[port] = srv.handler
+	return nil
+}
+
+// failServer removes the server that failed to listen and serve, and fails the routes registered with it
+func failServer(controller *Controller, port string, srv *server, err error) {
+	Lock.Lock()
+	defer Lock.Unlock()
 
-	Lock.Unlock()
+	if controller.servers[port] == srv {
+		delete(controller.servers, port)
+		delete(controller.ActiveServerHandlers, port)
+	}
+	for key, reg := range srv.registrations {
+		route := reg.router.GetRoute()
+		if controller.ActiveRoutes[key] == route {
+			delete(controller.ActiveRoutes, key)
+		}
+		select {
+		case route.failCh <- err:
+		default:
+		}
+	}
+	srv.registrations = make(map[string]*registration)
+	srv.rebuild()
+}
+
+// stopRoute inactivates the route and unregisters it from its server.
+// The server is shut down once no route is left on its port.
+func stopRoute(router Router, controller *Controller) {
+	route := router.GetRoute()
+	port := route.Context.Port
+	route.Active = false
+
+	log := route.Logger.WithFields(
+		map[string]interface{}{
+			common.LabelEventSource: route.EventSource.Name,
+			common.LabelPort:        port,
+			common.LabelEndpoint:    route.Context.Endpoint,
+		})
+
+	Lock.Lock()
+	defer Lock.Unlock()
+
+	srv, ok := controller.servers[port]
+	if !ok || !srv.unregister(router) {
+		return
+	}
+	delete(controller.ActiveRoutes, routeKey(route))
+	log.Info("route is unregistered")
+
+	if len(srv.registrations) > 0 {
+		controller.ActiveServerHandlers[port] = srv.handler
+		return
+	}
+
+	log.Info("no route is left on the port, stopping the http server...")
+	delete(controller.servers, port)
+	delete(controller.ActiveServerHandlers, port)
+	controller.stoppingServers[port] = srv
+	go func() {
+		srv.shutdown(log)
+		Lock.Lock()
+		if controller.stoppingServers[port] == srv {
+			delete(controller.stoppingServers, port)
+		}
+		Lock.Unlock()
+	}()
 }
 
 // activateRoute activates a route to process incoming requests
@@ -132,24 +229,54 @@ func manageRouteStream(router Router, controller *Controller, eventStream gatewa
 	for {
 		select {
 		case data := <-route.DataCh:
-			route.Logger.WithField(common.LabelEventSource, route.EventSource.Name).Info("new event received, dispatching to gateway client")
-			err := eventStream.Send(&gateways.Event{
-				Name:    route.EventSource.Name,
-				Payload: data,
-			})
-			if err != nil {
-				route.Logger.WithField(common.LabelEventSource, route.EventSource.Name).WithError(err).Error("failed to send event")
+			if err := sendEvent(route, eventStream, data, ""); err != nil {
 				return err
 			}
 
+		case e := <-route.eventCh:
+			acknowledger, ok := eventStream.(gatewayserver.Acknowledger)
+			if !ok {
+				e.ackCh <- errors.New("gateway client doesn't acknowledge the events")
+				continue
+			}
+			// the event fails once the stream is closed if the error is returned
+			id := fmt.Sprintf("%x", uuid.New())
+			acknowledger.ExpectAck(id, e.ackCh)
+			if err := sendEvent(route, eventStream, e.data, id); err != nil {
+				return err
+			}
+
+		case err := <-route.failCh:
+			return fmt.Errorf("http server on port %s failed. err: %+v", route.Context.Port, err)
+
 		case <-eventStream.Context().Done():
 			route.Logger.WithField(common.LabelEventSource, route.EventSource.Name).Info("connection is closed by client")
-			controller.RouteDeactivateChan <- router
 			return nil
 		}
 	}
 }
 
+// sendEvent sends the data received on the route to the gateway client.
+// The gateway client acknowledges the delivery of the event if the id is set.
+func sendEvent(route *Route, eventStream gateways.Eventing_StartEventSourceServer, data []byte, id string) error {
+	route.Logger.WithField(common.LabelEventSource, route.EventSource.Name).Info("new event received, dispatching to gateway client")
+	_, span := trace.StartSpan(eventStream.Context(), "gateway.receive_event")
+	span.AddAttributes(trace.StringAttribute(common.LabelEventSource, route.EventSource.Name))
+	span.AddAttributes(trace.StringAttribute(common.LabelEndpoint, route.Context.Endpoint))
+	err := eventStream.Send(&gateways.Event{
+		Name:        route.EventSource.Name,
+		Payload:     data,
+		TraceParent: tracing.TraceParent(span.SpanContext()),
+		Id:          id,
+	})
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		route.Logger.WithField(common.LabelEventSource, route.EventSource.Name).WithError(err).Error("failed to send event")
+	}
+	return err
+}
+
 // ManagerRoute manages the lifecycle of a route
 func ManageRoute(router Router, controller *Controller, eventStream gateways.Eventing_StartEventSourceServer) error {
 	route := router.GetRoute()
@@ -172,6 +299,17 @@ func ManageRoute(router Router, controller *Controller, eventStream gateways.Eve
 	logger.Info("activating the route...")
 	activateRoute(router, controller)
 
+	// the route is inactivated however it stops, so that its server doesn't wait on it forever
+	defer func() {
+		close(route.doneCh)
+		controller.RouteDeactivateChan <- router
+
+		logger.Info("running operations post route inactivation...")
+		if err := router.PostInactivate(); err != nil {
+			logger.WithError(err).Error("error occurred while running operations post route inactivation")
+		}
+	}()
+
 	logger.Info("running operations post route activation...")
 	if err := router.PostActivate(); err != nil {
 		logger.WithError(err).Error("error occurred while performing post route activation operations")
@@ -184,10 +322,5 @@ func ManageRoute(router Router, controller *Controller, eventStream gateways.Eve
 		return err
 	}
 
-	logger.Info("running operations post route inactivation...")
-	if err := router.PostInactivate(); err != nil {
-		logger.WithError(err).Error("error occurred while running operations post route inactivation")
-	}
-
 	return nil
 }
@@ -44,22 +44,22 @@ func (listener *EventListener) StartEventSource(eventSource *gateways.EventSourc
 
 	ctx := eventStream.Context()
 
-	dataCh := make(chan []byte)
+	messageCh := make(chan *server.Message)
 	errorCh := make(chan error)
 	doneCh := make(chan struct{}, 1)
 
-	go listener.listenEvents(ctx, eventSource, dataCh, errorCh, doneCh)
-	return server.HandleEventsFromEventSource(eventSource.Name, eventStream, dataCh, errorCh, doneCh, listener.Logger)
+	go listener.listenEvents(ctx, eventSource, messageCh, errorCh, doneCh)
+	return server.HandleMessagesFromEventSource(eventSource.Name, eventStream, messageCh, errorCh, doneCh, listener.Logger)
 }
 
 // listenEvents listens to GCP PubSub events
-func (listener *EventListener) listenEvents(ctx context.Context, eventSource *gateways.EventSource, dataCh chan []byte, errorCh chan error, doneCh chan struct{}) {
+func (listener *EventListener) listenEvents(ctx context.Context, eventSource *gateways.EventSource, messageCh chan *server.Message, errorCh chan error, doneCh chan struct{}) {
 	// In order to listen events from GCP PubSub,
 	// 1. Parse the event source that contains configuration to connect to GCP PubSub
 	// 2. Create a new PubSub client
 	// 3. Create the topic if one doesn't exist already
 	// 4. Create a subscription if one doesn't exist already.
-	// 5. Start listening to messages on the queue
+	// 5. Start listening to messages on the queue. A message is acked once the gateway client acknowledges it and nacked otherwise
 	// 6. Once the event source is stopped perform cleaning up - 1. Delete the subscription if configured so 2. Close the PubSub client
 
 	logger := listener.Logger.WithField(common.LabelEventSource, eventSource.Name)
@@ -132,15 +132,37 @@ func (listener *EventListener) listenEvents(ctx context.Context, eventSource *ga
 	logger.Infoln("listening for messages from PubSub...")
 	err = subscription.Receive(ctx, func(msgCtx context.Context, m *pubsub.Message) {
 		logger.Info("received GCP PubSub Message from topic")
-		dataCh <- m.Data
-		m.Ack()
+		message := &server.Message{
+			Data: m.Data,
+			Ack: func(err error) {
+				if err != nil {
+					logger.WithError(err).Warnln("message was not dispatched, nacking it")
+					m.Nack()
+					return
+				}
+				m.Ack()
+			},
+		}
+		select {
+		case messageCh <- message:
+		case <-msgCtx.Done():
+			// the subscription is stopping, the message is redelivered
+			m.Nack()
+		}
 	})
 	if err != nil {
-		errorCh <- err
+		select {
+		case errorCh <- err:
+		case <-ctx.Done():
+		}
 		return
 	}
 
-	<-doneCh
+	// Receive returns once the stream to the gateway client is closed
+	select {
+	case <-doneCh:
+	case <-ctx.Done():
+	}
 
 	if pubsubEventSource.DeleteSubscriptionOnFinish {
 		logger.Info("deleting PubSub subscription...")
@@ -106,7 +106,11 @@ func (router *Router) HandleRoute(writer http.ResponseWriter, request *http.Requ
 	}
 
 	logger.Infoln("dispatching event on route's data channel")
-	route.DataCh <- body
+	if err := route.Dispatch(body); err != nil {
+		logger.WithError(err).Errorln("failed to deliver the event")
+		webhook.SendDispatchError(writer, err)
+		return
+	}
 	logger.Info("request successfully processed")
 
 	common.SendSuccessResponse(writer, "success")
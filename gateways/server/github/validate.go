@@ -75,5 +75,8 @@ func validate(githubEventSource *v1alpha1.GithubEventSource) error {
 			return fmt.Errorf("content type must be \"json\" or \"form\"")
 		}
 	}
+	if err := webhook.ValidateNoAuth(githubEventSource.Webhook); err != nil {
+		return err
+	}
 	return webhook.ValidateWebhookContext(githubEventSource.Webhook)
 }
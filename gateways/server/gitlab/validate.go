@@ -72,5 +72,8 @@ func validate(eventSource *v1alpha1.GitlabEventSource) error {
 	if eventSource.AccessToken == nil {
 		return fmt.Errorf("access token can't be nil")
 	}
+	if err := webhook.ValidateNoAuth(eventSource.Webhook); err != nil {
+		return err
+	}
 	return webhook.ValidateWebhookContext(eventSource.Webhook)
 }
@@ -22,13 +22,16 @@ import (
 	"os"
 
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	"github.com/argoproj/argo-events/gateways"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 	"google.golang.org/grpc"
 )
 
 // StartGateway start a gateway
-func StartGateway(es gateways.EventingServer) {
+func StartGateway(listener EventListener) {
 	port, ok := os.LookupEnv(common.EnvVarGatewayServerPort)
 	if !ok {
 		panic(fmt.Errorf("port is not provided"))
@@ -37,8 +40,14 @@ func StartGateway(es gateways.EventingServer) {
 	if err != nil {
 		panic(err)
 	}
+	// export the spans of the received events
+	flushSpans, err := tracing.ConfigureExporter(os.Getenv(common.EnvVarResourceName))
+	if err != nil {
+		panic(err)
+	}
+	go tracing.FlushOnTermination(flushSpans)
 	srv := grpc.NewServer()
-	gateways.RegisterEventingServer(srv, es)
+	gateways.RegisterEventingServer(srv, NewEventingServer(listener))
 
 	fmt.Println("starting gateway server")
 
@@ -59,12 +68,7 @@ func HandleEventsFromEventSource(name string, eventStream gateways.Eventing_Star
 	for {
 		select {
 		case data := <-dataCh:
-			log.WithField(common.LabelEventSource, name).Info("new event received, dispatching to gateway client")
-			err := eventStream.Send(&gateways.Event{
-				Name:    name,
-				Payload: data,
-			})
-			if err != nil {
+			if err := sendEvent(name, eventStream, data, "", log); err != nil {
 				return err
 			}
 
@@ -79,3 +83,69 @@ func HandleEventsFromEventSource(name string, eventStream gateways.Eventing_Star
 		}
 	}
 }
+
+// Message is an event that the event source commits only once the gateway client acknowledges it
+type Message struct {
+	// Data is the event payload
+	Data []byte
+	// Ack is called once with the result of the dispatch of the event, nil if the event was dispatched.
+	// The event source commits the event on success and hands it back to its source otherwise, so it is redelivered.
+	Ack func(err error)
+}
+
+// HandleMessagesFromEventSource handles the messages from an event source that commits the events on acknowledgement.
+// If the gateway client doesn't acknowledge the events, a message is acknowledged as soon as it is sent to the client.
+func HandleMessagesFromEventSource(name string, eventStream gateways.Eventing_StartEventSourceServer, messageCh chan *Message, errorCh chan error, doneCh chan struct{}, log *logrus.Logger) error {
+	acknowledger, acked := eventStream.(Acknowledger)
+
+	for {
+		select {
+		case message := <-messageCh:
+			if !acked {
+				err := sendEvent(name, eventStream, message.Data, "", log)
+				message.Ack(err)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			// the message fails once the stream is closed if the error is returned
+			id := fmt.Sprintf("%x", uuid.New())
+			ackCh := make(chan error, 1)
+			acknowledger.ExpectAck(id, ackCh)
+			go func(message *Message) {
+				message.Ack(<-ackCh)
+			}(message)
+			if err := sendEvent(name, eventStream, message.Data, id, log); err != nil {
+				return err
+			}
+
+		case err := <-errorCh:
+			log.WithField(common.LabelEventSource, name).WithError(err).Error("error occurred processing the event source")
+			return err
+
+		case <-eventStream.Context().Done():
+			log.WithField(common.LabelEventSource, name).Info("connection is closed by client")
+			doneCh <- struct{}{}
+			return nil
+		}
+	}
+}
+
+// sendEvent sends the data from the event source to the gateway client.
+// The gateway client acknowledges the dispatch of the event if the id is set.
+func sendEvent(name string, eventStream gateways.Eventing_StartEventSourceServer, data []byte, id string, log *logrus.Logger) error {
+	log.WithField(common.LabelEventSource, name).Info("new event received, dispatching to gateway client")
+	_, span := trace.StartSpan(eventStream.Context(), "gateway.receive_event")
+	span.AddAttributes(trace.StringAttribute(common.LabelEventSource, name))
+	err := eventStream.Send(&gateways.Event{
+		Name:        name,
+		Payload:     data,
+		TraceParent: tracing.TraceParent(span.SpanContext()),
+		Id:          id,
+	})
+	tracing.RecordError(span, err)
+	span.End()
+	return err
+}
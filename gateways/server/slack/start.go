@@ -116,7 +116,11 @@ func (rc *Router) HandleRoute(writer http.ResponseWriter, request *http.Request)
 
 	if data != nil {
 		logger.Infoln("dispatching event on route's data channel...")
-		route.DataCh <- data
+		if err := route.Dispatch(data); err != nil {
+			logger.WithError(err).Errorln("failed to deliver the event")
+			webhook.SendDispatchError(writer, err)
+			return
+		}
 	}
 
 	logger.Info("request successfully processed")
@@ -66,5 +66,8 @@ func validate(eventSource *v1alpha1.SlackEventSource) error {
 	if eventSource.Token == nil {
 		return fmt.Errorf("token not provided")
 	}
+	if err := webhook.ValidateNoAuth(eventSource.Webhook); err != nil {
+		return err
+	}
 	return webhook.ValidateWebhookContext(eventSource.Webhook)
 }
@@ -25,6 +25,7 @@ import (
 
 	"github.com/argoproj/argo-events/common"
 	"github.com/argoproj/argo-events/gateways"
+	"github.com/argoproj/argo-events/gateways/server/common/webhook"
 	"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1"
 	"github.com/ghodss/yaml"
 )
@@ -60,5 +61,9 @@ func TestSlackEventSource(t *testing.T) {
 		})
 		fmt.Println(valid.Reason)
 		assert.Equal(t, true, valid.IsValid)
+
+		// only the generic webhook event source supports auth
+		value.Webhook.Auth = &webhook.Auth{AllowedSourceIPs: []string{"10.0.0.0/8"}}
+		assert.NotNil(t, validate(&value))
 	}
 }
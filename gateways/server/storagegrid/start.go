@@ -31,6 +31,7 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/google/uuid"
 	"github.com/joncalhoun/qson"
+	"github.com/sirupsen/logrus"
 )
 
 // controller controls the webhook operations
@@ -133,20 +134,34 @@ func (router *Router) HandleRoute(writer http.ResponseWriter, request *http.Requ
 	case http.MethodHead:
 		respBody = ""
 	}
+
+	if notification := router.filterNotification(body, logger); notification != nil {
+		logger.Infoln("new event received, dispatching event on route's data channel")
+		if err := route.Dispatch(notification); err != nil {
+			logger.WithError(err).Errorln("failed to deliver the event")
+			webhook.SendDispatchError(writer, err)
+			return
+		}
+	}
+
 	writer.WriteHeader(http.StatusOK)
 	writer.Header().Add("Content-Type", "text/plain")
 	writer.Write([]byte(respBody))
+}
 
+// filterNotification converts the request body into a storage grid notification in JSON format.
+// It returns nil if the body is not a valid notification or the notification does not pass all filters.
+func (router *Router) filterNotification(body []byte, logger *logrus.Entry) []byte {
 	// notification received from storage grid is url encoded.
 	parsedURL, err := url.QueryUnescape(string(body))
 	if err != nil {
 		logger.WithError(err).Errorln("failed to unescape request body url")
-		return
+		return nil
 	}
 	b, err := qson.ToJSON(parsedURL)
 	if err != nil {
 		logger.WithError(err).Errorln("failed to convert request body in JSON format")
-		return
+		return nil
 	}
 
 	logger.Infoln("converting request body to storage grid notification")
@@ -154,16 +169,15 @@ func (router *Router) HandleRoute(writer http.ResponseWriter, request *http.Requ
 	err = json.Unmarshal(b, &notification)
 	if err != nil {
 		logger.WithError(err).Errorln("failed to convert the request body into storage grid notification")
-		return
+		return nil
 	}
 
 	if filterEvent(notification, router.storageGridEventSource) && filterName(notification, router.storageGridEventSource) {
-		logger.WithError(err).Errorln("new event received, dispatching event on route's data channel")
-		route.DataCh <- b
-		return
+		return b
 	}
 
 	logger.Warnln("discarding notification since it did not pass all filters")
+	return nil
 }
 
 // PostActivate performs operations once the route is activated and ready to consume requests
@@ -62,5 +62,8 @@ func validate(eventSource *v1alpha1.StorageGridEventSource) error {
 	if eventSource == nil {
 		return common.ErrNilEventSource
 	}
+	if err := webhook.ValidateNoAuth(eventSource.Webhook); err != nil {
+		return err
+	}
 	return webhook.ValidateWebhookContext(eventSource.Webhook)
 }
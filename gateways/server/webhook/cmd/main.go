@@ -17,13 +17,28 @@ limitations under the License.
 package main
 
 import (
+	"os"
+
 	"github.com/argoproj/argo-events/common"
 	"github.com/argoproj/argo-events/gateways/server"
 	"github.com/argoproj/argo-events/gateways/server/webhook"
+	"k8s.io/client-go/kubernetes"
 )
 
 func main() {
+	kubeConfig, _ := os.LookupEnv(common.EnvVarKubeConfig)
+	restConfig, err := common.GetClientConfig(kubeConfig)
+	if err != nil {
+		panic(err)
+	}
+	clientset := kubernetes.NewForConfigOrDie(restConfig)
+	namespace, ok := os.LookupEnv(common.EnvVarNamespace)
+	if !ok {
+		panic("namespace is not provided")
+	}
 	server.StartGateway(&webhook.EventListener{
-		Logger: common.NewArgoEventsLogger(),
+		Logger:    common.NewArgoEventsLogger(),
+		K8sClient: clientset,
+		Namespace: namespace,
 	})
 }
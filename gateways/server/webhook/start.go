@@ -18,6 +18,7 @@ package webhook
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 
@@ -25,20 +26,28 @@ import (
 	"github.com/argoproj/argo-events/gateways"
 	"github.com/argoproj/argo-events/gateways/server"
 	"github.com/argoproj/argo-events/gateways/server/common/webhook"
+	"github.com/argoproj/argo-events/store"
 	"github.com/ghodss/yaml"
 	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
 )
 
 // EventListener implements Eventing for webhook events
 type EventListener struct {
 	// Logger logs stuff
 	Logger *logrus.Logger
+	// K8sClient is the Kubernetes client used to retrieve the auth secrets
+	K8sClient kubernetes.Interface
+	// Namespace where gateway is deployed
+	Namespace string
 }
 
 // Router contains the configuration information for a route
 type Router struct {
 	// route contains information about a API endpoint
 	route *webhook.Route
+	// credentials hold the secrets the requests are authenticated against
+	credentials *webhook.Credentials
 }
 
 // controller controls the webhook operations
@@ -90,6 +99,12 @@ func (router *Router) HandleRoute(writer http.ResponseWriter, request *http.Requ
 		return
 	}
 
+	if err := route.Context.Auth.VerifySource(request); err != nil {
+		logger.WithError(err).Warnln("rejected the request")
+		router.sendAuthError(writer, err)
+		return
+	}
+
 	body, err := ioutil.ReadAll(request.Body)
 	if err != nil {
 		logger.WithError(err).Error("failed to parse request body")
@@ -97,6 +112,12 @@ func (router *Router) HandleRoute(writer http.ResponseWriter, request *http.Requ
 		return
 	}
 
+	if err := route.Context.Auth.VerifyRequest(request, body, router.credentials); err != nil {
+		logger.WithError(err).Warnln("rejected the request")
+		router.sendAuthError(writer, err)
+		return
+	}
+
 	data, err := json.Marshal(&payload{
 		Header: request.Header,
 		Body:   body,
@@ -108,11 +129,24 @@ func (router *Router) HandleRoute(writer http.ResponseWriter, request *http.Requ
 	}
 
 	logger.Infoln("dispatching event on route's data channel...")
-	route.DataCh <- data
+	if err := route.Dispatch(data); err != nil {
+		logger.WithError(err).Errorln("failed to deliver the event")
+		webhook.SendDispatchError(writer, err)
+		return
+	}
 	logger.Info("successfully processed the request")
 	common.SendSuccessResponse(writer, "success")
 }
 
+// sendAuthError responds to a request that failed the authentication of the route
+func (router *Router) sendAuthError(writer http.ResponseWriter, err *webhook.AuthError) {
+	if challenge := router.route.Context.Auth.Challenge(); err.Code == http.StatusUnauthorized && challenge != "" {
+		writer.Header().Set("WWW-Authenticate", challenge)
+	}
+	writer.WriteHeader(err.Code)
+	writer.Write([]byte(err.Reason))
+}
+
 // PostActivate performs operations once the route is activated and ready to consume requests
 func (router *Router) PostActivate() error {
 	return nil
@@ -137,9 +171,47 @@ func (listener *EventListener) StartEventSource(eventSource *gateways.EventSourc
 		return err
 	}
 
+	credentials, err := listener.getCredentials(webhookEventSource.Auth)
+	if err != nil {
+		log.WithError(err).Error("failed to retrieve the auth secrets")
+		return err
+	}
+
 	route := webhook.NewRoute(webhookEventSource, listener.Logger, eventSource)
 
 	return webhook.ManageRoute(&Router{
-		route: route,
+		route:       route,
+		credentials: credentials,
 	}, controller, eventStream)
 }
+
+// getCredentials retrieves the secrets the auth of the route refers to
+func (listener *EventListener) getCredentials(auth *webhook.Auth) (*webhook.Credentials, error) {
+	credentials := &webhook.Credentials{}
+	if auth == nil || (auth.HMAC == nil && auth.BearerToken == nil && auth.BasicAuth == nil) {
+		return credentials, nil
+	}
+	if listener.K8sClient == nil {
+		return nil, fmt.Errorf("kubernetes client is required to retrieve the auth secrets")
+	}
+	var err error
+	if auth.HMAC != nil {
+		if credentials.HMACKey, err = store.GetSecrets(listener.K8sClient, listener.Namespace, auth.HMAC.Secret.Name, auth.HMAC.Secret.Key); err != nil {
+			return nil, err
+		}
+	}
+	if auth.BearerToken != nil {
+		if credentials.BearerToken, err = store.GetSecrets(listener.K8sClient, listener.Namespace, auth.BearerToken.Name, auth.BearerToken.Key); err != nil {
+			return nil, err
+		}
+	}
+	if auth.BasicAuth != nil {
+		if credentials.Username, err = store.GetSecrets(listener.K8sClient, listener.Namespace, auth.BasicAuth.Username.Name, auth.BasicAuth.Username.Key); err != nil {
+			return nil, err
+		}
+		if credentials.Password, err = store.GetSecrets(listener.K8sClient, listener.Namespace, auth.BasicAuth.Password.Name, auth.BasicAuth.Password.Key); err != nil {
+			return nil, err
+		}
+	}
+	return credentials, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/gateways"
+	"github.com/argoproj/argo-events/gateways/server/common/webhook"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetCredentials(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	_, err := client.CoreV1().Secrets("argo-events").Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "webhook-auth",
+		},
+		Data: map[string][]byte{
+			"token": []byte("secret-token"),
+		},
+	})
+	assert.Nil(t, err)
+
+	auth := &webhook.Auth{
+		BearerToken: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "webhook-auth"},
+			Key:                  "token",
+		},
+	}
+
+	listener := &EventListener{Logger: common.NewArgoEventsLogger()}
+	credentials, err := listener.getCredentials(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &webhook.Credentials{}, credentials)
+	_, err = listener.getCredentials(auth)
+	assert.NotNil(t, err)
+
+	listener.K8sClient = client
+	listener.Namespace = "argo-events"
+	credentials, err = listener.getCredentials(auth)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret-token", credentials.BearerToken)
+
+	auth.BearerToken.Key = "unknown"
+	_, err = listener.getCredentials(auth)
+	assert.NotNil(t, err)
+}
+
+func TestHandleRouteWithAuth(t *testing.T) {
+	route := webhook.NewRoute(&webhook.Context{
+		Endpoint: "/example",
+		Method:   http.MethodPost,
+		Port:     "12000",
+		Auth: &webhook.Auth{
+			BearerToken: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "webhook-auth"},
+				Key:                  "token",
+			},
+			AllowedSourceIPs: []string{"10.0.0.0/8"},
+		},
+	}, common.NewArgoEventsLogger(), &gateways.EventSource{
+		Name: "example",
+	})
+	route.Active = true
+	router := &Router{
+		route:       route,
+		credentials: &webhook.Credentials{BearerToken: "secret-token"},
+	}
+
+	newRequest := func(remoteAddr, token string) *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/example", bytes.NewReader([]byte("hello")))
+		request.RemoteAddr = remoteAddr
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		return request
+	}
+
+	writer := httptest.NewRecorder()
+	router.HandleRoute(writer, newRequest("192.168.1.1:41234", "secret-token"))
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	writer = httptest.NewRecorder()
+	router.HandleRoute(writer, newRequest("10.1.2.3:41234", ""))
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+	assert.Equal(t, "Bearer", writer.Header().Get("WWW-Authenticate"))
+
+	writer = httptest.NewRecorder()
+	router.HandleRoute(writer, newRequest("10.1.2.3:41234", "wrong-token"))
+	assert.Equal(t, http.StatusForbidden, writer.Code)
+
+	go func() {
+		<-route.DataCh
+	}()
+	writer = httptest.NewRecorder()
+	router.HandleRoute(writer, newRequest("10.1.2.3:41234", "secret-token"))
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
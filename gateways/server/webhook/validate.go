@@ -70,5 +70,9 @@ func validate(webhookEventSource *webhook.Context) error {
 		return fmt.Errorf("unknown HTTP method %s", webhookEventSource.Method)
 	}
 
+	if err := webhook.ValidateAuth(webhookEventSource.Auth); err != nil {
+		return err
+	}
+
 	return webhook.ValidateWebhookContext(webhookEventSource)
 }
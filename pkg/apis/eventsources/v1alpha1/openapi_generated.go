@@ -31,10 +31,12 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.AMQPEventSource":        schema_pkg_apis_eventsources_v1alpha1_AMQPEventSource(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.CalendarEventSource":    schema_pkg_apis_eventsources_v1alpha1_CalendarEventSource(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.EventSource":            schema_pkg_apis_eventsources_v1alpha1_EventSource(ref),
+		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.EventSourceCondition":   schema_pkg_apis_eventsources_v1alpha1_EventSourceCondition(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.EventSourceList":        schema_pkg_apis_eventsources_v1alpha1_EventSourceList(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.EventSourceSpec":        schema_pkg_apis_eventsources_v1alpha1_EventSourceSpec(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.EventSourceStatus":      schema_pkg_apis_eventsources_v1alpha1_EventSourceStatus(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.FileEventSource":        schema_pkg_apis_eventsources_v1alpha1_FileEventSource(ref),
+		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.GatewayReference":       schema_pkg_apis_eventsources_v1alpha1_GatewayReference(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.GithubEventSource":      schema_pkg_apis_eventsources_v1alpha1_GithubEventSource(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.GitlabEventSource":      schema_pkg_apis_eventsources_v1alpha1_GitlabEventSource(ref),
 		"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.HDFSEventSource":        schema_pkg_apis_eventsources_v1alpha1_HDFSEventSource(ref),
@@ -208,6 +210,49 @@ func schema_pkg_apis_eventsources_v1alpha1_EventSource(ref common.ReferenceCallb
 	}
 }
 
+func schema_pkg_apis_eventsources_v1alpha1_EventSourceCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EventSourceCondition holds the validation state of an entry of the event source",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the entry",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"valid": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Valid tells whether the entry passed the validation of the gateway of the event source type",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message holds the validation error of the entry",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastTransitionTime is the last time the entry became valid or invalid",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"name", "valid"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_eventsources_v1alpha1_EventSourceList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -533,11 +578,54 @@ func schema_pkg_apis_eventsources_v1alpha1_EventSourceStatus(ref common.Referenc
 							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message holds the validation error of the event source as a whole, e.g. an unknown type",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "conditions",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions hold the validation state of each entry of the event source, sorted by name",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.EventSourceCondition"),
+									},
+								},
+							},
+						},
+					},
+					"gateways": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "gateways",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Gateways refer to the gateways that consume the event source",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.GatewayReference"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.EventSourceCondition", "github.com/argoproj/argo-events/pkg/apis/eventsources/v1alpha1.GatewayReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -570,6 +658,34 @@ func schema_pkg_apis_eventsources_v1alpha1_FileEventSource(ref common.ReferenceC
 	}
 }
 
+func schema_pkg_apis_eventsources_v1alpha1_GatewayReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GatewayReference refers to a gateway",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the gateway",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the gateway",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "namespace"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_eventsources_v1alpha1_GithubEventSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
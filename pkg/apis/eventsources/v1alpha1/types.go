@@ -384,4 +384,37 @@ type StorageGridFilter struct {
 // EventSourceStatus holds the status of the event-source resource
 type EventSourceStatus struct {
 	CreatedAt metav1.Time `json:"createdAt,omitempty" protobuf:"bytes,1,opt,name=createdAt"`
+	// Message holds the validation error of the event source as a whole, e.g. an unknown type
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+	// +listType=conditions
+	// Conditions hold the validation state of each entry of the event source, sorted by name
+	// +optional
+	Conditions []EventSourceCondition `json:"conditions,omitempty" protobuf:"bytes,3,rep,name=conditions"`
+	// +listType=gateways
+	// Gateways refer to the gateways that consume the event source
+	// +optional
+	Gateways []GatewayReference `json:"gateways,omitempty" protobuf:"bytes,4,rep,name=gateways"`
+}
+
+// EventSourceCondition holds the validation state of an entry of the event source
+type EventSourceCondition struct {
+	// Name of the entry
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+	// Valid tells whether the entry passed the validation of the gateway of the event source type
+	Valid bool `json:"valid" protobuf:"varint,2,name=valid"`
+	// Message holds the validation error of the entry
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,3,opt,name=message"`
+	// LastTransitionTime is the last time the entry became valid or invalid
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,4,opt,name=lastTransitionTime"`
+}
+
+// GatewayReference refers to a gateway
+type GatewayReference struct {
+	// Name of the gateway
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+	// Namespace of the gateway
+	Namespace string `json:"namespace" protobuf:"bytes,2,name=namespace"`
 }
@@ -112,6 +112,23 @@ func (in *EventSource) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventSourceCondition) DeepCopyInto(out *EventSourceCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventSourceCondition.
+func (in *EventSourceCondition) DeepCopy() *EventSourceCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(EventSourceCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventSourceList) DeepCopyInto(out *EventSourceList) {
 	*out = *in
@@ -180,7 +197,7 @@ func (in *EventSourceSpec) DeepCopyInto(out *EventSourceSpec) {
 		in, out := &in.Webhook, &out.Webhook
 		*out = make(map[string]webhook.Context, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	if in.AMQP != nil {
@@ -284,6 +301,18 @@ func (in *EventSourceSpec) DeepCopy() *EventSourceSpec {
 func (in *EventSourceStatus) DeepCopyInto(out *EventSourceStatus) {
 	*out = *in
 	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]EventSourceCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]GatewayReference, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -314,13 +343,29 @@ func (in *FileEventSource) DeepCopy() *FileEventSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GithubEventSource) DeepCopyInto(out *GithubEventSource) {
 	*out = *in
 	if in.Webhook != nil {
 		in, out := &in.Webhook, &out.Webhook
 		*out = new(webhook.Context)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Events != nil {
 		in, out := &in.Events, &out.Events
@@ -356,7 +401,7 @@ func (in *GitlabEventSource) DeepCopyInto(out *GitlabEventSource) {
 	if in.Webhook != nil {
 		in, out := &in.Webhook, &out.Webhook
 		*out = new(webhook.Context)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.AccessToken != nil {
 		in, out := &in.AccessToken, &out.AccessToken
@@ -551,7 +596,7 @@ func (in *SNSEventSource) DeepCopyInto(out *SNSEventSource) {
 	if in.Webhook != nil {
 		in, out := &in.Webhook, &out.Webhook
 		*out = new(webhook.Context)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.AccessKey != nil {
 		in, out := &in.AccessKey, &out.AccessKey
@@ -618,7 +663,7 @@ func (in *SlackEventSource) DeepCopyInto(out *SlackEventSource) {
 	if in.Webhook != nil {
 		in, out := &in.Webhook, &out.Webhook
 		*out = new(webhook.Context)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -639,7 +684,7 @@ func (in *StorageGridEventSource) DeepCopyInto(out *StorageGridEventSource) {
 	if in.Webhook != nil {
 		in, out := &in.Webhook, &out.Webhook
 		*out = new(webhook.Context)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Events != nil {
 		in, out := &in.Events, &out.Events
@@ -28,6 +28,7 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.EventQueue":                 schema_pkg_apis_gateway_v1alpha1_EventQueue(ref),
 		"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.EventSourceRef":             schema_pkg_apis_gateway_v1alpha1_EventSourceRef(ref),
 		"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.Gateway":                    schema_pkg_apis_gateway_v1alpha1_Gateway(ref),
 		"github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.GatewayList":                schema_pkg_apis_gateway_v1alpha1_GatewayList(ref),
@@ -41,6 +42,41 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	}
 }
 
+func schema_pkg_apis_gateway_v1alpha1_EventQueue(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EventQueue configures the local queues of the events that are yet to be delivered to the sensor watchers",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"dir": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Dir is the directory of the queues in the gateway containers. Defaults to /tmp/argo-events/queue.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"volume": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Volume is mounted at Dir in the gateway containers. Defaults to an emptyDir volume, which keeps the pending events across restarts of the containers but not of the pod. Use a persistent volume claim to keep them across restarts of the pod. The volume is not mounted if the pod template already mounts a volume at Dir.",
+							Ref:         ref("k8s.io/api/core/v1.VolumeSource"),
+						},
+					},
+					"maxEvents": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxEvents is the maximum number of pending events in the queue of a sensor watcher. Once the queue is full, new events are sent to the sensor watcher once, without retries. Defaults to 10000.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.VolumeSource"},
+	}
+}
+
 func schema_pkg_apis_gateway_v1alpha1_EventSourceRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -294,12 +330,24 @@ func schema_pkg_apis_gateway_v1alpha1_GatewaySpec(ref common.ReferenceCallback)
 							Format:      "int32",
 						},
 					},
+					"deliveryBackoff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeliveryBackoff is the backoff applied while retrying to deliver an event to a sensor watcher. Events that could not be delivered are kept in a local queue and replayed once the sensor is reachable again. Only used if the event protocol is HTTP.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/wait.Backoff"),
+						},
+					},
+					"queue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Queue configures the local queues of the events that are yet to be delivered to the sensor watchers. Only used if the event protocol is HTTP.",
+							Ref:         ref("github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.EventQueue"),
+						},
+					},
 				},
 				Required: []string{"template", "type", "processorPort", "eventProtocol"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-events/pkg/apis/common.EventProtocol", "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.EventSourceRef", "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.NotificationWatchers", "k8s.io/api/core/v1.PodTemplateSpec", "k8s.io/api/core/v1.Service"},
+			"github.com/argoproj/argo-events/pkg/apis/common.EventProtocol", "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.EventQueue", "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.EventSourceRef", "github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.NotificationWatchers", "k8s.io/api/core/v1.PodTemplateSpec", "k8s.io/api/core/v1.Service", "k8s.io/apimachinery/pkg/util/wait.Backoff"},
 	}
 }
 
@@ -350,6 +398,21 @@ func schema_pkg_apis_gateway_v1alpha1_GatewayStatus(ref common.ReferenceCallback
 							Ref:         ref("github.com/argoproj/argo-events/pkg/apis/gateway/v1alpha1.GatewayResource"),
 						},
 					},
+					"pendingEvents": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingEvents is the number of events yet to be delivered to each sensor watcher. It is keyed by the namespace and name of the sensor.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"integer"},
+										Format: "int32",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"phase", "resources"},
 			},
@@ -20,6 +20,7 @@ import (
 	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 // NodePhase is the label for the condition of a node.
@@ -33,6 +34,7 @@ const (
 	NodePhaseCompleted      NodePhase = "Completed"      // node has completed running
 	NodePhaseRemove         NodePhase = "Remove"         // stale node
 	NodePhaseResourceUpdate NodePhase = "ResourceUpdate" // resource is updated
+	NodePhasePendingEvents  NodePhase = "PendingEvents"  // depth of the undelivered event queues changed
 )
 
 // Gateway is the definition of a gateway resource
@@ -78,6 +80,34 @@ type GatewaySpec struct {
 	EventProtocol *apicommon.EventProtocol `json:"eventProtocol" protobuf:"bytes,7,opt,name=eventProtocol"`
 	// Replica is the gateway deployment replicas
 	Replica int `json:"replica,omitempty" protobuf:"bytes,9,opt,name=replica"`
+	// DeliveryBackoff is the backoff applied while retrying to deliver an event to a sensor watcher.
+	// Events that could not be delivered are kept in a local queue and replayed once the sensor is reachable again.
+	// Only used if the event protocol is HTTP.
+	// +optional
+	DeliveryBackoff *wait.Backoff `json:"deliveryBackoff,omitempty" protobuf:"bytes,10,opt,name=deliveryBackoff"`
+	// Queue configures the local queues of the events that are yet to be delivered to the sensor watchers.
+	// Only used if the event protocol is HTTP.
+	// +optional
+	Queue *EventQueue `json:"queue,omitempty" protobuf:"bytes,11,opt,name=queue"`
+}
+
+// EventQueue configures the local queues of the events that are yet to be delivered to the sensor watchers
+type EventQueue struct {
+	// Dir is the directory of the queues in the gateway containers.
+	// Defaults to /tmp/argo-events/queue.
+	// +optional
+	Dir string `json:"dir,omitempty" protobuf:"bytes,1,opt,name=dir"`
+	// Volume is mounted at Dir in the gateway containers.
+	// Defaults to an emptyDir volume, which keeps the pending events across restarts of the containers but not of the pod.
+	// Use a persistent volume claim to keep them across restarts of the pod.
+	// The volume is not mounted if the pod template already mounts a volume at Dir.
+	// +optional
+	Volume *corev1.VolumeSource `json:"volume,omitempty" protobuf:"bytes,2,opt,name=volume"`
+	// MaxEvents is the maximum number of pending events in the queue of a sensor watcher.
+	// Once the queue is full, new events are sent to the sensor watcher once, without retries.
+	// Defaults to 10000.
+	// +optional
+	MaxEvents int32 `json:"maxEvents,omitempty" protobuf:"varint,3,opt,name=maxEvents"`
 }
 
 // EventSourceRef holds information about the EventSourceRef custom resource
@@ -112,6 +142,9 @@ type GatewayStatus struct {
 	Nodes map[string]NodeStatus `json:"nodes,omitempty" protobuf:"bytes,5,rep,name=nodes"`
 	// Resources refers to the metadata about the gateway resources
 	Resources *GatewayResource `json:"resources" protobuf:"bytes,6,opt,name=resources"`
+	// PendingEvents is the number of events yet to be delivered to each sensor watcher.
+	// It is keyed by the namespace and name of the sensor.
+	PendingEvents map[string]int32 `json:"pendingEvents,omitempty" protobuf:"bytes,7,rep,name=pendingEvents"`
 }
 
 // NodeStatus describes the status for an individual node in the gateway configurations.
@@ -21,11 +21,33 @@ package v1alpha1
 
 import (
 	common "github.com/argoproj/argo-events/pkg/apis/common"
-	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	wait "k8s.io/apimachinery/pkg/util/wait"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventQueue) DeepCopyInto(out *EventQueue) {
+	*out = *in
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventQueue.
+func (in *EventQueue) DeepCopy() *EventQueue {
+	if in == nil {
+		return nil
+	}
+	out := new(EventQueue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventSourceRef) DeepCopyInto(out *EventSourceRef) {
 	*out = *in
@@ -124,12 +146,12 @@ func (in *GatewayResource) DeepCopyInto(out *GatewayResource) {
 	*out = *in
 	if in.Deployment != nil {
 		in, out := &in.Deployment, &out.Deployment
-		*out = new(v1.ObjectMeta)
+		*out = new(metav1.ObjectMeta)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
-		*out = new(v1.ObjectMeta)
+		*out = new(metav1.ObjectMeta)
 		(*in).DeepCopyInto(*out)
 	}
 	return
@@ -150,7 +172,7 @@ func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 	*out = *in
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
-		*out = new(corev1.PodTemplateSpec)
+		*out = new(v1.PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.EventSourceRef != nil {
@@ -160,7 +182,7 @@ func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 	}
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
-		*out = new(corev1.Service)
+		*out = new(v1.Service)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Watchers != nil {
@@ -173,6 +195,16 @@ func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 		*out = new(common.EventProtocol)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeliveryBackoff != nil {
+		in, out := &in.DeliveryBackoff, &out.DeliveryBackoff
+		*out = new(wait.Backoff)
+		**out = **in
+	}
+	if in.Queue != nil {
+		in, out := &in.Queue, &out.Queue
+		*out = new(EventQueue)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -202,6 +234,13 @@ func (in *GatewayStatus) DeepCopyInto(out *GatewayStatus) {
 		*out = new(GatewayResource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PendingEvents != nil {
+		in, out := &in.PendingEvents, &out.PendingEvents
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
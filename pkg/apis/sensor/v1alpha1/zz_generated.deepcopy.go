@@ -24,8 +24,37 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	wait "k8s.io/apimachinery/pkg/util/wait"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQPTrigger) DeepCopyInto(out *AMQPTrigger) {
+	*out = *in
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = make([]TriggerParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConnectionBackoff != nil {
+		in, out := &in.ConnectionBackoff, &out.ConnectionBackoff
+		*out = new(Backoff)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQPTrigger.
+func (in *AMQPTrigger) DeepCopy() *AMQPTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQPTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArtifactLocation) DeepCopyInto(out *ArtifactLocation) {
 	*out = *in
@@ -92,6 +121,80 @@ func (in *Backoff) DeepCopy() *Backoff {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
+	*out = *in
+	if in.Username != nil {
+		in, out := &in.Username, &out.Username
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuth.
+func (in *BasicAuth) DeepCopy() *BasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BoltDBStateStore) DeepCopyInto(out *BoltDBStateStore) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BoltDBStateStore.
+func (in *BoltDBStateStore) DeepCopy() *BoltDBStateStore {
+	if in == nil {
+		return nil
+	}
+	out := new(BoltDBStateStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapDeadLetterSink) DeepCopyInto(out *ConfigMapDeadLetterSink) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapDeadLetterSink.
+func (in *ConfigMapDeadLetterSink) DeepCopy() *ConfigMapDeadLetterSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapDeadLetterSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapStateStore) DeepCopyInto(out *ConfigMapStateStore) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapStateStore.
+func (in *ConfigMapStateStore) DeepCopy() *ConfigMapStateStore {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapStateStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigmapArtifact) DeepCopyInto(out *ConfigmapArtifact) {
 	*out = *in
@@ -108,6 +211,27 @@ func (in *ConfigmapArtifact) DeepCopy() *ConfigmapArtifact {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Correlation) DeepCopyInto(out *Correlation) {
+	*out = *in
+	if in.Sets != nil {
+		in, out := &in.Sets, &out.Sets
+		*out = new(EventSets)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Correlation.
+func (in *Correlation) DeepCopy() *Correlation {
+	if in == nil {
+		return nil
+	}
+	out := new(Correlation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataFilter) DeepCopyInto(out *DataFilter) {
 	*out = *in
@@ -129,6 +253,42 @@ func (in *DataFilter) DeepCopy() *DataFilter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadLetterSink) DeepCopyInto(out *DeadLetterSink) {
+	*out = *in
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileDeadLetterSink)
+		**out = **in
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapDeadLetterSink)
+		**out = **in
+	}
+	if in.NATS != nil {
+		in, out := &in.NATS, &out.NATS
+		*out = new(NATSDeadLetterSink)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPDeadLetterSink)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadLetterSink.
+func (in *DeadLetterSink) DeepCopy() *DeadLetterSink {
+	if in == nil {
+		return nil
+	}
+	out := new(DeadLetterSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DependencyGroup) DeepCopyInto(out *DependencyGroup) {
 	*out = *in
@@ -137,6 +297,11 @@ func (in *DependencyGroup) DeepCopyInto(out *DependencyGroup) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Correlation != nil {
+		in, out := &in.Correlation, &out.Correlation
+		*out = new(Correlation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -204,6 +369,22 @@ func (in *EventDependencyFilter) DeepCopy() *EventDependencyFilter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventSets) DeepCopyInto(out *EventSets) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventSets.
+func (in *EventSets) DeepCopy() *EventSets {
+	if in == nil {
+		return nil
+	}
+	out := new(EventSets)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileArtifact) DeepCopyInto(out *FileArtifact) {
 	*out = *in
@@ -220,6 +401,22 @@ func (in *FileArtifact) DeepCopy() *FileArtifact {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileDeadLetterSink) DeepCopyInto(out *FileDeadLetterSink) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileDeadLetterSink.
+func (in *FileDeadLetterSink) DeepCopy() *FileDeadLetterSink {
+	if in == nil {
+		return nil
+	}
+	out := new(FileDeadLetterSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitArtifact) DeepCopyInto(out *GitArtifact) {
 	*out = *in
@@ -293,6 +490,149 @@ func (in *GitRemoteConfig) DeepCopy() *GitRemoteConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPDeadLetterSink) DeepCopyInto(out *HTTPDeadLetterSink) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPDeadLetterSink.
+func (in *HTTPDeadLetterSink) DeepCopy() *HTTPDeadLetterSink {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPDeadLetterSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTrigger) DeepCopyInto(out *HTTPTrigger) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = make([]TriggerParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryStrategy != nil {
+		in, out := &in.RetryStrategy, &out.RetryStrategy
+		*out = new(wait.Backoff)
+		**out = **in
+	}
+	if in.SuccessStatuses != nil {
+		in, out := &in.SuccessStatuses, &out.SuccessStatuses
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTrigger.
+func (in *HTTPTrigger) DeepCopy() *HTTPTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTrigger) DeepCopyInto(out *KafkaTrigger) {
+	*out = *in
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PartitioningKey != nil {
+		in, out := &in.PartitioningKey, &out.PartitioningKey
+		*out = new(TriggerParameterSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = make([]TriggerParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTrigger.
+func (in *KafkaTrigger) DeepCopy() *KafkaTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATSDeadLetterSink) DeepCopyInto(out *NATSDeadLetterSink) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATSDeadLetterSink.
+func (in *NATSDeadLetterSink) DeepCopy() *NATSDeadLetterSink {
+	if in == nil {
+		return nil
+	}
+	out := new(NATSDeadLetterSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATSTrigger) DeepCopyInto(out *NATSTrigger) {
+	*out = *in
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = make([]TriggerParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConnectionBackoff != nil {
+		in, out := &in.ConnectionBackoff, &out.ConnectionBackoff
+		*out = new(Backoff)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATSTrigger.
+func (in *NATSTrigger) DeepCopy() *NATSTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(NATSTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 	*out = *in
@@ -316,6 +656,48 @@ func (in *NodeStatus) DeepCopy() *NodeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStateStore) DeepCopyInto(out *RedisStateStore) {
+	*out = *in
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStateStore.
+func (in *RedisStateStore) DeepCopy() *RedisStateStore {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStateStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplayServer) DeepCopyInto(out *ReplayServer) {
+	*out = *in
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplayServer.
+func (in *ReplayServer) DeepCopy() *ReplayServer {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplayServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceLabelsPolicy) DeepCopyInto(out *ResourceLabelsPolicy) {
 	*out = *in
@@ -465,6 +847,26 @@ func (in *SensorSpec) DeepCopyInto(out *SensorSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Correlation != nil {
+		in, out := &in.Correlation, &out.Correlation
+		*out = new(Correlation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateStore != nil {
+		in, out := &in.StateStore, &out.StateStore
+		*out = new(StateStore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeadLetter != nil {
+		in, out := &in.DeadLetter, &out.DeadLetter
+		*out = new(DeadLetterSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replay != nil {
+		in, out := &in.Replay, &out.Replay
+		*out = new(ReplayServer)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -509,6 +911,58 @@ func (in *SensorStatus) DeepCopy() *SensorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackTrigger) DeepCopyInto(out *SlackTrigger) {
+	*out = *in
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackTrigger.
+func (in *SlackTrigger) DeepCopy() *SlackTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateStore) DeepCopyInto(out *StateStore) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapStateStore)
+		**out = **in
+	}
+	if in.BoltDB != nil {
+		in, out := &in.BoltDB, &out.BoltDB
+		*out = new(BoltDBStateStore)
+		**out = **in
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisStateStore)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateStore.
+func (in *StateStore) DeepCopy() *StateStore {
+	if in == nil {
+		return nil
+	}
+	out := new(StateStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TimeFilter) DeepCopyInto(out *TimeFilter) {
 	*out = *in
@@ -552,6 +1006,13 @@ func (in *Trigger) DeepCopyInto(out *Trigger) {
 		*out = new(TriggerPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PatchParameters != nil {
+		in, out := &in.PatchParameters, &out.PatchParameters
+		*out = make([]TriggerParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -673,6 +1134,36 @@ func (in *TriggerTemplate) DeepCopyInto(out *TriggerTemplate) {
 		*out = new(ArtifactLocation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Patch != nil {
+		in, out := &in.Patch, &out.Patch
+		*out = new(string)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NATS != nil {
+		in, out := &in.NATS, &out.NATS
+		*out = new(NATSTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMQP != nil {
+		in, out := &in.AMQP, &out.AMQP
+		*out = new(AMQPTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackTrigger)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
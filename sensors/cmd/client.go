@@ -19,6 +19,7 @@ package main
 import (
 	"fmt"
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	sv1 "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned"
 	"github.com/argoproj/argo-events/sensors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -60,6 +61,20 @@ func main() {
 
 	// wait for sensor http server to shutdown
 	sensorExecutionCtx := sensors.NewSensorContext(sensorClient, kubeClient, dynamicClient, sensor, controllerInstanceID)
+
+	// export the spans of the event notifications and the triggers, and release the trigger connections on termination
+	flushSpans, err := tracing.ConfigureExporter(sensorName)
+	if err != nil {
+		panic(err)
+	}
+	go tracing.FlushOnTermination(func() {
+		sensorExecutionCtx.Close()
+		flushSpans()
+	})
+
+	// expose sensor metrics
+	go common.StartMetricsServer(common.GetMetricsPort(), sensorExecutionCtx.Logger)
+
 	if err := sensorExecutionCtx.ListenEvents(); err != nil {
 		panic(err)
 	}
@@ -20,7 +20,13 @@ import (
 	"github.com/argoproj/argo-events/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	sensorclientset "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/dependencies"
+	"github.com/argoproj/argo-events/sensors/state"
+	"github.com/argoproj/argo-events/sensors/triggers"
 	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/nats-io/go-nats"
+	snats "github.com/nats-io/go-nats-streaming"
 	"github.com/sirupsen/logrus"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -44,11 +50,27 @@ type SensorContext struct {
 	ControllerInstanceID string
 	// Updated indicates update to Sensor resource
 	Updated bool
+	// NatsConn is the standard nats connection used to subscribe to gateway subjects. Only used if event protocol is NATS
+	NatsConn *nats.Conn
+	// NatsStreamingConn is the nats streaming connection. Only used if nats type is Streaming
+	NatsStreamingConn snats.Conn
+	// EventSets holds the incomplete sets of correlated events. Only used if the sensor correlates multiple sets of events at a time
+	EventSets dependencies.EventSetStore
+	// StateStore persists the events of the dependencies outside the sensor status. Only used if the sensor has a state store
+	StateStore state.Store
+	// DeadLetter is the sink of the events that were rejected by the filters or whose triggers failed. Only used if the sensor has a dead-letter sink
+	DeadLetter deadletter.Sink
+	// TriggerConnections caches the producers of the messaging triggers so that they are reused across the trigger executions
+	TriggerConnections *triggers.Connections
+	// triggerJobs is the queue of the trigger workers. Only used if the sensor has trigger workers
+	triggerJobs chan *triggerJob
+	// render receives the rendered triggers in place of their execution. Only set if the sensor is offline
+	render TriggerRenderer
 }
 
 // NewSensorContext returns a new sensor execution context.
 func NewSensorContext(sensorClient sensorclientset.Interface, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, sensor *v1alpha1.Sensor, controllerInstanceID string) *SensorContext {
-	return &SensorContext{
+	sensorCtx := &SensorContext{
 		SensorClient:         sensorClient,
 		KubeClient:           kubeClient,
 		DynamicClient:        dynamicClient,
@@ -56,5 +78,17 @@ func NewSensorContext(sensorClient sensorclientset.Interface, kubeClient kuberne
 		Logger:               common.NewArgoEventsLogger().WithField(common.LabelSensorName, sensor.Name).Logger,
 		NotificationQueue:    make(chan *types.Notification),
 		ControllerInstanceID: controllerInstanceID,
+		EventSets:            newEventSetStore(sensor),
+		DeadLetter:           newDeadLetterSink(kubeClient, sensor),
+		TriggerConnections:   triggers.NewConnections(),
+	}
+	sensorCtx.startTriggerWorkers(int(sensor.Spec.TriggerWorkers))
+	return sensorCtx
+}
+
+// Close releases the connections of the triggers. It is called once the sensor terminates.
+func (sensorCtx *SensorContext) Close() {
+	if err := sensorCtx.TriggerConnections.Close(); err != nil {
+		sensorCtx.Logger.WithError(err).Errorln("failed to close the trigger connections")
 	}
 }
@@ -0,0 +1,77 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// newDeadLetterSink returns the dead-letter sink of the sensor, nil if the sensor has none
+func newDeadLetterSink(kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor) deadletter.Sink {
+	sink := sensor.Spec.DeadLetter
+	if sink == nil {
+		return nil
+	}
+
+	switch {
+	case sink.File != nil:
+		return deadletter.NewFileSink(sink.File.Path)
+	case sink.ConfigMap != nil:
+		name := sink.ConfigMap.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-dead-letter", sensor.Name)
+		}
+		return deadletter.NewConfigMapSink(kubeClient, sensor.Namespace, name, int(sink.ConfigMap.MaxRecords), metav1.NewControllerRef(sensor, v1alpha1.SchemaGroupVersionKind))
+	case sink.NATS != nil:
+		return deadletter.NewNATSSink(sink.NATS.URL, sink.NATS.Subject)
+	case sink.HTTP != nil:
+		return deadletter.NewHTTPSink(sink.HTTP.URL, time.Duration(sink.HTTP.Timeout)*time.Second)
+	default:
+		return nil
+	}
+}
+
+// deadLetter sends the event of the notification to the dead-letter sink along with the trigger that failed, if any, and the error.
+// A failure to deliver the record is logged, it doesn't fail the processing of the event.
+func (sensorCtx *SensorContext) deadLetter(sensorName string, notification *types.Notification, triggerName string, err error) {
+	if sensorCtx.DeadLetter == nil {
+		return
+	}
+	record := deadletter.NewRecord(sensorName, notification.EventDependency.Name, triggerName, notification.Event, err, notification.Attempts+1)
+	logger := sensorCtx.Logger.WithFields(map[string]interface{}{
+		"dependency-name": notification.EventDependency.Name,
+		"trigger-name":    triggerName,
+		"record-id":       record.ID,
+	})
+
+	status := common.MetricStatusSuccess
+	if sendErr := sensorCtx.DeadLetter.Send(record); sendErr != nil {
+		status = common.MetricStatusFailure
+		logger.WithError(sendErr).Errorln("failed to send the event to the dead-letter sink")
+	} else {
+		logger.Infoln("sent the event to the dead-letter sink")
+	}
+	deadLetterRecords.WithLabelValues(sensorName, status).Inc()
+}
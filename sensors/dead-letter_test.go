@@ -0,0 +1,89 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	sensorFake "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned/fake"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	dfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeadLetter(t *testing.T) {
+	sensorClient := sensorFake.NewSimpleClientset()
+	dynamicClient := dfake.NewSimpleDynamicClient(runtime.NewScheme())
+	k8sClient := fake.NewSimpleClientset()
+	// the trigger fails as the endpoint is not reachable
+	obj := newTriggerWorkersSensor("http://127.0.0.1:1")
+	obj.Spec.Triggers = obj.Spec.Triggers[:1]
+	obj.Spec.DeadLetter = &v1alpha1.DeadLetterSink{
+		ConfigMap: &v1alpha1.ConfigMapDeadLetterSink{},
+	}
+	obj.Spec.Dependencies[1].Filters = &v1alpha1.EventDependencyFilter{
+		Data: []v1alpha1.DataFilter{
+			{
+				Path:  "name",
+				Type:  "string",
+				Value: []string{"fake"},
+			},
+		},
+	}
+	newObj, err := sensorClient.ArgoprojV1alpha1().Sensors(obj.Namespace).Create(obj)
+	assert.Nil(t, err)
+	sensorCtx := NewSensorContext(sensorClient, k8sClient, dynamicClient, newObj.DeepCopy(), "1")
+	assert.NotNil(t, sensorCtx.DeadLetter)
+
+	// the event of the second dependency is rejected by the filter
+	sensorCtx.processQueue(&types.Notification{
+		Event:            newTriggerWorkersEvent(),
+		EventDependency:  &obj.Spec.Dependencies[1],
+		NotificationType: v1alpha1.EventNotification,
+	})
+
+	// the trigger fails once the dependencies are resolved
+	obj.Spec.Dependencies[1].Filters = nil
+	sensorCtx.Sensor.Spec.Dependencies[1].Filters = nil
+	for i := range obj.Spec.Dependencies {
+		sensorCtx.processQueue(&types.Notification{
+			Event:            newTriggerWorkersEvent(),
+			EventDependency:  &obj.Spec.Dependencies[i],
+			NotificationType: v1alpha1.EventNotification,
+			Attempts:         1,
+		})
+	}
+	assert.Equal(t, v1alpha1.TriggerCycleFailure, sensorCtx.Sensor.Status.TriggerCycleStatus)
+
+	records, err := sensorCtx.DeadLetter.(deadletter.Reader).Records()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+
+	assert.Equal(t, "dep2", records[0].Dependency)
+	assert.Equal(t, "", records[0].Trigger)
+	assert.Equal(t, 1, records[0].Attempts)
+
+	assert.Equal(t, "dep2", records[1].Dependency)
+	assert.Equal(t, "slow-trigger", records[1].Trigger)
+	assert.Equal(t, 2, records[1].Attempts)
+	assert.NotEmpty(t, records[1].Error)
+	assert.Equal(t, []byte("{}"), records[1].Event.Data)
+}
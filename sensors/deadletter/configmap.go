@@ -0,0 +1,180 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// DefaultMaxRecords is the number of records kept by a config map sink if none is specified
+	DefaultMaxRecords = 100
+	// recordsKey is the key of the records in the config map
+	recordsKey = "records"
+)
+
+// ConfigMapSink keeps the latest records in a config map
+type ConfigMapSink struct {
+	client     kubernetes.Interface
+	namespace  string
+	name       string
+	maxRecords int
+	owner      *metav1.OwnerReference
+	lock       sync.Mutex
+}
+
+// NewConfigMapSink returns a sink that keeps at most maxRecords records in the config map.
+// The config map is created on the first record and is owned by the owner, if any.
+func NewConfigMapSink(client kubernetes.Interface, namespace, name string, maxRecords int, owner *metav1.OwnerReference) *ConfigMapSink {
+	if maxRecords <= 0 {
+		maxRecords = DefaultMaxRecords
+	}
+	return &ConfigMapSink{
+		client:     client,
+		namespace:  namespace,
+		name:       name,
+		maxRecords: maxRecords,
+		owner:      owner,
+	}
+}
+
+// Send adds the record to the config map and drops the oldest records beyond the limit.
+// The records are also limited to the size of a config map: the oldest records are dropped until the records fit,
+// and a record that doesn't fit on its own is kept without the data of its event.
+func (sink *ConfigMapSink) Send(record *Record) error {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	configMaps := sink.client.CoreV1().ConfigMaps(sink.namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		exists := true
+		cm, err := configMaps.Get(sink.name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			exists = false
+			cm = sink.newConfigMap()
+		}
+		records, err := decodeRecords(cm)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+		if len(records) > sink.maxRecords {
+			records = records[len(records)-sink.maxRecords:]
+		}
+		data, err := encodeRecords(records, common.MaxConfigMapSize-len(recordsKey))
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[recordsKey] = string(data)
+
+		if !exists {
+			_, err = configMaps.Create(cm)
+			return err
+		}
+		_, err = configMaps.Update(cm)
+		return err
+	})
+}
+
+// encodeRecords encodes the latest records whose encoding fits in maxSize bytes
+func encodeRecords(records []*Record, maxSize int) ([]byte, error) {
+	encoded := make([][]byte, len(records))
+	// the records are encoded as a JSON array
+	size := len(records) + 1
+	for i, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode the records")
+		}
+		encoded[i] = data
+		size += len(data)
+	}
+	for len(encoded) > 1 && size > maxSize {
+		size -= len(encoded[0]) + 1
+		encoded = encoded[1:]
+	}
+	if size > maxSize {
+		data, err := json.Marshal(records[len(records)-1].truncate())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode the records")
+		}
+		if len(data)+2 > maxSize {
+			return nil, fmt.Errorf("record of %d bytes exceeds the size limit of the config map", len(data))
+		}
+		encoded[0] = data
+	}
+	return append(append([]byte("["), bytes.Join(encoded, []byte(","))...), ']'), nil
+}
+
+// Records returns the records kept in the config map
+func (sink *ConfigMapSink) Records() ([]*Record, error) {
+	cm, err := sink.client.CoreV1().ConfigMaps(sink.namespace).Get(sink.name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeRecords(cm)
+}
+
+// newConfigMap returns the config map that keeps the records
+func (sink *ConfigMapSink) newConfigMap() *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sink.name,
+			Namespace: sink.namespace,
+		},
+	}
+	if sink.owner != nil {
+		cm.OwnerReferences = []metav1.OwnerReference{*sink.owner}
+		cm.Labels = map[string]string{
+			common.LabelOwnerName: sink.owner.Name,
+		}
+	}
+	return cm
+}
+
+// decodeRecords decodes the records of the config map
+func decodeRecords(cm *corev1.ConfigMap) ([]*Record, error) {
+	data, ok := cm.Data[recordsKey]
+	if !ok || data == "" {
+		return nil, nil
+	}
+	var records []*Record
+	if err := json.Unmarshal([]byte(data), &records); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the records of the config map %s", cm.Name)
+	}
+	return records, nil
+}
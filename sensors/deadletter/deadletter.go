@@ -0,0 +1,84 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/google/uuid"
+)
+
+// Record is an event that was rejected by the filters of its dependency or whose trigger execution failed
+type Record struct {
+	// ID of the record
+	ID string `json:"id"`
+	// Sensor is the name of the sensor
+	Sensor string `json:"sensor"`
+	// Dependency is the name of the dependency the event was received for
+	Dependency string `json:"dependency"`
+	// Trigger is the name of the trigger that failed. Empty if the event was rejected by the filters.
+	Trigger string `json:"trigger,omitempty"`
+	// Event that failed
+	Event *apicommon.Event `json:"event"`
+	// Error is the error of the filter or the trigger
+	Error string `json:"error"`
+	// Attempts is the number of times the event was processed, including the replays
+	Attempts int `json:"attempts"`
+	// Time the record was created
+	Time time.Time `json:"time"`
+	// Truncated is true if the data of the event was dropped for the record to fit in the sink
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// NewRecord returns a record of the failure of the event
+func NewRecord(sensor, dependency, trigger string, event *apicommon.Event, err error, attempts int) *Record {
+	return &Record{
+		ID:         uuid.New().String(),
+		Sensor:     sensor,
+		Dependency: dependency,
+		Trigger:    trigger,
+		Event:      event,
+		Error:      err.Error(),
+		Attempts:   attempts,
+		Time:       time.Now().UTC(),
+	}
+}
+
+// truncate returns a copy of the record without the data of the event
+func (record *Record) truncate() *Record {
+	truncated := *record
+	if record.Event != nil {
+		event := *record.Event
+		event.Data = nil
+		truncated.Event = &event
+	}
+	truncated.Truncated = true
+	return &truncated
+}
+
+// Sink is the destination of the dead-letter records
+type Sink interface {
+	// Send delivers the record
+	Send(record *Record) error
+}
+
+// Reader is implemented by the sinks the records can be read back from
+type Reader interface {
+	// Records returns the records from the oldest to the newest
+	Records() ([]*Record, error)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	natstest "github.com/nats-io/gnatsd/test"
+	natslib "github.com/nats-io/go-nats"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestRecord(id int) *Record {
+	record := NewRecord("fake-sensor", "fake-dependency", "fake-trigger", &apicommon.Event{
+		Context: apicommon.EventContext{
+			ID: fmt.Sprintf("%d", id),
+		},
+		Data: []byte(`{"message": "hello"}`),
+	}, fmt.Errorf("trigger failed"), 1)
+	return record
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dead-letter")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	sink := NewFileSink(filepath.Join(dir, "records.json"))
+	records, err := sink.Records()
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, sink.Send(newTestRecord(i)))
+	}
+	records, err = sink.Records()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, "2", records[2].Event.Context.ID)
+	assert.Equal(t, "fake-trigger", records[2].Trigger)
+	assert.Equal(t, "trigger failed", records[2].Error)
+	assert.Equal(t, []byte(`{"message": "hello"}`), records[2].Event.Data)
+}
+
+func TestConfigMapSink(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := NewConfigMapSink(client, "fake", "fake-sensor-dead-letter", 2, nil)
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, sink.Send(newTestRecord(i)))
+	}
+	// the oldest record is dropped
+	records, err := sink.Records()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, "1", records[0].Event.Context.ID)
+	assert.Equal(t, "2", records[1].Event.Context.ID)
+}
+
+func TestConfigMapSinkSize(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := NewConfigMapSink(client, "fake", "fake-sensor-dead-letter", 0, nil)
+
+	// only the latest records fit in the config map
+	for i := 0; i < 3; i++ {
+		record := newTestRecord(i)
+		record.Event.Data = make([]byte, 300*1024)
+		assert.Nil(t, sink.Send(record))
+	}
+	records, err := sink.Records()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, "1", records[0].Event.Context.ID)
+	assert.False(t, records[1].Truncated)
+
+	// a record that doesn't fit on its own is kept without its data
+	record := newTestRecord(3)
+	record.Event.Data = make([]byte, 2*1024*1024)
+	assert.Nil(t, sink.Send(record))
+	records, err = sink.Records()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, "3", records[0].Event.Context.ID)
+	assert.True(t, records[0].Truncated)
+	assert.Nil(t, records[0].Event.Data)
+	assert.Equal(t, 2*1024*1024, len(record.Event.Data))
+}
+
+func TestNATSSink(t *testing.T) {
+	opts := natstest.DefaultTestOptions
+	opts.Port = 14225
+	server := natstest.RunServer(&opts)
+	defer server.Shutdown()
+
+	conn, err := natslib.Connect("nats://127.0.0.1:14225")
+	assert.Nil(t, err)
+	defer conn.Close()
+	msgCh := make(chan *natslib.Msg, 1)
+	_, err = conn.ChanSubscribe("dead-letter", msgCh)
+	assert.Nil(t, err)
+	assert.Nil(t, conn.Flush())
+
+	sink := NewNATSSink("nats://127.0.0.1:14225", "dead-letter")
+	assert.Nil(t, sink.Send(newTestRecord(1)))
+
+	select {
+	case msg := <-msgCh:
+		record := &Record{}
+		assert.Nil(t, json.Unmarshal(msg.Data, record))
+		assert.Equal(t, "fake-dependency", record.Dependency)
+	case <-time.After(5 * time.Second):
+		t.Fatal("record was not published")
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	records := make(chan *Record, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record := &Record{}
+		if err := json.NewDecoder(r.Body).Decode(record); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		records <- record
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, time.Second)
+	assert.Nil(t, sink.Send(newTestRecord(1)))
+	record := <-records
+	assert.Equal(t, 1, record.Attempts)
+
+	failing := httptest.NewServer(http.NotFoundHandler())
+	defer failing.Close()
+	sink = NewHTTPSink(failing.URL, time.Second)
+	assert.NotNil(t, sink.Send(newTestRecord(2)))
+}
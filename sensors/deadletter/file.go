@@ -0,0 +1,91 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FileSink appends the records to a file as JSON lines
+type FileSink struct {
+	path string
+	lock sync.Mutex
+}
+
+// NewFileSink returns a sink that appends the records to the file at the path
+func NewFileSink(path string) *FileSink {
+	return &FileSink{
+		path: path,
+	}
+}
+
+// Send appends the record to the file
+func (sink *FileSink) Send(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the record")
+	}
+
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	file, err := os.OpenFile(sink.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open the file %s", sink.path)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return errors.Wrapf(err, "failed to write the record to the file %s", sink.path)
+	}
+	return file.Close()
+}
+
+// Records reads the records from the file
+func (sink *FileSink) Records() ([]*Record, error) {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	file, err := os.Open(sink.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to open the file %s", sink.path)
+	}
+	defer file.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(file)
+	// the events can be larger than the default line limit
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, errors.Wrap(err, "failed to decode the record")
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
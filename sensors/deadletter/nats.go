@@ -0,0 +1,62 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"encoding/json"
+	"sync"
+
+	natslib "github.com/nats-io/go-nats"
+	"github.com/pkg/errors"
+)
+
+// NATSSink publishes the records to a NATS subject
+type NATSSink struct {
+	url     string
+	subject string
+	conn    *natslib.Conn
+	lock    sync.Mutex
+}
+
+// NewNATSSink returns a sink that publishes the records to the subject. The connection is established on the first record.
+func NewNATSSink(url, subject string) *NATSSink {
+	return &NATSSink{
+		url:     url,
+		subject: subject,
+	}
+}
+
+// Send publishes the record to the subject
+func (sink *NATSSink) Send(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the record")
+	}
+
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	if sink.conn == nil || sink.conn.IsClosed() {
+		if sink.conn, err = natslib.Connect(sink.url); err != nil {
+			return errors.Wrapf(err, "failed to connect to the nats cluster %s", sink.url)
+		}
+	}
+	if err := sink.conn.Publish(sink.subject, data); err != nil {
+		return errors.Wrapf(err, "failed to publish the record to subject %s", sink.subject)
+	}
+	return sink.conn.Flush()
+}
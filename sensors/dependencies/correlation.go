@@ -0,0 +1,122 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependencies
+
+import (
+	"fmt"
+	"time"
+
+	snctrl "github.com/argoproj/argo-events/controllers/sensor"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// ApplyCorrelation expires the events of the dependencies that don't correlate with the event just received for the dependency.
+// The sensor level correlation applies to all the dependencies, a group level correlation to the dependencies of the group.
+// Expired dependency nodes are marked back as active so they wait for a new event.
+func ApplyCorrelation(sensor *v1alpha1.Sensor, dependencyName string, logger *logrus.Logger) error {
+	if sensor.Spec.Correlation != nil {
+		names := make([]string, 0, len(sensor.Spec.Dependencies))
+		for _, dependency := range sensor.Spec.Dependencies {
+			names = append(names, dependency.Name)
+		}
+		if err := correlate(sensor, sensor.Spec.Correlation, dependencyName, names, logger); err != nil {
+			return err
+		}
+	}
+	for _, group := range sensor.Spec.DependencyGroups {
+		if group.Correlation == nil || !contains(group.Dependencies, dependencyName) {
+			continue
+		}
+		if err := correlate(sensor, group.Correlation, dependencyName, group.Dependencies, logger); err != nil {
+			return errors.Wrapf(err, "failed to correlate the events of group %s", group.Name)
+		}
+	}
+	return nil
+}
+
+// correlate expires the events of the dependencies that are outside the window or have a different key than the event of the reference dependency
+func correlate(sensor *v1alpha1.Sensor, correlation *v1alpha1.Correlation, reference string, dependencies []string, logger *logrus.Logger) error {
+	referenceNode := snctrl.GetNodeByName(sensor, reference)
+	if referenceNode == nil || referenceNode.Event == nil || referenceNode.Phase != v1alpha1.NodePhaseComplete {
+		return nil
+	}
+
+	var window time.Duration
+	if correlation.Window != "" {
+		var err error
+		if window, err = time.ParseDuration(correlation.Window); err != nil {
+			return errors.Wrapf(err, "failed to parse the correlation window %s", correlation.Window)
+		}
+	}
+	referenceTime := referenceNode.Event.Context.Time.Time
+	referenceKey := correlationKey(correlation, referenceNode)
+
+	for _, dependency := range dependencies {
+		if dependency == reference {
+			continue
+		}
+		node := snctrl.GetNodeByName(sensor, dependency)
+		if node == nil || node.Event == nil || node.Phase != v1alpha1.NodePhaseComplete {
+			continue
+		}
+		if window > 0 && !referenceTime.IsZero() && !node.Event.Context.Time.IsZero() {
+			if distance := referenceTime.Sub(node.Event.Context.Time.Time); distance > window || distance < -window {
+				expireNode(sensor, dependency, logger, fmt.Sprintf("event expired as it is %s apart from the event of dependency %s", distance, reference))
+				continue
+			}
+		}
+		if correlation.Key != "" {
+			if key := correlationKey(correlation, node); key != referenceKey {
+				expireNode(sensor, dependency, logger, fmt.Sprintf("event expired as its correlation key %s doesn't match the key %s of the event of dependency %s", key, referenceKey, reference))
+			}
+		}
+	}
+	return nil
+}
+
+// correlationKey returns the correlation key of the event of the node
+func correlationKey(correlation *v1alpha1.Correlation, node *v1alpha1.NodeStatus) string {
+	if correlation.Key == "" {
+		return ""
+	}
+	return CorrelationKey(correlation, node.Event)
+}
+
+// CorrelationKey returns the correlation key of the event. The key is empty if the path doesn't exist.
+func CorrelationKey(correlation *v1alpha1.Correlation, event *apicommon.Event) string {
+	return gjson.GetBytes(event.Data, correlation.Key).String()
+}
+
+// expireNode marks the dependency node as active and removes its event
+func expireNode(sensor *v1alpha1.Sensor, dependency string, logger *logrus.Logger, message string) {
+	node := snctrl.MarkNodePhase(sensor, dependency, v1alpha1.NodeTypeEventDependency, v1alpha1.NodePhaseActive, nil, logger, message)
+	node.Event = nil
+	sensor.Status.Nodes[node.ID] = *node
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
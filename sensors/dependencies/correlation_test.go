@@ -0,0 +1,128 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependencies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	snctrl "github.com/argoproj/argo-events/controllers/sensor"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newCorrelationEvent(eventTime time.Time, sha string) *apicommon.Event {
+	return &apicommon.Event{
+		Context: apicommon.EventContext{
+			Time:            metav1.MicroTime{Time: eventTime},
+			DataContentType: common.MediaTypeJSON,
+		},
+		Data: []byte(`{"head_commit": {"id": "` + sha + `"}}`),
+	}
+}
+
+func TestApplyCorrelation(t *testing.T) {
+	logger := common.NewArgoEventsLogger()
+	now := time.Now()
+
+	newSensor := func() *v1alpha1.Sensor {
+		obj := sensorObj.DeepCopy()
+		for _, dependency := range obj.Spec.Dependencies {
+			snctrl.InitializeNode(obj, dependency.Name, v1alpha1.NodeTypeEventDependency, logger, "dependency is initialized")
+		}
+		return obj
+	}
+	complete := func(obj *v1alpha1.Sensor, name string, event *apicommon.Event) {
+		snctrl.MarkNodePhase(obj, name, v1alpha1.NodeTypeEventDependency, v1alpha1.NodePhaseComplete, event, logger, "event is received")
+	}
+
+	t.Run("expire events outside the window", func(t *testing.T) {
+		obj := newSensor()
+		obj.Spec.Correlation = &v1alpha1.Correlation{
+			Window: "10m",
+		}
+		complete(obj, "dep-1", newCorrelationEvent(now.Add(-48*time.Hour), "a"))
+		complete(obj, "dep-2", newCorrelationEvent(now.Add(-5*time.Minute), "a"))
+		complete(obj, "dep-3", newCorrelationEvent(now, "a"))
+
+		err := ApplyCorrelation(obj, "dep-3", logger)
+		assert.Nil(t, err)
+		node := snctrl.GetNodeByName(obj, "dep-1")
+		assert.Equal(t, v1alpha1.NodePhaseActive, node.Phase)
+		assert.Nil(t, node.Event)
+		assert.Equal(t, v1alpha1.NodePhaseComplete, snctrl.GetNodeByName(obj, "dep-2").Phase)
+		assert.Equal(t, v1alpha1.NodePhaseComplete, snctrl.GetNodeByName(obj, "dep-3").Phase)
+		assert.False(t, obj.AreAllNodesSuccess(v1alpha1.NodeTypeEventDependency))
+	})
+
+	t.Run("expire events with a different key", func(t *testing.T) {
+		obj := newSensor()
+		obj.Spec.Correlation = &v1alpha1.Correlation{
+			Key: "head_commit.id",
+		}
+		complete(obj, "dep-1", newCorrelationEvent(now, "a"))
+		complete(obj, "dep-2", newCorrelationEvent(now, "b"))
+		complete(obj, "dep-3", newCorrelationEvent(now, "b"))
+
+		err := ApplyCorrelation(obj, "dep-3", logger)
+		assert.Nil(t, err)
+		assert.Equal(t, v1alpha1.NodePhaseActive, snctrl.GetNodeByName(obj, "dep-1").Phase)
+		assert.Equal(t, v1alpha1.NodePhaseComplete, snctrl.GetNodeByName(obj, "dep-2").Phase)
+
+		// the event with the matching key resolves the dependencies
+		complete(obj, "dep-1", newCorrelationEvent(now, "b"))
+		err = ApplyCorrelation(obj, "dep-1", logger)
+		assert.Nil(t, err)
+		assert.True(t, obj.AreAllNodesSuccess(v1alpha1.NodeTypeEventDependency))
+	})
+
+	t.Run("apply the correlation of a group to its dependencies", func(t *testing.T) {
+		obj := newSensor()
+		obj.Spec.DependencyGroups = []v1alpha1.DependencyGroup{
+			{
+				Name:         "group1",
+				Dependencies: []string{"dep-1", "dep-2"},
+				Correlation: &v1alpha1.Correlation{
+					Window: "1h",
+					Key:    "head_commit.id",
+				},
+			},
+		}
+		complete(obj, "dep-1", newCorrelationEvent(now.Add(-2*time.Hour), "a"))
+		complete(obj, "dep-3", newCorrelationEvent(now.Add(-2*time.Hour), "a"))
+		complete(obj, "dep-2", newCorrelationEvent(now, "a"))
+
+		err := ApplyCorrelation(obj, "dep-2", logger)
+		assert.Nil(t, err)
+		assert.Equal(t, v1alpha1.NodePhaseActive, snctrl.GetNodeByName(obj, "dep-1").Phase)
+		// dep-3 isn't part of the group
+		assert.Equal(t, v1alpha1.NodePhaseComplete, snctrl.GetNodeByName(obj, "dep-3").Phase)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		obj := newSensor()
+		obj.Spec.Correlation = &v1alpha1.Correlation{
+			Window: "fake",
+		}
+		complete(obj, "dep-1", newCorrelationEvent(now, "a"))
+		err := ApplyCorrelation(obj, "dep-1", logger)
+		assert.NotNil(t, err)
+	})
+}
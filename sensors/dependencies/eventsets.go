@@ -0,0 +1,155 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependencies
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+)
+
+const (
+	// DefaultMaxEventSets is the maximum number of incomplete event sets held by a sensor if none is specified
+	DefaultMaxEventSets = 100
+	// DefaultEventSetTTL is how long an incomplete event set is held if no TTL is specified
+	DefaultEventSetTTL = time.Hour
+)
+
+// EventSet is a partial match of the events of the dependencies that share a correlation key
+type EventSet struct {
+	// Key is the correlation key of the events
+	Key string `json:"key"`
+	// Events of the set keyed by the dependency name
+	Events map[string]*apicommon.Event `json:"events"`
+	// CreatedAt is the time the first event of the set was received
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewEventSet returns an empty event set for the correlation key
+func NewEventSet(key string, createdAt time.Time) *EventSet {
+	return &EventSet{
+		Key:       key,
+		Events:    make(map[string]*apicommon.Event),
+		CreatedAt: createdAt,
+	}
+}
+
+// EventSetStore holds the incomplete event sets of a sensor keyed by the correlation key
+type EventSetStore interface {
+	// Get returns the event set of the key, nil if there is none
+	Get(key string) (*EventSet, error)
+	// Put stores the event set and returns the sets evicted to stay within the bounds
+	Put(set *EventSet) ([]*EventSet, error)
+	// Remove removes the event set of the key
+	Remove(key string) error
+	// Evict removes and returns the event sets created before the time
+	Evict(before time.Time) ([]*EventSet, error)
+	// Len returns the number of event sets
+	Len() int
+}
+
+// MemoryEventSetStore is an EventSetStore that holds a bounded number of event sets in memory
+type MemoryEventSetStore struct {
+	lock    sync.Mutex
+	sets    map[string]*EventSet
+	maxSets int
+}
+
+// NewMemoryEventSetStore returns an in-memory store that holds at most maxSets event sets
+func NewMemoryEventSetStore(maxSets int) *MemoryEventSetStore {
+	if maxSets <= 0 {
+		maxSets = DefaultMaxEventSets
+	}
+	return &MemoryEventSetStore{
+		sets:    make(map[string]*EventSet),
+		maxSets: maxSets,
+	}
+}
+
+// Get returns the event set of the key
+func (store *MemoryEventSetStore) Get(key string) (*EventSet, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return store.sets[key], nil
+}
+
+// Put stores the event set. The oldest sets are evicted if the store holds more than the maximum number of sets.
+func (store *MemoryEventSetStore) Put(set *EventSet) ([]*EventSet, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.sets[set.Key] = set
+	if len(store.sets) <= store.maxSets {
+		return nil, nil
+	}
+	sets := store.sortedSets()
+	var evicted []*EventSet
+	for _, oldest := range sets {
+		if len(store.sets) <= store.maxSets {
+			break
+		}
+		if oldest.Key == set.Key {
+			continue
+		}
+		delete(store.sets, oldest.Key)
+		evicted = append(evicted, oldest)
+	}
+	return evicted, nil
+}
+
+// Remove removes the event set of the key
+func (store *MemoryEventSetStore) Remove(key string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	delete(store.sets, key)
+	return nil
+}
+
+// Evict removes and returns the event sets created before the time
+func (store *MemoryEventSetStore) Evict(before time.Time) ([]*EventSet, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	var evicted []*EventSet
+	for _, set := range store.sortedSets() {
+		if !set.CreatedAt.Before(before) {
+			break
+		}
+		delete(store.sets, set.Key)
+		evicted = append(evicted, set)
+	}
+	return evicted, nil
+}
+
+// Len returns the number of event sets
+func (store *MemoryEventSetStore) Len() int {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return len(store.sets)
+}
+
+// sortedSets returns the event sets from the oldest to the newest
+func (store *MemoryEventSetStore) sortedSets() []*EventSet {
+	sets := make([]*EventSet, 0, len(store.sets))
+	for _, set := range store.sets {
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		return sets[i].CreatedAt.Before(sets[j].CreatedAt)
+	})
+	return sets
+}
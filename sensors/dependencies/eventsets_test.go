@@ -0,0 +1,72 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependencies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryEventSetStore(t *testing.T) {
+	now := time.Now()
+
+	t.Run("evict the oldest sets on overflow", func(t *testing.T) {
+		store := NewMemoryEventSetStore(2)
+		for i, key := range []string{"a", "b"} {
+			evicted, err := store.Put(NewEventSet(key, now.Add(time.Duration(i)*time.Second)))
+			assert.Nil(t, err)
+			assert.Empty(t, evicted)
+		}
+		evicted, err := store.Put(NewEventSet("c", now.Add(-time.Minute)))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(evicted))
+		assert.Equal(t, "a", evicted[0].Key)
+		assert.Equal(t, 2, store.Len())
+
+		set, err := store.Get("c")
+		assert.Nil(t, err)
+		assert.NotNil(t, set)
+	})
+
+	t.Run("evict the sets that outlived the ttl", func(t *testing.T) {
+		store := NewMemoryEventSetStore(0)
+		_, err := store.Put(NewEventSet("a", now.Add(-2*time.Hour)))
+		assert.Nil(t, err)
+		_, err = store.Put(NewEventSet("b", now))
+		assert.Nil(t, err)
+
+		evicted, err := store.Evict(now.Add(-DefaultEventSetTTL))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(evicted))
+		assert.Equal(t, "a", evicted[0].Key)
+
+		set, err := store.Get("a")
+		assert.Nil(t, err)
+		assert.Nil(t, set)
+		assert.Equal(t, 1, store.Len())
+	})
+
+	t.Run("remove a complete set", func(t *testing.T) {
+		store := NewMemoryEventSetStore(0)
+		_, err := store.Put(NewEventSet("a", now))
+		assert.Nil(t, err)
+		assert.Nil(t, store.Remove("a"))
+		assert.Equal(t, 0, store.Len())
+	})
+}
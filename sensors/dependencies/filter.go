@@ -17,6 +17,7 @@ limitations under the License.
 package dependencies
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -24,23 +25,33 @@ import (
 	"time"
 
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	"github.com/argoproj/argo-events/sensors/types"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"go.opencensus.io/trace"
 )
 
+// ApplyFilter applies the filters of the event dependency on the event of the notification
 func ApplyFilter(notification *types.Notification) error {
+	_, span := trace.StartSpanWithRemoteParent(context.Background(), "sensor.apply_filter", notification.SpanContext)
+	span.AddAttributes(trace.StringAttribute("dependency-name", notification.EventDependency.Name))
+	defer span.End()
+
 	if notification.EventDependency.Filters == nil {
 		return nil
 	}
 	ok, err := filterEvent(notification.EventDependency.Filters, notification.Event)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return err
 	}
 	if !ok {
-		return errors.Errorf("failed to apply filter on Event dependency %s", notification.EventDependency.Name)
+		err := errors.Errorf("failed to apply filter on Event dependency %s", notification.EventDependency.Name)
+		tracing.RecordError(span, err)
+		return err
 	}
 	return nil
 }
@@ -242,13 +242,14 @@ func TestOperateEventNotification(t *testing.T) {
 		Resource: deployment,
 	}
 
-	err := sensorCtx.operateEventNotification(&types.Notification{
+	executed, err := sensorCtx.operateEventNotification(&types.Notification{
 		Event:            event,
 		EventDependency:  &obj.Spec.Dependencies[0],
 		Sensor:           obj,
 		NotificationType: v1alpha1.EventNotification,
 	})
 	assert.Nil(t, err)
+	assert.True(t, executed)
 
 	assert.Equal(t, v1alpha1.NodePhaseComplete, obj.Status.Nodes[dep1].Phase)
 
@@ -288,12 +289,13 @@ func TestOperateEventNotification(t *testing.T) {
 		},
 	}
 
-	err = sensorCtx.operateEventNotification(&types.Notification{
+	executed, err = sensorCtx.operateEventNotification(&types.Notification{
 		Event:            event,
 		EventDependency:  &obj.Spec.Dependencies[0],
 		Sensor:           obj,
 		NotificationType: v1alpha1.EventNotification,
 	})
 	assert.NotNil(t, err)
+	assert.False(t, executed)
 	assert.Equal(t, v1alpha1.NodePhaseError, obj.Status.Nodes[dep1].Phase)
 }
@@ -17,7 +17,11 @@ limitations under the License.
 package sensors
 
 import (
+	"context"
+	"time"
+
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	snctrl "github.com/argoproj/argo-events/controllers/sensor"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	"github.com/argoproj/argo-events/sensors/dependencies"
@@ -26,6 +30,7 @@ import (
 	"github.com/argoproj/argo-events/sensors/types"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
@@ -44,8 +49,9 @@ func isEligibleForExecution(sensor *v1alpha1.Sensor, logger *logrus.Logger) (boo
 	return false, nil
 }
 
-// OperateEventNotifications operates on an event notification
-func (sensorCtx *SensorContext) operateEventNotification(notification *types.Notification) error {
+// OperateEventNotifications operates on an event notification.
+// It returns true if the dependencies are resolved and the triggers were executed.
+func (sensorCtx *SensorContext) operateEventNotification(notification *types.Notification) (bool, error) {
 	nodeName := notification.EventDependency.Name
 	snctrl.MarkNodePhase(sensorCtx.Sensor, nodeName, v1alpha1.NodeTypeEventDependency, v1alpha1.NodePhaseComplete, notification.Event, sensorCtx.Logger, "event is received")
 
@@ -55,75 +61,165 @@ func (sensorCtx *SensorContext) operateEventNotification(notification *types.Not
 	// Apply filters
 	logger.Infoln("applying filters on event notifications if any")
 	if err := dependencies.ApplyFilter(notification); err != nil {
+		filterRejections.WithLabelValues(sensorCtx.Sensor.Name, nodeName).Inc()
+		sensorCtx.deadLetter(sensorCtx.Sensor.Name, notification, "", err)
 		snctrl.MarkNodePhase(sensorCtx.Sensor, nodeName, v1alpha1.NodeTypeEventDependency, v1alpha1.NodePhaseError, nil, sensorCtx.Logger, err.Error())
-		return err
+		return false, err
+	}
+
+	// Resolve the dependencies with the events of the set the event belongs to, if the sensor tracks multiple sets of events
+	var set *dependencies.EventSet
+	if sensorCtx.EventSets != nil {
+		var err error
+		if set, err = sensorCtx.loadEventSet(nodeName, notification.Event); err != nil {
+			return false, err
+		}
+	}
+
+	// Expire the events of the other dependencies that don't correlate with the event
+	if err := dependencies.ApplyCorrelation(sensorCtx.Sensor, nodeName, sensorCtx.Logger); err != nil {
+		return false, err
 	}
 
 	// Apply Circuit if any or check if all dependencies are resolved
 	logger.Infoln("applying circuit logic if any or checking if all dependencies are resolved")
 	ok, err := isEligibleForExecution(sensorCtx.Sensor, sensorCtx.Logger)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if !ok {
+		if set != nil {
+			if err := sensorCtx.saveEventSet(set); err != nil {
+				return false, err
+			}
+		}
 		sensorCtx.Logger.Infoln("dependencies are not yet resolved, won't execute triggers")
-		return nil
+		return false, nil
+	}
+	if set != nil {
+		// the set is complete, the triggers are executed with its events
+		if err := sensorCtx.removeEventSet(set); err != nil {
+			return false, err
+		}
 	}
 
 	logger.Infoln("starting to execute triggers")
-	// Iterate over each trigger,
-	// 1. Apply template level parameters
-	// 2. Check if switches are resolved
-	// 3. Fetch the resource
-	// 4. Apply resource level parameters
-	// 5. If any policy is set, apply it
-	for _, trigger := range sensorCtx.Sensor.Spec.Triggers {
-		if err := triggers.ApplyTemplateParameters(sensorCtx.Sensor, &trigger); err != nil {
+	// the triggers continue the trace of the event that resolved the dependencies
+	ctx, span := trace.StartSpanWithRemoteParent(context.Background(), "sensor.execute_triggers", notification.SpanContext)
+	// the triggers are executed on a snapshot of the sensor, so the dependencies keep being updated meanwhile
+	sensor := sensorCtx.Sensor.DeepCopy()
+	if sensorCtx.triggerJobs != nil {
+		go func() {
+			err := sensorCtx.executeTriggerCycle(ctx, sensor, notification, logger)
+			tracing.RecordError(span, err)
+			span.End()
+			sensorCtx.NotificationQueue <- &types.Notification{
+				NotificationType: v1alpha1.TriggerCycleNotification,
+				TriggerCycleErr:  err,
+			}
+		}()
+		return true, nil
+	}
+	err = sensorCtx.executeTriggerCycle(ctx, sensor, notification, logger)
+	tracing.RecordError(span, err)
+	span.End()
+	return true, err
+}
+
+// executeTrigger fetches the trigger resource, applies the resource parameters, performs the trigger operation on the resource and applies the trigger policy.
+// HTTP, messaging and notification triggers send their request or message instead. The triggers of an offline sensor are only rendered.
+func (sensorCtx *SensorContext) executeTrigger(ctx context.Context, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger, logger *logrus.Entry) error {
+	if sensorCtx.render != nil {
+		return sensorCtx.renderTrigger(sensor, trigger)
+	}
+	if trigger.Template.HTTP != nil {
+		if err := triggers.ExecuteHTTPTrigger(ctx, sensorCtx.KubeClient, sensor, trigger); err != nil {
 			return err
 		}
-		if ok := triggers.ApplySwitches(sensorCtx.Sensor, &trigger); !ok {
-			logger.Infoln("switches/group level when conditions were not resolved, won't execute the trigger")
-			continue
-		}
-		uObj, err := triggers.FetchResource(sensorCtx.KubeClient, sensorCtx.Sensor, &trigger)
-		if err != nil {
+		logger.WithField("trigger-name", trigger.Template.Name).Infoln("http trigger successfully executed")
+		return nil
+	}
+	if trigger.Template.Kafka != nil {
+		if err := triggers.ExecuteKafkaTrigger(ctx, sensor, trigger, sensorCtx.TriggerConnections); err != nil {
 			return err
 		}
-		if uObj == nil {
-			continue
+		logger.WithField("trigger-name", trigger.Template.Name).Infoln("kafka trigger successfully executed")
+		return nil
+	}
+	if trigger.Template.NATS != nil {
+		if err := triggers.ExecuteNATSTrigger(ctx, sensor, trigger, sensorCtx.TriggerConnections); err != nil {
+			return err
 		}
-		if err := triggers.ApplyResourceParameters(sensorCtx.Sensor, trigger.ResourceParameters, uObj); err != nil {
+		logger.WithField("trigger-name", trigger.Template.Name).Infoln("nats trigger successfully executed")
+		return nil
+	}
+	if trigger.Template.AMQP != nil {
+		if err := triggers.ExecuteAMQPTrigger(ctx, sensor, trigger, sensorCtx.TriggerConnections); err != nil {
 			return err
 		}
-		client := sensorCtx.DynamicClient.Resource(schema.GroupVersionResource{
-			Group:    trigger.Template.GroupVersionResource.Group,
-			Version:  trigger.Template.GroupVersionResource.Version,
-			Resource: trigger.Template.GroupVersionResource.Resource,
-		})
-		newObj, err := triggers.Execute(sensorCtx.Sensor, uObj, client)
-		if err != nil {
+		logger.WithField("trigger-name", trigger.Template.Name).Infoln("amqp trigger successfully executed")
+		return nil
+	}
+	if trigger.Template.Slack != nil {
+		if err := triggers.ExecuteSlackTrigger(ctx, sensorCtx.KubeClient, sensor, trigger); err != nil {
 			return err
 		}
-		logger.WithField("trigger-name", trigger.Template.Name).Infoln("trigger successfully created")
+		logger.WithField("trigger-name", trigger.Template.Name).Infoln("slack trigger successfully executed")
+		return nil
+	}
 
-		logger.WithField("trigger-name", trigger.Template.Name).Infoln("applying trigger policy")
-		p := policy.GetPolicy(&trigger, client, newObj)
-		if p == nil {
-			logger.WithField("trigger-name", trigger.Template.Name).Infoln("no trigger policy found, continue...")
-			continue
-		}
-		err = p.ApplyPolicy()
-		if err != nil {
-			switch err {
-			case wait.ErrWaitTimeout:
-				if trigger.Policy.ErrorOnBackoffTimeout {
-					return errors.Errorf("failed to determine status of the triggered resource. setting trigger state as failed")
-				}
-				continue
-			default:
-				return err
+	uObj, err := triggers.FetchResource(sensorCtx.KubeClient, sensor, trigger)
+	if err != nil {
+		return err
+	}
+	if uObj == nil {
+		return nil
+	}
+	if err := triggers.ApplyResourceParameters(sensor, trigger.ResourceParameters, uObj); err != nil {
+		return err
+	}
+	client := sensorCtx.DynamicClient.Resource(schema.GroupVersionResource{
+		Group:    trigger.Template.GroupVersionResource.Group,
+		Version:  trigger.Template.GroupVersionResource.Version,
+		Resource: trigger.Template.GroupVersionResource.Resource,
+	})
+	newObj, err := triggers.Execute(ctx, sensor, trigger, uObj, client)
+	if err != nil {
+		return err
+	}
+	logger.WithField("trigger-name", trigger.Template.Name).WithField("operation", string(trigger.Template.Operation)).Infoln("trigger successfully executed")
+	if newObj == nil {
+		// the resource was deleted, there is nothing to apply the policy on
+		return nil
+	}
+
+	logger.WithField("trigger-name", trigger.Template.Name).Infoln("applying trigger policy")
+	p := policy.GetPolicy(trigger, client, newObj)
+	if p == nil {
+		logger.WithField("trigger-name", trigger.Template.Name).Infoln("no trigger policy found, continue...")
+		return nil
+	}
+	err = p.ApplyPolicy()
+	if err != nil {
+		switch err {
+		case wait.ErrWaitTimeout:
+			if trigger.Policy.ErrorOnBackoffTimeout {
+				return errors.Errorf("failed to determine status of the triggered resource. setting trigger state as failed")
 			}
+			return nil
+		default:
+			return err
 		}
 	}
 	return nil
 }
+
+// recordTriggerExecution records the outcome and the duration of a trigger execution
+func recordTriggerExecution(sensorName, triggerName string, start time.Time, err error) {
+	status := common.MetricStatusSuccess
+	if err != nil {
+		status = common.MetricStatusFailure
+	}
+	triggerExecutions.WithLabelValues(sensorName, triggerName, status).Inc()
+	triggerDuration.WithLabelValues(sensorName, triggerName).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"fmt"
+	"time"
+
+	snctrl "github.com/argoproj/argo-events/controllers/sensor"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/sensors/dependencies"
+)
+
+const (
+	// eventSetExpired is the reason of the eviction of an event set that outlived its TTL
+	eventSetExpired = "expired"
+	// eventSetOverflow is the reason of the eviction of the oldest event set once the store is full
+	eventSetOverflow = "overflow"
+)
+
+// newEventSetStore returns the store of the event sets if the sensor correlates multiple sets of events at a time
+func newEventSetStore(sensor *v1alpha1.Sensor) dependencies.EventSetStore {
+	if sensor.Spec.Correlation == nil || sensor.Spec.Correlation.Sets == nil {
+		return nil
+	}
+	return dependencies.NewMemoryEventSetStore(int(sensor.Spec.Correlation.Sets.MaxSets))
+}
+
+// eventSetTTL returns how long an incomplete event set is held
+func eventSetTTL(sets *v1alpha1.EventSets) time.Duration {
+	if sets.TTL == "" {
+		return dependencies.DefaultEventSetTTL
+	}
+	ttl, err := time.ParseDuration(sets.TTL)
+	if err != nil || ttl <= 0 {
+		return dependencies.DefaultEventSetTTL
+	}
+	return ttl
+}
+
+// loadEventSet adds the event of the dependency to the set of its correlation key and marks the dependency nodes
+// as per the events of the set, so the dependencies are resolved with the events of the set only.
+func (sensorCtx *SensorContext) loadEventSet(dependencyName string, event *apicommon.Event) (*dependencies.EventSet, error) {
+	correlation := sensorCtx.Sensor.Spec.Correlation
+	now := time.Now()
+
+	evicted, err := sensorCtx.EventSets.Evict(now.Add(-eventSetTTL(correlation.Sets)))
+	if err != nil {
+		return nil, err
+	}
+	sensorCtx.recordEvictedEventSets(evicted, eventSetExpired)
+
+	key := dependencies.CorrelationKey(correlation, event)
+	set, err := sensorCtx.EventSets.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		set = dependencies.NewEventSet(key, now)
+	}
+	set.Events[dependencyName] = event
+
+	for _, dependency := range sensorCtx.Sensor.Spec.Dependencies {
+		if setEvent, ok := set.Events[dependency.Name]; ok {
+			snctrl.MarkNodePhase(sensorCtx.Sensor, dependency.Name, v1alpha1.NodeTypeEventDependency, v1alpha1.NodePhaseComplete, setEvent, sensorCtx.Logger, fmt.Sprintf("event is received for the set %s", key))
+			continue
+		}
+		node := snctrl.MarkNodePhase(sensorCtx.Sensor, dependency.Name, v1alpha1.NodeTypeEventDependency, v1alpha1.NodePhaseActive, nil, sensorCtx.Logger, fmt.Sprintf("waiting for an event of the set %s", key))
+		node.Event = nil
+		sensorCtx.Sensor.Status.Nodes[node.ID] = *node
+	}
+	return set, nil
+}
+
+// saveEventSet stores the incomplete event set with the events of the dependency nodes that are still complete
+func (sensorCtx *SensorContext) saveEventSet(set *dependencies.EventSet) error {
+	for name := range set.Events {
+		if node := snctrl.GetNodeByName(sensorCtx.Sensor, name); node == nil || node.Phase != v1alpha1.NodePhaseComplete {
+			delete(set.Events, name)
+		}
+	}
+	evicted, err := sensorCtx.EventSets.Put(set)
+	if err != nil {
+		return err
+	}
+	sensorCtx.recordEvictedEventSets(evicted, eventSetOverflow)
+	eventSetsInFlight.WithLabelValues(sensorCtx.Sensor.Name).Set(float64(sensorCtx.EventSets.Len()))
+	return nil
+}
+
+// removeEventSet removes the complete event set from the store
+func (sensorCtx *SensorContext) removeEventSet(set *dependencies.EventSet) error {
+	if err := sensorCtx.EventSets.Remove(set.Key); err != nil {
+		return err
+	}
+	eventSetsInFlight.WithLabelValues(sensorCtx.Sensor.Name).Set(float64(sensorCtx.EventSets.Len()))
+	return nil
+}
+
+// recordEvictedEventSets logs and counts the evicted event sets
+func (sensorCtx *SensorContext) recordEvictedEventSets(evicted []*dependencies.EventSet, reason string) {
+	for _, set := range evicted {
+		dependencyNames := make([]string, 0, len(set.Events))
+		for name := range set.Events {
+			dependencyNames = append(dependencyNames, name)
+		}
+		sensorCtx.Logger.WithFields(map[string]interface{}{
+			"key":          set.Key,
+			"reason":       reason,
+			"dependencies": dependencyNames,
+		}).Warnln("evicted an incomplete set of events")
+		eventSetsEvicted.WithLabelValues(sensorCtx.Sensor.Name, reason).Inc()
+	}
+	if len(evicted) > 0 {
+		eventSetsInFlight.WithLabelValues(sensorCtx.Sensor.Name).Set(float64(sensorCtx.EventSets.Len()))
+	}
+}
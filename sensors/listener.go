@@ -18,18 +18,36 @@ package sensors
 
 import (
 	"context"
-	"github.com/argoproj/argo-events/sensors/types"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
 	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	"github.com/argoproj/argo-events/sensors/dependencies"
+	"github.com/argoproj/argo-events/sensors/types"
 	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/nats-io/go-nats"
+	snats "github.com/nats-io/go-nats-streaming"
+	"go.opencensus.io/trace"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// WatchEventsFromGateways watches and handles events received from the gateway.
+// ListenEvents watches and handles events received from the gateway.
 func (sensorCtx *SensorContext) ListenEvents() error {
+	// restore the state of the dependencies before processing any event
+	if err := sensorCtx.initStateStore(); err != nil {
+		return err
+	}
+
+	// expose the replay endpoint, if any
+	if err := sensorCtx.startReplayServer(); err != nil {
+		return err
+	}
+
 	// start processing the update Notification NotificationQueue
 	go func() {
 		for e := range sensorCtx.NotificationQueue {
@@ -40,6 +58,19 @@ func (sensorCtx *SensorContext) ListenEvents() error {
 	// sync Sensor resource after updates
 	go sensorCtx.syncSensor(context.Background())
 
+	if sensorCtx.Sensor.Spec.EventProtocol != nil && sensorCtx.Sensor.Spec.EventProtocol.Type == apicommon.NATS {
+		if err := sensorCtx.listenEventsOverNats(); err != nil {
+			return err
+		}
+		// subscriptions deliver the events on their own routines
+		select {}
+	}
+
+	return sensorCtx.listenEventsOverHttp()
+}
+
+// listenEventsOverHttp starts a cloudevents http receiver and blocks until it stops
+func (sensorCtx *SensorContext) listenEventsOverHttp() error {
 	port := common.SensorServerPort
 	if sensorCtx.Sensor.Spec.Port != nil {
 		port = *sensorCtx.Sensor.Spec.Port
@@ -65,6 +96,116 @@ func (sensorCtx *SensorContext) ListenEvents() error {
 	return nil
 }
 
+// listenEventsOverNats connects to nats and subscribes to the subject of every gateway the sensor depends on
+func (sensorCtx *SensorContext) listenEventsOverNats() error {
+	natsConfig := sensorCtx.Sensor.Spec.EventProtocol.Nats
+
+	var err error
+	if sensorCtx.NatsConn == nil {
+		if sensorCtx.NatsConn, err = nats.Connect(natsConfig.URL); err != nil {
+			return fmt.Errorf("failed to obtain NATS standard connection. err: %+v", err)
+		}
+		sensorCtx.Logger.WithField(common.LabelURL, natsConfig.URL).Infoln("connected to nats service")
+	}
+
+	if natsConfig.Type == apicommon.Streaming && sensorCtx.NatsStreamingConn == nil {
+		sensorCtx.NatsStreamingConn, err = snats.Connect(natsConfig.ClusterId, natsConfig.ClientId, snats.NatsConn(sensorCtx.NatsConn))
+		if err != nil {
+			return fmt.Errorf("failed to obtain NATS streaming connection. err: %+v", err)
+		}
+		sensorCtx.Logger.WithField(common.LabelURL, natsConfig.URL).Infoln("nats streaming connection successful")
+	}
+
+	for _, subject := range gatewaySubjects(sensorCtx.Sensor.Spec.Dependencies) {
+		logger := sensorCtx.Logger.WithField(common.LabelNatsSubject, subject)
+
+		switch natsConfig.Type {
+		case apicommon.Streaming:
+			options, err := natsStreamingSubscriptionOptions(&natsConfig, sensorCtx.Sensor.Name)
+			if err != nil {
+				return err
+			}
+			if _, err := sensorCtx.NatsStreamingConn.Subscribe(subject, func(msg *snats.Msg) {
+				sensorCtx.handleNatsMessage(msg.Data)
+			}, options...); err != nil {
+				return fmt.Errorf("failed to subscribe to nats streaming subject %s. err: %+v", subject, err)
+			}
+		default:
+			if _, err := sensorCtx.NatsConn.Subscribe(subject, func(msg *nats.Msg) {
+				sensorCtx.handleNatsMessage(msg.Data)
+			}); err != nil {
+				return fmt.Errorf("failed to subscribe to nats subject %s. err: %+v", subject, err)
+			}
+		}
+
+		logger.Infoln("subscribed to nats subject")
+	}
+
+	return nil
+}
+
+// gatewaySubjects returns the unique nats subjects for the gateways referred by the dependencies
+func gatewaySubjects(eventDependencies []v1alpha1.EventDependency) []string {
+	var subjects []string
+	seen := make(map[string]bool)
+	for _, dependency := range eventDependencies {
+		if seen[dependency.GatewayName] {
+			continue
+		}
+		seen[dependency.GatewayName] = true
+		subjects = append(subjects, common.DefaultNatsSubject(dependency.GatewayName))
+	}
+	return subjects
+}
+
+// natsStreamingSubscriptionOptions translates the nats configuration into subscription options
+func natsStreamingSubscriptionOptions(natsConfig *apicommon.Nats, durableName string) ([]snats.SubscriptionOption, error) {
+	var options []snats.SubscriptionOption
+
+	if natsConfig.Durable {
+		options = append(options, snats.DurableName(durableName))
+	}
+	if natsConfig.DeliverAllAvailable {
+		options = append(options, snats.DeliverAllAvailable())
+	}
+	if natsConfig.StartWithLastReceived {
+		options = append(options, snats.StartWithLastReceived())
+	}
+	if natsConfig.StartAtSequence != "" {
+		sequence, err := strconv.ParseUint(natsConfig.StartAtSequence, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse start at sequence %s. err: %+v", natsConfig.StartAtSequence, err)
+		}
+		options = append(options, snats.StartAtSequence(sequence))
+	}
+	if natsConfig.StartAtTime != "" {
+		startTime, err := time.Parse(common.StandardTimeFormat, natsConfig.StartAtTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse start at time %s. err: %+v", natsConfig.StartAtTime, err)
+		}
+		options = append(options, snats.StartAtTime(startTime))
+	}
+	if natsConfig.StartAtTimeDelta != "" {
+		delta, err := time.ParseDuration(natsConfig.StartAtTimeDelta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse start at time delta %s. err: %+v", natsConfig.StartAtTimeDelta, err)
+		}
+		options = append(options, snats.StartAtTimeDelta(delta))
+	}
+
+	return options, nil
+}
+
+// handleNatsMessage decodes a structured cloudevent received over nats and handles it
+func (sensorCtx *SensorContext) handleNatsMessage(data []byte) {
+	event := cloudevents.Event{}
+	if err := json.Unmarshal(data, &event); err != nil {
+		sensorCtx.Logger.WithError(err).Errorln("failed to decode the cloud event received over nats")
+		return
+	}
+	sensorCtx.handleEvent(context.Background(), &event)
+}
+
 func cloudEventConverter(event *cloudevents.Event) (*apicommon.Event, error) {
 	data, err := event.DataBytes()
 	if err != nil {
@@ -86,8 +227,16 @@ func cloudEventConverter(event *cloudevents.Event) (*apicommon.Event, error) {
 
 // handleEvent handles a cloudevent, validates and sends it over internal Event NotificationQueue
 func (sensorCtx *SensorContext) handleEvent(ctx context.Context, event *cloudevents.Event) bool {
+	// continue the trace of the gateway, if any. The trace parent is left empty if the event doesn't carry it.
+	var traceParent string
+	_ = event.ExtensionAs(tracing.TraceParentExtension, &traceParent)
+	_, span := tracing.StartSpanWithTraceParent(ctx, "sensor.handle_event", traceParent)
+	span.AddAttributes(trace.StringAttribute(common.LabelEventSource, event.Source()))
+	defer span.End()
+
 	internalEvent, err := cloudEventConverter(event)
 	if err != nil {
+		tracing.RecordError(span, err)
 		sensorCtx.Logger.WithError(err).Errorln("failed to parse the cloud event payload")
 		return false
 	}
@@ -99,6 +248,7 @@ func (sensorCtx *SensorContext) handleEvent(ctx context.Context, event *cloudeve
 			Event:            internalEvent,
 			EventDependency:  eventDependency,
 			NotificationType: v1alpha1.EventNotification,
+			SpanContext:      span.SpanContext(),
 		}
 		return true
 	}
@@ -18,13 +18,20 @@ package sensors
 
 import (
 	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
 	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	"github.com/argoproj/argo-events/sensors/types"
 	cloudevents "github.com/cloudevents/sdk-go"
+	natstest "github.com/nats-io/gnatsd/test"
+	"github.com/nats-io/go-nats"
 	"github.com/stretchr/testify/assert"
-	"testing"
-	"time"
+	"go.opencensus.io/trace"
 )
 
 func TestHandleEvent(t *testing.T) {
@@ -93,3 +100,146 @@ func TestHandleEvent(t *testing.T) {
 
 	done <- struct{}{}
 }
+
+func TestHandleEventTraceContext(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracing.SetExporter(exporter)
+	defer tracing.SetExporter(&tracing.LogExporter{})
+
+	obj := sensorObj.DeepCopy()
+	obj.Spec.Dependencies = []v1alpha1.EventDependency{
+		{
+			Name:        "dep1",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-1",
+		},
+	}
+
+	_, gatewaySpan := trace.StartSpan(context.Background(), "gateway.dispatch_event")
+
+	event := cloudevents.NewEvent(cloudevents.VersionV03)
+	event.SetID("1")
+	event.SetSource("webhook-gateway")
+	event.SetSubject("example-1")
+	event.SetType("webhook")
+	event.SetDataContentType(common.MediaTypeJSON)
+	event.SetTime(time.Now())
+	event.SetExtension(tracing.TraceParentExtension, tracing.TraceParent(gatewaySpan.SpanContext()))
+
+	queue := make(chan *types.Notification, 1)
+	sensorCtx := &SensorContext{
+		Sensor:            obj,
+		NotificationQueue: queue,
+	}
+
+	ok := sensorCtx.handleEvent(context.Background(), &event)
+	assert.True(t, ok)
+
+	notification := <-queue
+	assert.Equal(t, gatewaySpan.SpanContext().TraceID, notification.SpanContext.TraceID)
+
+	spans := exporter.SpansByName("sensor.handle_event")
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, gatewaySpan.SpanContext().SpanID, spans[0].ParentSpanID)
+}
+
+func TestGatewaySubjects(t *testing.T) {
+	subjects := gatewaySubjects([]v1alpha1.EventDependency{
+		{
+			Name:        "dep1",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-1",
+		},
+		{
+			Name:        "dep2",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-2",
+		},
+		{
+			Name:        "dep3",
+			GatewayName: "calendar-gateway",
+			EventName:   "example-1",
+		},
+	})
+	assert.Equal(t, []string{"webhook-gateway-events", "calendar-gateway-events"}, subjects)
+}
+
+func TestNatsStreamingSubscriptionOptions(t *testing.T) {
+	options, err := natsStreamingSubscriptionOptions(&apicommon.Nats{
+		Durable:             true,
+		DeliverAllAvailable: true,
+		StartAtSequence:     "10",
+		StartAtTimeDelta:    "30s",
+	}, "fake-sensor")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(options))
+
+	_, err = natsStreamingSubscriptionOptions(&apicommon.Nats{
+		StartAtSequence: "not-a-number",
+	}, "fake-sensor")
+	assert.NotNil(t, err)
+
+	_, err = natsStreamingSubscriptionOptions(&apicommon.Nats{
+		StartAtTime: "yesterday",
+	}, "fake-sensor")
+	assert.NotNil(t, err)
+}
+
+func TestListenEventsOverNats(t *testing.T) {
+	opts := natstest.DefaultTestOptions
+	opts.Port = 14222
+	server := natstest.RunServer(&opts)
+	defer server.Shutdown()
+
+	obj := sensorObj.DeepCopy()
+	obj.Spec.Dependencies = []v1alpha1.EventDependency{
+		{
+			Name:        "dep1",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-1",
+		},
+	}
+	obj.Spec.EventProtocol = &apicommon.EventProtocol{
+		Type: apicommon.NATS,
+		Nats: apicommon.Nats{
+			URL:  "nats://localhost:14222",
+			Type: apicommon.Standard,
+		},
+	}
+
+	queue := make(chan *types.Notification)
+	sensorCtx := &SensorContext{
+		Sensor:            obj,
+		NotificationQueue: queue,
+		Logger:            common.NewArgoEventsLogger(),
+	}
+
+	err := sensorCtx.listenEventsOverNats()
+	assert.Nil(t, err)
+	defer sensorCtx.NatsConn.Close()
+
+	event := cloudevents.NewEvent(cloudevents.VersionV03)
+	event.SetID("1")
+	event.SetSource("webhook-gateway")
+	event.SetSubject("example-1")
+	event.SetType("webhook")
+	event.SetDataContentType(common.MediaTypeJSON)
+	event.SetTime(time.Now())
+	payload, err := json.Marshal(&event)
+	assert.Nil(t, err)
+
+	conn, err := nats.Connect("nats://localhost:14222")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	err = conn.Publish(common.DefaultNatsSubject("webhook-gateway"), payload)
+	assert.Nil(t, err)
+
+	select {
+	case notification := <-queue:
+		assert.Equal(t, "dep1", notification.EventDependency.Name)
+		assert.Equal(t, "1", notification.Event.Context.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the event over nats")
+	}
+}
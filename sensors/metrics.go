@@ -0,0 +1,77 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"github.com/argoproj/argo-events/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// filterRejections counts the events rejected by the filters of each dependency
+	filterRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "sensor",
+		Name:      "filter_rejections_total",
+		Help:      "Number of events rejected by the dependency filters",
+	}, []string{common.MetricLabelSensorName, common.MetricLabelDependencyName})
+
+	// triggerExecutions counts the successful and failed executions of each trigger template
+	triggerExecutions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "sensor",
+		Name:      "trigger_executions_total",
+		Help:      "Number of trigger executions, by outcome",
+	}, []string{common.MetricLabelSensorName, common.MetricLabelTriggerName, common.MetricLabelStatus})
+
+	// triggerDuration observes how long each trigger template takes to execute
+	triggerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "sensor",
+		Name:      "trigger_duration_seconds",
+		Help:      "Time taken to execute a trigger",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{common.MetricLabelSensorName, common.MetricLabelTriggerName})
+
+	// eventSetsInFlight is the number of incomplete sets of correlated events held by each sensor
+	eventSetsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "sensor",
+		Name:      "event_sets_in_flight",
+		Help:      "Number of incomplete sets of correlated events",
+	}, []string{common.MetricLabelSensorName})
+
+	// eventSetsEvicted counts the incomplete sets of correlated events evicted by each sensor
+	eventSetsEvicted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "sensor",
+		Name:      "event_sets_evicted_total",
+		Help:      "Number of incomplete sets of correlated events evicted, by reason",
+	}, []string{common.MetricLabelSensorName, common.MetricLabelReason})
+
+	// deadLetterRecords counts the records sent to the dead-letter sink of each sensor
+	deadLetterRecords = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: common.MetricsNamespace,
+		Subsystem: "sensor",
+		Name:      "dead_letter_records_total",
+		Help:      "Number of records sent to the dead-letter sink",
+	}, []string{common.MetricLabelSensorName, common.MetricLabelStatus})
+)
+
+func init() {
+	prometheus.MustRegister(filterRejections, triggerExecutions, triggerDuration, eventSetsInFlight, eventSetsEvicted, deadLetterRecords)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	sensorFake "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned/fake"
+	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	dfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// sampleCount returns the number of observations made by the histogram
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	metric := &dto.Metric{}
+	assert.Nil(t, observer.(prometheus.Histogram).Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+// runMetricsSensor resolves the dependencies of a sensor with a single http trigger
+func runMetricsSensor(t *testing.T, obj *v1alpha1.Sensor) *SensorContext {
+	sensorClient := sensorFake.NewSimpleClientset()
+	dynamicClient := dfake.NewSimpleDynamicClient(runtime.NewScheme())
+	k8sClient := fake.NewSimpleClientset()
+	obj.Spec.Triggers = obj.Spec.Triggers[:1]
+	newObj, err := sensorClient.ArgoprojV1alpha1().Sensors(obj.Namespace).Create(obj)
+	assert.Nil(t, err)
+	sensorCtx := NewSensorContext(sensorClient, k8sClient, dynamicClient, newObj.DeepCopy(), "1")
+
+	for i := range obj.Spec.Dependencies {
+		sensorCtx.processQueue(&types.Notification{
+			Event:            newTriggerWorkersEvent(),
+			EventDependency:  &obj.Spec.Dependencies[i],
+			NotificationType: v1alpha1.EventNotification,
+			Attempts:         1,
+		})
+	}
+	return sensorCtx
+}
+
+func TestTriggerMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	obj := newTriggerWorkersSensor(server.URL)
+	success := triggerExecutions.WithLabelValues(obj.Name, "slow-trigger", common.MetricStatusSuccess)
+	failure := triggerExecutions.WithLabelValues(obj.Name, "slow-trigger", common.MetricStatusFailure)
+	duration := triggerDuration.WithLabelValues(obj.Name, "slow-trigger")
+	successBefore, failureBefore := testutil.ToFloat64(success), testutil.ToFloat64(failure)
+	observations := sampleCount(t, duration)
+
+	sensorCtx := runMetricsSensor(t, obj)
+	assert.Equal(t, v1alpha1.TriggerCycleSuccess, sensorCtx.Sensor.Status.TriggerCycleStatus)
+	assert.Equal(t, successBefore+1, testutil.ToFloat64(success))
+	assert.Equal(t, failureBefore, testutil.ToFloat64(failure))
+	assert.Equal(t, observations+1, sampleCount(t, duration))
+
+	// the trigger fails as the endpoint is not reachable
+	sensorCtx = runMetricsSensor(t, newTriggerWorkersSensor("http://127.0.0.1:1"))
+	assert.Equal(t, v1alpha1.TriggerCycleFailure, sensorCtx.Sensor.Status.TriggerCycleStatus)
+	assert.Equal(t, successBefore+1, testutil.ToFloat64(success))
+	assert.Equal(t, failureBefore+1, testutil.ToFloat64(failure))
+	assert.Equal(t, observations+2, sampleCount(t, duration))
+}
+
+func TestFilterAndDeadLetterMetrics(t *testing.T) {
+	obj := newTriggerWorkersSensor("http://127.0.0.1:1")
+	obj.Spec.DeadLetter = &v1alpha1.DeadLetterSink{
+		ConfigMap: &v1alpha1.ConfigMapDeadLetterSink{},
+	}
+	obj.Spec.Dependencies[1].Filters = &v1alpha1.EventDependencyFilter{
+		Data: []v1alpha1.DataFilter{
+			{
+				Path:  "name",
+				Type:  "string",
+				Value: []string{"fake"},
+			},
+		},
+	}
+	rejections := filterRejections.WithLabelValues(obj.Name, "dep2")
+	records := deadLetterRecords.WithLabelValues(obj.Name, common.MetricStatusSuccess)
+	rejectionsBefore, recordsBefore := testutil.ToFloat64(rejections), testutil.ToFloat64(records)
+
+	// the event of the second dependency is rejected and sent to the dead-letter sink
+	runMetricsSensor(t, obj)
+	assert.Equal(t, rejectionsBefore+1, testutil.ToFloat64(rejections))
+	assert.Equal(t, recordsBefore+1, testutil.ToFloat64(records))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/triggers"
+	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TriggerRenderer receives what a trigger of an offline sensor would create or send, in place of the execution of the trigger
+type TriggerRenderer func(trigger *v1alpha1.Trigger, rendered *triggers.Rendered)
+
+// NewOfflineSensorContext returns a sensor context that processes the notifications the way the sensor does, without a cluster:
+// the updates of the sensor are kept in memory, the triggers are rendered and passed to render instead of being executed, and the
+// events rejected by the filters or whose triggers failed are sent to deadLetter, if any. The triggers of a cycle are executed one after
+// the other, and the cycle stops at the first failure.
+// The kubernetes client is only used to read the trigger sources that live in the cluster, it can be nil otherwise.
+func NewOfflineSensorContext(kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor, logger *logrus.Logger, deadLetter deadletter.Sink, render TriggerRenderer) *SensorContext {
+	return &SensorContext{
+		KubeClient: kubeClient,
+		Sensor:     sensor,
+		Logger:     logger,
+		EventSets:  newEventSetStore(sensor),
+		DeadLetter: deadLetter,
+		render:     render,
+	}
+}
+
+// ProcessNotification processes the notification the way the sensor processes the notifications of its queue
+func (sensorCtx *SensorContext) ProcessNotification(notification *types.Notification) {
+	sensorCtx.processQueue(notification)
+}
+
+// renderTrigger renders the trigger of an offline sensor and passes it to the renderer
+func (sensorCtx *SensorContext) renderTrigger(sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger) error {
+	rendered, err := triggers.Render(sensorCtx.KubeClient, sensor, trigger)
+	if err != nil {
+		return err
+	}
+	sensorCtx.render(trigger, rendered)
+	return nil
+}
@@ -25,14 +25,7 @@ import (
 
 // processQueue processes events received on internal queue and updates the state of the node representing the event dependency
 func (sensorCtx *SensorContext) processQueue(notification *types.Notification) {
-	defer func() {
-		updatedSensor, err := snctrl.PersistUpdates(sensorCtx.SensorClient, sensorCtx.Sensor, sensorCtx.Logger)
-		if err != nil {
-			sensorCtx.Logger.WithError(err).Error("failed to persist sensor update")
-		}
-		// update Sensor ref. in case of failure to persist updates, this is a deep copy of old Sensor resource
-		sensorCtx.Sensor = updatedSensor
-	}()
+	defer sensorCtx.persistUpdates()
 
 	switch notification.NotificationType {
 	case v1alpha1.EventNotification:
@@ -41,18 +34,14 @@ func (sensorCtx *SensorContext) processQueue(notification *types.Notification) {
 			return
 		}
 
-		err := sensorCtx.operateEventNotification(notification)
-		if err != nil {
-			sensorCtx.Logger.WithError(err).Errorln("failed to operate on the event notification")
-			sensorCtx.Sensor.Status.TriggerCycleStatus = v1alpha1.TriggerCycleFailure
-		} else {
-			sensorCtx.Sensor.Status.TriggerCycleStatus = v1alpha1.TriggerCycleSuccess
+		executed, err := sensorCtx.operateEventNotification(notification)
+		if err == nil && !executed {
+			// the dependencies wait for the other events of the cycle
+			return
+		}
+		if err != nil || sensorCtx.triggerJobs == nil {
+			sensorCtx.recordTriggerCycle(err)
 		}
-
-		// increment completion counter
-		sensorCtx.Sensor.Status.TriggerCycleCount = sensorCtx.Sensor.Status.TriggerCycleCount + 1
-		// set completion time
-		sensorCtx.Sensor.Status.LastCycleTime = metav1.Now()
 
 		// Mark all dependency nodes as active
 		for _, dependency := range sensorCtx.Sensor.Spec.Dependencies {
@@ -63,6 +52,9 @@ func (sensorCtx *SensorContext) processQueue(notification *types.Notification) {
 			snctrl.MarkNodePhase(sensorCtx.Sensor, group.Name, v1alpha1.NodeTypeDependencyGroup, v1alpha1.NodePhaseActive, nil, sensorCtx.Logger, "dependency group is re-activated")
 		}
 
+	case v1alpha1.TriggerCycleNotification:
+		sensorCtx.recordTriggerCycle(notification.TriggerCycleErr)
+
 	case v1alpha1.ResourceUpdateNotification:
 		sensorCtx.operateResourceUpdateNotification(notification)
 
@@ -70,3 +62,18 @@ func (sensorCtx *SensorContext) processQueue(notification *types.Notification) {
 		sensorCtx.Logger.WithField("Notification-type", string(notification.NotificationType)).Error("unknown Notification type")
 	}
 }
+
+// recordTriggerCycle records the outcome of a trigger cycle in the sensor status
+func (sensorCtx *SensorContext) recordTriggerCycle(err error) {
+	if err != nil {
+		sensorCtx.Logger.WithError(err).Errorln("failed to operate on the event notification")
+		sensorCtx.Sensor.Status.TriggerCycleStatus = v1alpha1.TriggerCycleFailure
+	} else {
+		sensorCtx.Sensor.Status.TriggerCycleStatus = v1alpha1.TriggerCycleSuccess
+	}
+
+	// increment completion counter
+	sensorCtx.Sensor.Status.TriggerCycleCount = sensorCtx.Sensor.Status.TriggerCycleCount + 1
+	// set completion time
+	sensorCtx.Sensor.Status.LastCycleTime = metav1.Now()
+}
@@ -113,3 +113,146 @@ func TestProcessQueue(t *testing.T) {
 	})
 	assert.Equal(t, int32(1), sensorCtx.Sensor.Status.TriggerCycleCount)
 }
+
+func TestProcessQueueWaitsForAllDependencies(t *testing.T) {
+	sensorClient := sensorFake.NewSimpleClientset()
+	dynamicClient := dfake.NewSimpleDynamicClient(runtime.NewScheme())
+	k8sClient := fake.NewSimpleClientset()
+	obj := sensorObj.DeepCopy()
+	obj.Spec.Dependencies = []v1alpha1.EventDependency{
+		{
+			Name:        "dep1",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-1",
+		},
+		{
+			Name:        "dep2",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-2",
+		},
+	}
+	obj.Status.Nodes = map[string]v1alpha1.NodeStatus{}
+	for _, dependency := range obj.Spec.Dependencies {
+		id := obj.NodeID(dependency.Name)
+		obj.Status.Nodes[id] = v1alpha1.NodeStatus{
+			ID:          id,
+			Name:        dependency.Name,
+			DisplayName: dependency.Name,
+			Type:        v1alpha1.NodeTypeEventDependency,
+			Phase:       v1alpha1.NodePhaseActive,
+		}
+	}
+	obj.Spec.Triggers[0].Template.Source = &v1alpha1.ArtifactLocation{
+		Resource: newUnstructured("apps/v1", "Deployment", "fake", "fake-deployment"),
+	}
+
+	newObj, err := sensorClient.ArgoprojV1alpha1().Sensors(obj.Namespace).Create(obj)
+	assert.Nil(t, err)
+	sensorCtx := NewSensorContext(sensorClient, k8sClient, dynamicClient, newObj.DeepCopy(), "1")
+
+	event := &apicommon.Event{
+		Context: apicommon.EventContext{
+			DataContentType: "application/json",
+			Source:          "webhook-gateway",
+			Time:            metav1.MicroTime{Time: time.Now()},
+		},
+		Data: []byte("{}"),
+	}
+
+	// the event of the first dependency waits for the event of the second one
+	sensorCtx.processQueue(&types.Notification{
+		Event:            event,
+		EventDependency:  &obj.Spec.Dependencies[0],
+		NotificationType: v1alpha1.EventNotification,
+	})
+	assert.Equal(t, int32(0), sensorCtx.Sensor.Status.TriggerCycleCount)
+	assert.Equal(t, v1alpha1.NodePhaseComplete, sensorCtx.Sensor.Status.Nodes[obj.NodeID("dep1")].Phase)
+
+	sensorCtx.processQueue(&types.Notification{
+		Event:            event,
+		EventDependency:  &obj.Spec.Dependencies[1],
+		NotificationType: v1alpha1.EventNotification,
+	})
+	assert.Equal(t, int32(1), sensorCtx.Sensor.Status.TriggerCycleCount)
+	assert.Equal(t, v1alpha1.TriggerCycleSuccess, sensorCtx.Sensor.Status.TriggerCycleStatus)
+	assert.Equal(t, v1alpha1.NodePhaseActive, sensorCtx.Sensor.Status.Nodes[obj.NodeID("dep1")].Phase)
+}
+
+func TestProcessQueueCorrelatesEventSets(t *testing.T) {
+	sensorClient := sensorFake.NewSimpleClientset()
+	dynamicClient := dfake.NewSimpleDynamicClient(runtime.NewScheme())
+	k8sClient := fake.NewSimpleClientset()
+	obj := sensorObj.DeepCopy()
+	obj.Spec.Dependencies = []v1alpha1.EventDependency{
+		{
+			Name:        "dep1",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-1",
+		},
+		{
+			Name:        "dep2",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-2",
+		},
+	}
+	obj.Spec.Correlation = &v1alpha1.Correlation{
+		Key:  "sha",
+		Sets: &v1alpha1.EventSets{},
+	}
+	obj.Status.Nodes = map[string]v1alpha1.NodeStatus{}
+	for _, dependency := range obj.Spec.Dependencies {
+		id := obj.NodeID(dependency.Name)
+		obj.Status.Nodes[id] = v1alpha1.NodeStatus{
+			ID:          id,
+			Name:        dependency.Name,
+			DisplayName: dependency.Name,
+			Type:        v1alpha1.NodeTypeEventDependency,
+			Phase:       v1alpha1.NodePhaseActive,
+		}
+	}
+	obj.Spec.Triggers[0].Template.Source = &v1alpha1.ArtifactLocation{
+		Resource: newUnstructured("apps/v1", "Deployment", "fake", "fake-deployment"),
+	}
+
+	newObj, err := sensorClient.ArgoprojV1alpha1().Sensors(obj.Namespace).Create(obj)
+	assert.Nil(t, err)
+	sensorCtx := NewSensorContext(sensorClient, k8sClient, dynamicClient, newObj.DeepCopy(), "1")
+	assert.NotNil(t, sensorCtx.EventSets)
+
+	newEvent := func(sha string) *apicommon.Event {
+		return &apicommon.Event{
+			Context: apicommon.EventContext{
+				DataContentType: "application/json",
+				Source:          "webhook-gateway",
+				Time:            metav1.MicroTime{Time: time.Now()},
+			},
+			Data: []byte(`{"sha": "` + sha + `"}`),
+		}
+	}
+	send := func(dependency int, sha string) {
+		sensorCtx.processQueue(&types.Notification{
+			Event:            newEvent(sha),
+			EventDependency:  &obj.Spec.Dependencies[dependency],
+			NotificationType: v1alpha1.EventNotification,
+		})
+	}
+
+	// the events of two commits interleave, each commit is a set of its own
+	send(0, "a")
+	send(0, "b")
+	assert.Equal(t, int32(0), sensorCtx.Sensor.Status.TriggerCycleCount)
+	assert.Equal(t, 2, sensorCtx.EventSets.Len())
+
+	send(1, "a")
+	assert.Equal(t, int32(1), sensorCtx.Sensor.Status.TriggerCycleCount)
+	assert.Equal(t, 1, sensorCtx.EventSets.Len())
+
+	set, err := sensorCtx.EventSets.Get("b")
+	assert.Nil(t, err)
+	assert.NotNil(t, set)
+	assert.Contains(t, set.Events, "dep1")
+
+	send(1, "b")
+	assert.Equal(t, int32(2), sensorCtx.Sensor.Status.TriggerCycleCount)
+	assert.Equal(t, 0, sensorCtx.EventSets.Len())
+}
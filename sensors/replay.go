@@ -0,0 +1,206 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/dependencies"
+	"github.com/argoproj/argo-events/sensors/replay"
+	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/argoproj/argo-events/store"
+	"github.com/pkg/errors"
+)
+
+// maxReplayRequestSize is the size limit of the body of a replay request
+const maxReplayRequestSize = 16 * 1024 * 1024
+
+// startReplayServer starts the admin endpoint that re-injects past events into the dependencies, if the sensor has one
+func (sensorCtx *SensorContext) startReplayServer() error {
+	replaySpec := sensorCtx.Sensor.Spec.Replay
+	if replaySpec == nil {
+		return nil
+	}
+	token, err := store.GetSecrets(sensorCtx.KubeClient, sensorCtx.Sensor.Namespace, replaySpec.Token.Name, replaySpec.Token.Key)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the token of the replay endpoint")
+	}
+	if token == "" {
+		return errors.New("token of the replay endpoint is empty")
+	}
+	port := replaySpec.Port
+	if port == 0 {
+		port = common.SensorReplayPort
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(replay.Endpoint, sensorCtx.replayHandler(token))
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	go func() {
+		sensorCtx.Logger.WithField("port", port).Infoln("starting the replay endpoint")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			sensorCtx.Logger.WithError(err).Errorln("replay endpoint stopped")
+		}
+	}()
+	return nil
+}
+
+// replayHandler returns the handler of the replay requests authenticated with the bearer token
+func (sensorCtx *SensorContext) replayHandler(token string) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			writer.Header().Set("Allow", http.MethodPost)
+			writeReplayResponse(writer, http.StatusMethodNotAllowed, &replay.Response{Error: "only POST is allowed"})
+			return
+		}
+
+		auth := request.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			writer.Header().Set("WWW-Authenticate", "Bearer")
+			writeReplayResponse(writer, http.StatusUnauthorized, &replay.Response{Error: "bearer token is missing"})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+			writeReplayResponse(writer, http.StatusForbidden, &replay.Response{Error: "bearer token is invalid"})
+			return
+		}
+
+		replayRequest := &replay.Request{}
+		if err := json.NewDecoder(io.LimitReader(request.Body, maxReplayRequestSize)).Decode(replayRequest); err != nil {
+			writeReplayResponse(writer, http.StatusBadRequest, &replay.Response{Error: fmt.Sprintf("failed to decode the request. err: %+v", err)})
+			return
+		}
+
+		status, response := sensorCtx.replay(replayRequest)
+		writeReplayResponse(writer, status, response)
+	})
+}
+
+// replay resolves the event and the dependency of the request and either applies the filters of the dependency
+// on the event for a dry run, or sends the event on the notification queue.
+func (sensorCtx *SensorContext) replay(request *replay.Request) (int, *replay.Response) {
+	event := request.Event
+	attempts := request.Attempts
+	dependencyName := request.Dependency
+
+	if request.RecordID != "" {
+		reader, ok := sensorCtx.DeadLetter.(deadletter.Reader)
+		if !ok {
+			return http.StatusBadRequest, &replay.Response{Error: "the dead-letter sink of the sensor can't be read back, send the event instead"}
+		}
+		record, err := findDeadLetterRecord(reader, request.RecordID)
+		if err != nil {
+			return http.StatusInternalServerError, &replay.Response{Error: err.Error()}
+		}
+		if record == nil {
+			return http.StatusNotFound, &replay.Response{Error: fmt.Sprintf("dead-letter record %s not found", request.RecordID)}
+		}
+		event = record.Event
+		attempts = record.Attempts
+		if dependencyName == "" {
+			dependencyName = record.Dependency
+		}
+	}
+	if event == nil {
+		return http.StatusBadRequest, &replay.Response{Error: "either a record id or an event must be specified"}
+	}
+	if dependencyName == "" {
+		return http.StatusBadRequest, &replay.Response{Error: "dependency must be specified"}
+	}
+
+	dependency := lookupDependency(sensorCtx.Sensor.Spec.Dependencies, dependencyName)
+	if dependency == nil {
+		return http.StatusNotFound, &replay.Response{Error: fmt.Sprintf("dependency %s not found", dependencyName)}
+	}
+
+	logger := sensorCtx.Logger.WithFields(map[string]interface{}{
+		"dependency-name": dependency.Name,
+		"event-id":        event.Context.ID,
+		"dry-run":         request.DryRun,
+	})
+
+	notification := &types.Notification{
+		Event:            event,
+		EventDependency:  dependency,
+		NotificationType: v1alpha1.EventNotification,
+		Attempts:         attempts,
+	}
+	response := &replay.Response{
+		Dependency: dependency.Name,
+		EventID:    event.Context.ID,
+		DryRun:     request.DryRun,
+	}
+
+	if request.DryRun {
+		if err := dependencies.ApplyFilter(notification); err != nil {
+			response.Message = fmt.Sprintf("event is rejected by the filters of the dependency. err: %+v", err)
+		} else {
+			response.Passed = true
+			response.Message = "event passes the filters of the dependency"
+		}
+		logger.Infoln("replayed the event as a dry run")
+		return http.StatusOK, response
+	}
+
+	sensorCtx.NotificationQueue <- notification
+	response.Passed = true
+	response.Message = "event is queued for processing"
+	logger.Infoln("replayed the event")
+	return http.StatusAccepted, response
+}
+
+// findDeadLetterRecord reads the record from the dead-letter sink. It returns nil if the record doesn't exist.
+func findDeadLetterRecord(reader deadletter.Reader, id string) (*deadletter.Record, error) {
+	records, err := reader.Records()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the dead-letter records")
+	}
+	for _, record := range records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+// lookupDependency returns a copy of the dependency with the name, nil if the sensor has no such dependency
+func lookupDependency(eventDependencies []v1alpha1.EventDependency, name string) *v1alpha1.EventDependency {
+	for _, dependency := range eventDependencies {
+		if dependency.Name == name {
+			return dependency.DeepCopy()
+		}
+	}
+	return nil
+}
+
+// writeReplayResponse writes the response as JSON with the status code
+func writeReplayResponse(writer http.ResponseWriter, status int, response *replay.Response) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/pkg/errors"
+)
+
+// Endpoint is the path of the replay endpoint of the sensor
+const Endpoint = "/replay"
+
+// Request re-injects a past event into a dependency of the sensor
+type Request struct {
+	// Dependency is the name of the dependency the event is replayed to.
+	// Defaults to the dependency of the dead-letter record.
+	Dependency string `json:"dependency,omitempty"`
+	// RecordID is the ID of the record in the dead-letter sink of the sensor to replay.
+	// Either the record ID or the event must be set.
+	RecordID string `json:"recordId,omitempty"`
+	// Event to replay, e.g. read from an event log
+	Event *apicommon.Event `json:"event,omitempty"`
+	// Attempts is the number of earlier attempts to process the event. Only used along with the event.
+	Attempts int `json:"attempts,omitempty"`
+	// DryRun applies the filters of the dependency on the event without processing it
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Response is the outcome of a replay request
+type Response struct {
+	// Dependency the event was replayed to
+	Dependency string `json:"dependency,omitempty"`
+	// EventID is the ID of the event that was replayed
+	EventID string `json:"eventId,omitempty"`
+	// DryRun tells whether the event was only checked against the filters
+	DryRun bool `json:"dryRun,omitempty"`
+	// Passed tells whether the event passed the filters of the dependency
+	Passed bool `json:"passed"`
+	// Message describes the outcome
+	Message string `json:"message,omitempty"`
+	// Error of the request, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Client submits replay requests to the replay endpoint of a sensor
+type Client struct {
+	// URL of the sensor, e.g. http://my-sensor.argo-events.svc:12100
+	URL string
+	// Token is the bearer token of the endpoint
+	Token string
+	// HTTPClient sends the requests
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client of the replay endpoint at the URL
+func NewClient(url, token string) *Client {
+	return &Client{
+		URL:   strings.TrimSuffix(url, "/"),
+		Token: token,
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Submit sends the request to the sensor and returns its response
+func (client *Client) Submit(request *Request) (*Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode the replay request")
+	}
+	req, err := http.NewRequest(http.MethodPost, client.URL+Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+client.Token)
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to send the replay request to %s", client.URL)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the replay response")
+	}
+	response := &Response{}
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return nil, fmt.Errorf("replay request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return response, fmt.Errorf("replay request failed with status %d: %s", resp.StatusCode, response.Error)
+	}
+	return response, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	sensorFake "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned/fake"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/replay"
+	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	dfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReplayHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	obj := newTriggerWorkersSensor("http://127.0.0.1:1")
+	obj.Spec.DeadLetter = &v1alpha1.DeadLetterSink{
+		File: &v1alpha1.FileDeadLetterSink{
+			Path: filepath.Join(dir, "records"),
+		},
+	}
+	obj.Spec.Dependencies[1].Filters = &v1alpha1.EventDependencyFilter{
+		Data: []v1alpha1.DataFilter{
+			{
+				Path:  "name",
+				Type:  "string",
+				Value: []string{"fake"},
+			},
+		},
+	}
+	sensorCtx := NewSensorContext(sensorFake.NewSimpleClientset(), fake.NewSimpleClientset(), dfake.NewSimpleDynamicClient(runtime.NewScheme()), obj, "1")
+	sensorCtx.NotificationQueue = make(chan *types.Notification, 10)
+
+	record := deadletter.NewRecord(obj.Name, "dep1", "slow-trigger", newTriggerWorkersEvent(), errors.New("trigger failed"), 2)
+	assert.Nil(t, sensorCtx.DeadLetter.Send(record))
+
+	server := httptest.NewServer(sensorCtx.replayHandler("secret"))
+	defer server.Close()
+
+	// the requests must be authenticated
+	resp, err := http.Post(server.URL, "application/json", nil)
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, err = replay.NewClient(server.URL, "wrong").Submit(&replay.Request{RecordID: record.ID})
+	assert.NotNil(t, err)
+
+	client := replay.NewClient(server.URL, "secret")
+
+	// replay the dead-letter record to its dependency
+	response, err := client.Submit(&replay.Request{RecordID: record.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, "dep1", response.Dependency)
+	notification := <-sensorCtx.NotificationQueue
+	assert.Equal(t, "dep1", notification.EventDependency.Name)
+	assert.Equal(t, 2, notification.Attempts)
+	assert.Equal(t, v1alpha1.EventNotification, notification.NotificationType)
+
+	// a dry run applies the filters without processing the event
+	response, err = client.Submit(&replay.Request{Dependency: "dep2", Event: newTriggerWorkersEvent(), DryRun: true})
+	assert.Nil(t, err)
+	assert.True(t, response.DryRun)
+	assert.False(t, response.Passed)
+	assert.Equal(t, 0, len(sensorCtx.NotificationQueue))
+
+	response, err = client.Submit(&replay.Request{Dependency: "dep1", Event: newTriggerWorkersEvent(), DryRun: true})
+	assert.Nil(t, err)
+	assert.True(t, response.Passed)
+	assert.Equal(t, 0, len(sensorCtx.NotificationQueue))
+
+	// unknown records and dependencies are rejected
+	_, err = client.Submit(&replay.Request{RecordID: "unknown"})
+	assert.NotNil(t, err)
+	_, err = client.Submit(&replay.Request{Dependency: "unknown", Event: newTriggerWorkersEvent()})
+	assert.NotNil(t, err)
+	_, err = client.Submit(&replay.Request{Dependency: "dep1"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(sensorCtx.NotificationQueue))
+}
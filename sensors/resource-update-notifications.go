@@ -26,7 +26,12 @@ import (
 func (sensorCtx *SensorContext) operateResourceUpdateNotification(notification *types.Notification) {
 	sensorCtx.Logger.Info("Sensor resource update")
 	// update Sensor resource
+	events := dependencyNodeEvents(sensorCtx.Sensor)
 	sensorCtx.Sensor = notification.Sensor.DeepCopy()
+	if sensorCtx.StateStore != nil {
+		// the status of the resource only keeps the summaries of the events
+		attachEvents(sensorCtx.Sensor, events)
+	}
 
 	// initialize new dependencies
 	for _, dependency := range sensorCtx.Sensor.Spec.Dependencies {
@@ -0,0 +1,209 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	snctrl "github.com/argoproj/argo-events/controllers/sensor"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/sensors"
+	"github.com/argoproj/argo-events/sensors/deadletter"
+	"github.com/argoproj/argo-events/sensors/dependencies"
+	"github.com/argoproj/argo-events/sensors/triggers"
+	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Result is the outcome of a simulation, one entry per event in the order the events were fed
+type Result struct {
+	Events []*EventResult `json:"events"`
+}
+
+// EventResult is the outcome of an event
+type EventResult struct {
+	// EventID is the ID of the event
+	EventID string `json:"eventId,omitempty"`
+	// Source of the event
+	Source string `json:"source"`
+	// Dependency the event resolved to. Empty if no dependency of the sensor matches the event.
+	Dependency string `json:"dependency,omitempty"`
+	// Message describes what happened to the event
+	Message string `json:"message"`
+	// Triggers is the trigger cycle the event started, if the event resolved the dependencies
+	Triggers []*TriggerResult `json:"triggers,omitempty"`
+}
+
+// TriggerResult is the outcome of a trigger of a cycle
+type TriggerResult struct {
+	// Name of the trigger
+	Name string `json:"name"`
+	// Skipped tells whether the switches of the trigger were not resolved
+	Skipped bool `json:"skipped,omitempty"`
+	// Error of the trigger, if any
+	Error string `json:"error,omitempty"`
+	// Rendered is what the trigger would create or send
+	Rendered *triggers.Rendered `json:"rendered,omitempty"`
+}
+
+// Failed tells whether a trigger of the simulation failed
+func (result *Result) Failed() bool {
+	for _, event := range result.Events {
+		for _, trigger := range event.Triggers {
+			if trigger.Error != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// simulator feeds the events to an offline sensor and collects what happened to each event
+type simulator struct {
+	sensorCtx *sensors.SensorContext
+	// records are the dead-letter records of the current event
+	records []*deadletter.Record
+	// rendered are the triggers rendered for the current event, keyed by name
+	rendered map[string]*triggers.Rendered
+}
+
+// Simulate validates the sensor and feeds the events to it offline, through the queue of the sensor: the events are resolved to their
+// dependency and filtered, the events of the dependencies are correlated, the circuit is resolved and each trigger of a cycle is rendered
+// after its parameters and switches are applied, the way the sensor would execute it.
+// Nothing is written to the cluster. The client is only used to read the trigger sources that live in the cluster, it can be nil otherwise.
+func Simulate(kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor, events []*apicommon.Event, logger *logrus.Logger) (*Result, error) {
+	if err := snctrl.ValidateSensor(sensor); err != nil {
+		return nil, errors.Wrap(err, "sensor is invalid")
+	}
+
+	sensor = sensor.DeepCopy()
+	sensor.Status = v1alpha1.SensorStatus{}
+	initializeNodes(sensor, logger)
+
+	sim := &simulator{}
+	sim.sensorCtx = sensors.NewOfflineSensorContext(kubeClient, sensor, logger, sim, sim.render)
+
+	result := &Result{}
+	for _, event := range events {
+		result.Events = append(result.Events, sim.simulateEvent(event))
+	}
+	return result, nil
+}
+
+// Send records the event the sensor rejected or whose trigger failed
+func (sim *simulator) Send(record *deadletter.Record) error {
+	sim.records = append(sim.records, record)
+	return nil
+}
+
+// render records what the trigger would create or send
+func (sim *simulator) render(trigger *v1alpha1.Trigger, rendered *triggers.Rendered) {
+	sim.rendered[trigger.Template.Name] = rendered
+}
+
+// failure returns the dead-letter record of the trigger, nil if the trigger didn't fail. The record of an event rejected by the filters
+// has no trigger.
+func (sim *simulator) failure(triggerName string) *deadletter.Record {
+	for _, record := range sim.records {
+		if record.Trigger == triggerName {
+			return record
+		}
+	}
+	return nil
+}
+
+// simulateEvent feeds the event to the sensor the way the listener of the sensor does and describes what happened to it
+func (sim *simulator) simulateEvent(event *apicommon.Event) *EventResult {
+	sensor := sim.sensorCtx.Sensor
+	result := &EventResult{
+		EventID: event.Context.ID,
+		Source:  event.Context.Source,
+	}
+
+	dependency := dependencies.ResolveDependency(sensor.Spec.Dependencies, event)
+	if dependency == nil {
+		result.Message = "no dependency of the sensor matches the event"
+		return result
+	}
+	result.Dependency = dependency.Name
+
+	sim.records = nil
+	sim.rendered = make(map[string]*triggers.Rendered)
+	cycles := sensor.Status.TriggerCycleCount
+	sim.sensorCtx.ProcessNotification(&types.Notification{
+		Event:            event,
+		EventDependency:  dependency,
+		NotificationType: v1alpha1.EventNotification,
+	})
+
+	if sensor.Status.TriggerCycleCount == cycles {
+		// the node of the dependency is only left active if the sensor ignored the event
+		if node := snctrl.GetNodeByName(sensor, dependency.Name); node != nil && node.Phase == v1alpha1.NodePhaseComplete {
+			result.Message = "dependencies are not resolved yet"
+		} else {
+			result.Message = "last trigger cycle was a failure and sensor policy is set to ErrorOnFailedRound, the event is ignored"
+		}
+		return result
+	}
+	if record := sim.failure(""); record != nil {
+		result.Message = "event is rejected by the filters: " + record.Error
+		return result
+	}
+	if len(sim.records) == 0 && len(sim.rendered) == 0 && sensor.Status.TriggerCycleStatus == v1alpha1.TriggerCycleFailure {
+		result.Message = "trigger cycle failed before the triggers were executed"
+		return result
+	}
+
+	result.Message = "dependencies are resolved, the triggers are executed"
+	for _, trigger := range sensor.Spec.Triggers {
+		triggerResult := &TriggerResult{
+			Name: trigger.Template.Name,
+		}
+		result.Triggers = append(result.Triggers, triggerResult)
+		if rendered, ok := sim.rendered[trigger.Template.Name]; ok {
+			triggerResult.Rendered = rendered
+			continue
+		}
+		if record := sim.failure(trigger.Template.Name); record != nil {
+			triggerResult.Error = record.Error
+			// the cycle stops at the first failure
+			break
+		}
+		triggerResult.Skipped = true
+	}
+	return result
+}
+
+// initializeNodes initializes the nodes of the sensor and activates the dependencies the way the sensor controller does
+func initializeNodes(sensor *v1alpha1.Sensor, logger *logrus.Logger) {
+	for _, dependency := range sensor.Spec.Dependencies {
+		snctrl.InitializeNode(sensor, dependency.Name, v1alpha1.NodeTypeEventDependency, logger)
+	}
+	for _, group := range sensor.Spec.DependencyGroups {
+		snctrl.InitializeNode(sensor, group.Name, v1alpha1.NodeTypeDependencyGroup, logger)
+	}
+	for _, trigger := range sensor.Spec.Triggers {
+		snctrl.InitializeNode(sensor, trigger.Template.Name, v1alpha1.NodeTypeTrigger, logger)
+	}
+	for _, dependency := range sensor.Spec.Dependencies {
+		snctrl.MarkNodePhase(sensor, dependency.Name, v1alpha1.NodeTypeEventDependency, v1alpha1.NodePhaseActive, nil, logger, "dependency is re-activated")
+	}
+	for _, group := range sensor.Spec.DependencyGroups {
+		snctrl.MarkNodePhase(sensor, group.Name, v1alpha1.NodeTypeDependencyGroup, v1alpha1.NodePhaseActive, nil, logger, "dependency group is re-activated")
+	}
+}
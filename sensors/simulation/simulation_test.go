@@ -0,0 +1,206 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/ghodss/yaml"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var sensorSpec = `
+apiVersion: argoproj.io/v1alpha1
+kind: Sensor
+metadata:
+  name: simulation-sensor
+  namespace: argo-events
+spec:
+  template:
+    spec:
+      containers:
+        - name: sensor
+          image: argoproj/sensor
+  eventProtocol:
+    type: HTTP
+    http:
+      port: "9300"
+  dependencies:
+    - name: "webhook-gateway:example-1"
+      gatewayName: webhook-gateway
+      eventName: example-1
+    - name: "webhook-gateway:example-2"
+      gatewayName: webhook-gateway
+      eventName: example-2
+      filters:
+        data:
+          - path: type
+            type: string
+            value:
+              - deploy
+    - name: "webhook-gateway:example-3"
+      gatewayName: webhook-gateway
+      eventName: example-3
+  dependencyGroups:
+    - name: group1
+      dependencies:
+        - "webhook-gateway:example-1"
+        - "webhook-gateway:example-2"
+    - name: group2
+      dependencies:
+        - "webhook-gateway:example-3"
+  circuit: group1 || group2
+  triggers:
+    - template:
+        name: config-map-trigger
+        version: v1
+        resource: configmaps
+        source:
+          inline: |
+            apiVersion: v1
+            kind: ConfigMap
+            metadata:
+              name: placeholder
+            data:
+              type: placeholder
+      resourceParameters:
+        - src:
+            event: "webhook-gateway:example-1"
+            dataKey: name
+          dest: metadata.name
+        - src:
+            event: "webhook-gateway:example-2"
+            dataKey: type
+          dest: data.type
+    - template:
+        name: http-trigger
+        http:
+          url: http://example.com/deploy
+          payload:
+            - src:
+                event: "webhook-gateway:example-1"
+                dataKey: name
+              dest: name
+    - template:
+        name: patch-trigger
+        version: v1
+        resource: configmaps
+        operation: merge-patch
+        patch: |
+          data:
+            type: placeholder
+        source:
+          inline: |
+            apiVersion: v1
+            kind: ConfigMap
+            metadata:
+              name: app
+      patchParameters:
+        - src:
+            event: "webhook-gateway:example-2"
+            dataKey: type
+          dest: data.type
+    - template:
+        name: skipped-trigger
+        switch:
+          all:
+            - group2
+        http:
+          url: http://example.com/never
+`
+
+func newEvent(subject, data string) *apicommon.Event {
+	return &apicommon.Event{
+		Context: apicommon.EventContext{
+			ID:              subject,
+			Source:          "webhook-gateway",
+			Subject:         subject,
+			DataContentType: "application/json",
+			Time:            metav1.MicroTime{Time: time.Now()},
+		},
+		Data: []byte(data),
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	sensor := &v1alpha1.Sensor{}
+	assert.Nil(t, yaml.Unmarshal([]byte(sensorSpec), sensor))
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	result, err := Simulate(nil, sensor, []*apicommon.Event{
+		newEvent("example-1", `{"name": "app"}`),
+		newEvent("unknown", `{}`),
+		newEvent("example-2", `{"type": "test"}`),
+		newEvent("example-1", `{"name": "app"}`),
+		newEvent("example-2", `{"type": "deploy"}`),
+	}, logger)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(result.Events))
+	assert.False(t, result.Failed())
+
+	assert.Equal(t, "webhook-gateway:example-1", result.Events[0].Dependency)
+	assert.Equal(t, 0, len(result.Events[0].Triggers))
+	assert.Equal(t, "dependencies are not resolved yet", result.Events[0].Message)
+	assert.Equal(t, "", result.Events[1].Dependency)
+	// the rejected event fails the cycle and re-activates the dependencies
+	assert.Equal(t, "webhook-gateway:example-2", result.Events[2].Dependency)
+	assert.Equal(t, 0, len(result.Events[2].Triggers))
+	assert.Contains(t, result.Events[2].Message, "event is rejected by the filters")
+	assert.Equal(t, 0, len(result.Events[3].Triggers))
+
+	triggers := result.Events[4].Triggers
+	assert.Equal(t, 4, len(triggers))
+
+	resource := triggers[0].Rendered
+	assert.NotNil(t, resource)
+	assert.Equal(t, v1alpha1.Create, resource.Operation)
+	assert.Equal(t, "app", resource.Resource.GetName())
+	assert.Equal(t, "argo-events", resource.Resource.GetNamespace())
+	data, _, _ := unstructured.NestedString(resource.Resource.Object, "data", "type")
+	assert.Equal(t, "deploy", data)
+
+	assert.Equal(t, "http://example.com/deploy", triggers[1].Rendered.Destination)
+	payload := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(triggers[1].Rendered.Payload, &payload))
+	assert.Equal(t, "app", payload["name"])
+
+	patch := triggers[2].Rendered
+	assert.NotNil(t, patch)
+	assert.Equal(t, v1alpha1.MergePatch, patch.Operation)
+	assert.JSONEq(t, `{"data": {"type": "deploy"}}`, string(patch.Patch))
+
+	assert.True(t, triggers[3].Skipped)
+	assert.Nil(t, triggers[3].Rendered)
+
+	// the simulation doesn't change the sensor
+	assert.Nil(t, sensor.Status.Nodes)
+
+	// an invalid sensor is not simulated
+	sensor.Spec.Triggers = nil
+	_, err = Simulate(nil, sensor, nil, logger)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	snctrl "github.com/argoproj/argo-events/controllers/sensor"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/sensors/state"
+	"github.com/argoproj/argo-events/store"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newStateStore returns the store of the state of the sensor, nil if the sensor keeps the events in its status
+func (sensorCtx *SensorContext) newStateStore() (state.Store, error) {
+	sensor := sensorCtx.Sensor
+	stateStore := sensor.Spec.StateStore
+	if stateStore == nil {
+		return nil, nil
+	}
+
+	switch {
+	case stateStore.ConfigMap != nil:
+		name := stateStore.ConfigMap.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-state", sensor.Name)
+		}
+		return state.NewConfigMapStore(sensorCtx.KubeClient, sensor.Namespace, name, metav1.NewControllerRef(sensor, v1alpha1.SchemaGroupVersionKind)), nil
+
+	case stateStore.BoltDB != nil:
+		return state.NewBoltDBStore(stateStore.BoltDB.Path, sensor.Name)
+
+	case stateStore.Redis != nil:
+		redis := stateStore.Redis
+		var password string
+		if redis.Password != nil {
+			var err error
+			if password, err = store.GetSecrets(sensorCtx.KubeClient, sensor.Namespace, redis.Password.Name, redis.Password.Key); err != nil {
+				return nil, errors.Wrap(err, "failed to retrieve the redis password")
+			}
+		}
+		key := redis.Key
+		if key == "" {
+			key = fmt.Sprintf("argo-events:%s:%s", sensor.Namespace, sensor.Name)
+		}
+		return state.NewRedisStore(redis.Addr, password, int(redis.DB), key)
+
+	default:
+		return nil, fmt.Errorf("state store is not specified")
+	}
+}
+
+// initStateStore connects to the state store of the sensor, if any, and restores the partially resolved dependencies
+// and the incomplete event sets
+func (sensorCtx *SensorContext) initStateStore() error {
+	if sensorCtx.StateStore == nil {
+		stateStore, err := sensorCtx.newStateStore()
+		if err != nil {
+			return errors.Wrap(err, "failed to initialize the state store")
+		}
+		if stateStore == nil {
+			return nil
+		}
+		sensorCtx.StateStore = stateStore
+	}
+
+	if err := sensorCtx.restoreState(); err != nil {
+		return errors.Wrap(err, "failed to restore the state of the dependencies")
+	}
+
+	if correlation := sensorCtx.Sensor.Spec.Correlation; correlation != nil && correlation.Sets != nil {
+		sets, err := state.NewEventSetStore(sensorCtx.StateStore, int(correlation.Sets.MaxSets))
+		if err != nil {
+			return errors.Wrap(err, "failed to restore the event sets")
+		}
+		sensorCtx.EventSets = sets
+		eventSetsInFlight.WithLabelValues(sensorCtx.Sensor.Name).Set(float64(sets.Len()))
+	}
+	return nil
+}
+
+// restoreState restores the events of the complete dependency nodes from the state store.
+// The dependencies whose event is not found are re-activated.
+func (sensorCtx *SensorContext) restoreState() error {
+	for _, node := range sensorCtx.Sensor.Status.Nodes {
+		if node.Type != v1alpha1.NodeTypeEventDependency || node.Phase != v1alpha1.NodePhaseComplete {
+			continue
+		}
+		value, err := sensorCtx.StateStore.Get(state.DependencyKey(node.ID))
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			sensorCtx.Logger.WithField("node", node.Name).Warnln("event of the dependency is not found in the state store, re-activating the dependency")
+			node.Phase = v1alpha1.NodePhaseActive
+			node.Event = nil
+			node.Message = "event is not found in the state store"
+			sensorCtx.Sensor.Status.Nodes[node.ID] = node
+			continue
+		}
+		event := &apicommon.Event{}
+		if err := json.Unmarshal(value, event); err != nil {
+			return errors.Wrapf(err, "failed to decode the event of the dependency %s", node.Name)
+		}
+		node.Event = event
+		sensorCtx.Sensor.Status.Nodes[node.ID] = node
+	}
+	return nil
+}
+
+// saveState writes the events of the complete dependency nodes to the state store and deletes the others, at once
+// if the store supports it
+func (sensorCtx *SensorContext) saveState() error {
+	values := make(map[string][]byte)
+	for _, node := range sensorCtx.Sensor.Status.Nodes {
+		if node.Type != v1alpha1.NodeTypeEventDependency {
+			continue
+		}
+		key := state.DependencyKey(node.ID)
+		if node.Phase != v1alpha1.NodePhaseComplete || node.Event == nil {
+			values[key] = nil
+			continue
+		}
+		value, err := json.Marshal(node.Event)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode the event of the dependency %s", node.Name)
+		}
+		values[key] = value
+	}
+	return state.Write(sensorCtx.StateStore, values)
+}
+
+// persistUpdates persists the updates to the sensor resource. If the sensor has a state store, the events of the
+// dependencies are written to the store and the status only keeps their contexts. The updates of an offline sensor are kept in memory.
+func (sensorCtx *SensorContext) persistUpdates() {
+	if sensorCtx.render != nil {
+		return
+	}
+	if sensorCtx.StateStore == nil {
+		updatedSensor, err := snctrl.PersistUpdates(sensorCtx.SensorClient, sensorCtx.Sensor, sensorCtx.Logger)
+		if err != nil {
+			sensorCtx.Logger.WithError(err).Error("failed to persist sensor update")
+		}
+		// update Sensor ref. in case of failure to persist updates, this is a deep copy of old Sensor resource
+		sensorCtx.Sensor = updatedSensor
+		return
+	}
+
+	if err := sensorCtx.saveState(); err != nil {
+		sensorCtx.Logger.WithError(err).Error("failed to save the state of the dependencies")
+	}
+	events := dependencyNodeEvents(sensorCtx.Sensor)
+	updatedSensor, err := snctrl.PersistUpdates(sensorCtx.SensorClient, summarizeEvents(sensorCtx.Sensor), sensorCtx.Logger)
+	if err != nil {
+		sensorCtx.Logger.WithError(err).Error("failed to persist sensor update")
+	}
+	attachEvents(updatedSensor, events)
+	sensorCtx.Sensor = updatedSensor
+}
+
+// summarizeEvents returns a copy of the sensor whose dependency nodes only keep the contexts of the events
+func summarizeEvents(sensor *v1alpha1.Sensor) *v1alpha1.Sensor {
+	summary := sensor.DeepCopy()
+	for id, node := range summary.Status.Nodes {
+		if node.Type != v1alpha1.NodeTypeEventDependency || node.Event == nil {
+			continue
+		}
+		node.Event = &apicommon.Event{
+			Context: node.Event.Context,
+		}
+		summary.Status.Nodes[id] = node
+	}
+	return summary
+}
+
+// dependencyNodeEvents returns the events of the dependency nodes keyed by the node ID
+func dependencyNodeEvents(sensor *v1alpha1.Sensor) map[string]*apicommon.Event {
+	events := make(map[string]*apicommon.Event)
+	for id, node := range sensor.Status.Nodes {
+		if node.Type == v1alpha1.NodeTypeEventDependency && node.Event != nil {
+			events[id] = node.Event
+		}
+	}
+	return events
+}
+
+// attachEvents replaces the event summaries of the dependency nodes with the events
+func attachEvents(sensor *v1alpha1.Sensor, events map[string]*apicommon.Event) {
+	for id, node := range sensor.Status.Nodes {
+		event, ok := events[id]
+		if !ok || node.Type != v1alpha1.NodeTypeEventDependency || node.Event == nil {
+			continue
+		}
+		node.Event = event
+		sensor.Status.Nodes[id] = node
+	}
+}
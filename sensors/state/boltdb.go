@@ -0,0 +1,114 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltDBStore stores the state in a bucket of an embedded BoltDB database
+type BoltDBStore struct {
+	db     *bolt.DB
+	bucket []byte
+}
+
+// NewBoltDBStore opens the database file at the path and creates the bucket if it doesn't exist
+func NewBoltDBStore(path, bucket string) (*BoltDBStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 10 * time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the database %s", path)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrapf(err, "failed to create the bucket %s", bucket)
+	}
+	return &BoltDBStore{
+		db:     db,
+		bucket: []byte(bucket),
+	}, nil
+}
+
+// Get returns the value of the key
+func (store *BoltDBStore) Get(key string) ([]byte, error) {
+	var value []byte
+	err := store.db.View(func(tx *bolt.Tx) error {
+		// the value is only valid during the transaction
+		if v := tx.Bucket(store.bucket).Get([]byte(key)); v != nil {
+			value = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return value, err
+}
+
+// Put stores the value of the key
+func (store *BoltDBStore) Put(key string, value []byte) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(store.bucket).Put([]byte(key), value)
+	})
+}
+
+// Delete deletes the key
+func (store *BoltDBStore) Delete(key string) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(store.bucket).Delete([]byte(key))
+	})
+}
+
+// Write stores the values of the keys and deletes the keys whose value is nil, in a single transaction
+func (store *BoltDBStore) Write(values map[string][]byte) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(store.bucket)
+		for key, value := range values {
+			if value == nil {
+				if err := bucket.Delete([]byte(key)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := bucket.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// List returns the values of the keys with the prefix
+func (store *BoltDBStore) List(prefix string) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	err := store.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(store.bucket).Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = c.Next() {
+			values[string(k)] = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return values, err
+}
+
+// Close closes the database
+func (store *BoltDBStore) Close() error {
+	return store.db.Close()
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/argoproj/argo-events/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// ConfigMapStore stores the state in the binary data of a config map.
+// Each write rewrites the whole config map, and the state is limited to the 1MiB a config map can hold.
+// The boltdb and redis stores suit large or frequent events.
+type ConfigMapStore struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	owner     *metav1.OwnerReference
+	lock      sync.Mutex
+}
+
+// NewConfigMapStore returns a store backed by the config map. The config map is created on the first write
+// and is owned by the owner, if any.
+func NewConfigMapStore(client kubernetes.Interface, namespace, name string, owner *metav1.OwnerReference) *ConfigMapStore {
+	return &ConfigMapStore{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		owner:     owner,
+	}
+}
+
+// Get returns the value of the key
+func (store *ConfigMapStore) Get(key string) ([]byte, error) {
+	cm, err := store.client.CoreV1().ConfigMaps(store.namespace).Get(store.name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cm.BinaryData[key], nil
+}
+
+// Put stores the value of the key
+func (store *ConfigMapStore) Put(key string, value []byte) error {
+	return store.Write(map[string][]byte{key: value})
+}
+
+// Delete deletes the key
+func (store *ConfigMapStore) Delete(key string) error {
+	return store.Write(map[string][]byte{key: nil})
+}
+
+// Write stores the values of the keys and deletes the keys whose value is nil, in a single update of the config map
+func (store *ConfigMapStore) Write(values map[string][]byte) error {
+	return store.update(func(cm *corev1.ConfigMap) bool {
+		changed := false
+		for key, value := range values {
+			current, ok := cm.BinaryData[key]
+			if value == nil {
+				if ok {
+					delete(cm.BinaryData, key)
+					changed = true
+				}
+				continue
+			}
+			if ok && bytes.Equal(current, value) {
+				continue
+			}
+			if cm.BinaryData == nil {
+				cm.BinaryData = make(map[string][]byte)
+			}
+			cm.BinaryData[key] = value
+			changed = true
+		}
+		return changed
+	})
+}
+
+// List returns the values of the keys with the prefix
+func (store *ConfigMapStore) List(prefix string) (map[string][]byte, error) {
+	cm, err := store.client.CoreV1().ConfigMaps(store.namespace).Get(store.name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return map[string][]byte{}, nil
+		}
+		return nil, err
+	}
+	values := make(map[string][]byte)
+	for key, value := range cm.BinaryData {
+		if strings.HasPrefix(key, prefix) {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// Close is a no-op for the config map store
+func (store *ConfigMapStore) Close() error {
+	return nil
+}
+
+// update applies the mutation to the config map, creating the config map if it doesn't exist.
+// The config map is only updated if the mutation changed it, and if its data still fits in a config map.
+func (store *ConfigMapStore) update(mutate func(cm *corev1.ConfigMap) bool) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	configMaps := store.client.CoreV1().ConfigMaps(store.namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(store.name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			cm = store.newConfigMap()
+			if !mutate(cm) {
+				return nil
+			}
+			if err := checkSize(cm); err != nil {
+				return err
+			}
+			_, err = configMaps.Create(cm)
+			return err
+		}
+		if !mutate(cm) {
+			return nil
+		}
+		if err := checkSize(cm); err != nil {
+			return err
+		}
+		_, err = configMaps.Update(cm)
+		return err
+	})
+}
+
+// checkSize returns an error if the binary data exceeds the size limit of a config map
+func checkSize(cm *corev1.ConfigMap) error {
+	size := 0
+	for key, value := range cm.BinaryData {
+		size += len(key) + len(value)
+	}
+	if size > common.MaxConfigMapSize {
+		return fmt.Errorf("state of %d bytes exceeds the size limit of the config map %s, use a boltDB or redis state store instead", size, cm.Name)
+	}
+	return nil
+}
+
+// newConfigMap returns the config map that holds the state
+func (store *ConfigMapStore) newConfigMap() *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      store.name,
+			Namespace: store.namespace,
+		},
+		BinaryData: make(map[string][]byte),
+	}
+	if store.owner != nil {
+		cm.OwnerReferences = []metav1.OwnerReference{*store.owner}
+		cm.Labels = map[string]string{
+			common.LabelOwnerName: store.owner.Name,
+		}
+	}
+	return cm
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/argoproj/argo-events/sensors/dependencies"
+	"github.com/pkg/errors"
+)
+
+// EventSetStore holds the incomplete event sets in memory and writes them through to a store,
+// so the event sets survive the restarts of the sensor.
+type EventSetStore struct {
+	*dependencies.MemoryEventSetStore
+	store Store
+}
+
+// NewEventSetStore returns an event set store that writes through to the store. The event sets already in the store are loaded.
+func NewEventSetStore(store Store, maxSets int) (*EventSetStore, error) {
+	sets := &EventSetStore{
+		MemoryEventSetStore: dependencies.NewMemoryEventSetStore(maxSets),
+		store:               store,
+	}
+	values, err := store.List(EventSetPrefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the event sets")
+	}
+	for key, value := range values {
+		set := &dependencies.EventSet{}
+		if err := json.Unmarshal(value, set); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the event set %s", key)
+		}
+		evicted, err := sets.MemoryEventSetStore.Put(set)
+		if err != nil {
+			return nil, err
+		}
+		if err := sets.delete(evicted); err != nil {
+			return nil, err
+		}
+	}
+	return sets, nil
+}
+
+// Put stores the event set and deletes the evicted sets from the store
+func (sets *EventSetStore) Put(set *dependencies.EventSet) ([]*dependencies.EventSet, error) {
+	value, err := json.Marshal(set)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to encode the event set %s", set.Key)
+	}
+	if err := sets.store.Put(EventSetKey(set.Key), value); err != nil {
+		return nil, err
+	}
+	evicted, err := sets.MemoryEventSetStore.Put(set)
+	if err != nil {
+		return nil, err
+	}
+	return evicted, sets.delete(evicted)
+}
+
+// Remove removes the event set of the key
+func (sets *EventSetStore) Remove(key string) error {
+	if err := sets.store.Delete(EventSetKey(key)); err != nil {
+		return err
+	}
+	return sets.MemoryEventSetStore.Remove(key)
+}
+
+// Evict removes and returns the event sets created before the time
+func (sets *EventSetStore) Evict(before time.Time) ([]*dependencies.EventSet, error) {
+	evicted, err := sets.MemoryEventSetStore.Evict(before)
+	if err != nil {
+		return nil, err
+	}
+	return evicted, sets.delete(evicted)
+}
+
+// delete deletes the event sets from the store
+func (sets *EventSetStore) delete(evicted []*dependencies.EventSet) error {
+	for _, set := range evicted {
+		if err := sets.store.Delete(EventSetKey(set.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
+)
+
+// RedisStore stores the state in a hash of a Redis compatible server
+type RedisStore struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisStore connects to the server and returns a store backed by the hash of the key
+func NewRedisStore(addr, password string, db int, key string) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	if err := client.Ping().Err(); err != nil {
+		_ = client.Close()
+		return nil, errors.Wrapf(err, "failed to connect to redis at %s", addr)
+	}
+	return &RedisStore{
+		client: client,
+		key:    key,
+	}, nil
+}
+
+// Get returns the value of the key
+func (store *RedisStore) Get(key string) ([]byte, error) {
+	value, err := store.client.HGet(store.key, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Put stores the value of the key
+func (store *RedisStore) Put(key string, value []byte) error {
+	return store.client.HSet(store.key, key, value).Err()
+}
+
+// Delete deletes the key
+func (store *RedisStore) Delete(key string) error {
+	return store.client.HDel(store.key, key).Err()
+}
+
+// List returns the values of the keys with the prefix
+func (store *RedisStore) List(prefix string) (map[string][]byte, error) {
+	entries, err := store.client.HGetAll(store.key).Result()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string][]byte)
+	for key, value := range entries {
+		if strings.HasPrefix(key, prefix) {
+			values[key] = []byte(value)
+		}
+	}
+	return values, nil
+}
+
+// Close closes the connection to the server
+func (store *RedisStore) Close() error {
+	return store.client.Close()
+}
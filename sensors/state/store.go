@@ -0,0 +1,81 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// DependencyPrefix is the prefix of the keys of the events of the dependencies
+	DependencyPrefix = "dependency."
+	// EventSetPrefix is the prefix of the keys of the incomplete sets of correlated events
+	EventSetPrefix = "eventset."
+)
+
+// Store persists the state of a sensor as key-value pairs
+type Store interface {
+	// Get returns the value of the key, nil if the key is not found
+	Get(key string) ([]byte, error)
+	// Put stores the value of the key
+	Put(key string, value []byte) error
+	// Delete deletes the key. Deleting a key that is not found is not an error.
+	Delete(key string) error
+	// List returns the values of the keys with the prefix
+	List(prefix string) (map[string][]byte, error)
+	// Close releases the resources held by the store
+	Close() error
+}
+
+// BatchStore is a store that applies several writes at once
+type BatchStore interface {
+	// Write stores the values of the keys and deletes the keys whose value is nil
+	Write(values map[string][]byte) error
+}
+
+// Write stores the values of the keys and deletes the keys whose value is nil.
+// The writes are applied at once if the store is a batch store, one by one otherwise.
+func Write(store Store, values map[string][]byte) error {
+	if batch, ok := store.(BatchStore); ok {
+		return batch.Write(values)
+	}
+	for key, value := range values {
+		if value == nil {
+			if err := store.Delete(key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := store.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DependencyKey returns the key of the event of a dependency node
+func DependencyKey(nodeID string) string {
+	return DependencyPrefix + nodeID
+}
+
+// EventSetKey returns the key of an event set. The correlation key is hashed as it is extracted from the event data
+// and may contain characters that are not allowed in the keys of the stores.
+func EventSetKey(correlationKey string) string {
+	hash := sha256.Sum256([]byte(correlationKey))
+	return EventSetPrefix + hex.EncodeToString(hash[:])
+}
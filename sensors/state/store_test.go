@@ -0,0 +1,282 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/sensors/dependencies"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testStore runs the common store operations against the store
+func testStore(t *testing.T, store Store) {
+	value, err := store.Get(DependencyKey("sensor-1"))
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+	assert.Nil(t, store.Delete(DependencyKey("sensor-1")))
+
+	assert.Nil(t, store.Put(DependencyKey("sensor-1"), []byte("event-1")))
+	assert.Nil(t, store.Put(DependencyKey("sensor-2"), []byte("event-2")))
+	assert.Nil(t, store.Put(EventSetKey("head_commit"), []byte("set")))
+
+	value, err = store.Get(DependencyKey("sensor-1"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("event-1"), value)
+
+	values, err := store.List(DependencyPrefix)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]byte{
+		DependencyKey("sensor-1"): []byte("event-1"),
+		DependencyKey("sensor-2"): []byte("event-2"),
+	}, values)
+
+	assert.Nil(t, store.Delete(DependencyKey("sensor-1")))
+	value, err = store.Get(DependencyKey("sensor-1"))
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	values, err = store.List(EventSetPrefix)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(values))
+
+	// a nil value deletes the key
+	assert.Nil(t, Write(store, map[string][]byte{
+		DependencyKey("sensor-1"): []byte("event-3"),
+		DependencyKey("sensor-2"): nil,
+	}))
+	values, err = store.List(DependencyPrefix)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]byte{
+		DependencyKey("sensor-1"): []byte("event-3"),
+	}, values)
+
+	assert.Nil(t, store.Close())
+}
+
+func TestConfigMapStore(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	testStore(t, NewConfigMapStore(client, "argo-events", "sensor-state", nil))
+}
+
+func TestConfigMapStoreWrite(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapStore(client, "argo-events", "sensor-state", nil)
+	assert.Nil(t, store.Put(DependencyKey("sensor-1"), []byte("event-1")))
+
+	// the values are written with a single update, and unchanged values don't update the config map
+	client.ClearActions()
+	values := map[string][]byte{
+		DependencyKey("sensor-1"): []byte("event-1"),
+		DependencyKey("sensor-2"): []byte("event-2"),
+		DependencyKey("sensor-3"): nil,
+	}
+	assert.Nil(t, store.Write(values))
+	assert.Nil(t, store.Write(values))
+	updates := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" {
+			updates++
+		}
+	}
+	assert.Equal(t, 1, updates)
+
+	// the state must fit in the config map
+	err := store.Put(DependencyKey("sensor-3"), make([]byte, common.MaxConfigMapSize))
+	assert.NotNil(t, err)
+	value, err := store.Get(DependencyKey("sensor-3"))
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+}
+
+func TestBoltDBStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewBoltDBStore(filepath.Join(dir, "state.db"), "sensor")
+	assert.Nil(t, err)
+	testStore(t, store)
+}
+
+// fakeRedis serves the hash commands used by the redis store from memory
+type fakeRedis struct {
+	listener net.Listener
+	lock     sync.Mutex
+	hashes   map[string]map[string]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := &fakeRedis{
+		listener: listener,
+		hashes:   make(map[string]map[string]string),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (server *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, server.execute(args)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, arg); err != nil {
+			return nil, err
+		}
+		args[i] = string(arg[:size])
+	}
+	return args, nil
+}
+
+func (server *fakeRedis) execute(args []string) string {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	bulk := func(value string) string {
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	}
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "HGET":
+		value, ok := server.hashes[args[1]][args[2]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "HSET":
+		if _, ok := server.hashes[args[1]]; !ok {
+			server.hashes[args[1]] = make(map[string]string)
+		}
+		server.hashes[args[1]][args[2]] = args[3]
+		return ":1\r\n"
+	case "HDEL":
+		deleted := 0
+		for _, field := range args[2:] {
+			if _, ok := server.hashes[args[1]][field]; ok {
+				delete(server.hashes[args[1]], field)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	case "HGETALL":
+		reply := fmt.Sprintf("*%d\r\n", 2*len(server.hashes[args[1]]))
+		for field, value := range server.hashes[args[1]] {
+			reply += bulk(field) + bulk(value)
+		}
+		return reply
+	}
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+func TestRedisStore(t *testing.T) {
+	server := newFakeRedis(t)
+	defer server.listener.Close()
+
+	store, err := NewRedisStore(server.listener.Addr().String(), "", 0, "argo-events:argo-events:sensor")
+	assert.Nil(t, err)
+	testStore(t, store)
+
+	_, err = NewRedisStore("127.0.0.1:1", "", 0, "argo-events:argo-events:sensor")
+	assert.NotNil(t, err)
+}
+
+func TestEventSetStore(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapStore(client, "argo-events", "sensor-state", nil)
+	now := time.Now()
+
+	sets, err := NewEventSetStore(store, 2)
+	assert.Nil(t, err)
+	for i, key := range []string{"a", "b"} {
+		set := dependencies.NewEventSet(key, now.Add(time.Duration(i)*time.Second))
+		set.Events["dep1"] = &apicommon.Event{Data: []byte(key)}
+		_, err := sets.Put(set)
+		assert.Nil(t, err)
+	}
+
+	// the event sets are restored from the store
+	restored, err := NewEventSetStore(store, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, restored.Len())
+	set, err := restored.Get("a")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("a"), set.Events["dep1"].Data)
+
+	// the evicted sets are deleted from the store
+	evicted, err := restored.Put(dependencies.NewEventSet("c", now.Add(time.Minute)))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(evicted))
+	assert.Nil(t, restored.Remove("b"))
+	values, err := store.List(EventSetPrefix)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(values))
+	assert.Contains(t, values, EventSetKey("c"))
+}
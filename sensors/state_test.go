@@ -0,0 +1,110 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"testing"
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	sensorFake "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStateStore(t *testing.T) {
+	sensorClient := sensorFake.NewSimpleClientset()
+	dynamicClient := dfake.NewSimpleDynamicClient(runtime.NewScheme())
+	k8sClient := fake.NewSimpleClientset()
+	obj := sensorObj.DeepCopy()
+	obj.Spec.StateStore = &v1alpha1.StateStore{
+		ConfigMap: &v1alpha1.ConfigMapStateStore{},
+	}
+	obj.Spec.Dependencies = []v1alpha1.EventDependency{
+		{
+			Name:        "dep1",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-1",
+		},
+		{
+			Name:        "dep2",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-2",
+		},
+	}
+	dep1 := obj.NodeID("dep1")
+	dep2 := obj.NodeID("dep2")
+	event := &apicommon.Event{
+		Context: apicommon.EventContext{
+			DataContentType: "application/json",
+			Source:          "webhook-gateway",
+			ID:              "1",
+			Time:            metav1.MicroTime{Time: time.Now()},
+		},
+		Data: []byte(`{"name": "fake"}`),
+	}
+	obj.Status.Nodes = map[string]v1alpha1.NodeStatus{
+		dep1: {
+			ID:    dep1,
+			Name:  "dep1",
+			Type:  v1alpha1.NodeTypeEventDependency,
+			Phase: v1alpha1.NodePhaseActive,
+		},
+		dep2: {
+			ID:    dep2,
+			Name:  "dep2",
+			Type:  v1alpha1.NodeTypeEventDependency,
+			Phase: v1alpha1.NodePhaseActive,
+		},
+	}
+
+	newObj, err := sensorClient.ArgoprojV1alpha1().Sensors(obj.Namespace).Create(obj)
+	assert.Nil(t, err)
+	sensorCtx := NewSensorContext(sensorClient, k8sClient, dynamicClient, newObj.DeepCopy(), "1")
+	err = sensorCtx.initStateStore()
+	assert.Nil(t, err)
+	assert.NotNil(t, sensorCtx.StateStore)
+
+	// the first dependency is resolved
+	node := sensorCtx.Sensor.Status.Nodes[dep1]
+	node.Phase = v1alpha1.NodePhaseComplete
+	node.Event = event
+	sensorCtx.Sensor.Status.Nodes[dep1] = node
+	sensorCtx.persistUpdates()
+
+	// the status only keeps the context of the event
+	persisted, err := sensorClient.ArgoprojV1alpha1().Sensors(obj.Namespace).Get(obj.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1", persisted.Status.Nodes[dep1].Event.Context.ID)
+	assert.Nil(t, persisted.Status.Nodes[dep1].Event.Data)
+	assert.Equal(t, event.Data, sensorCtx.Sensor.Status.Nodes[dep1].Event.Data)
+
+	_, err = k8sClient.CoreV1().ConfigMaps(obj.Namespace).Get("fake-sensor-state", metav1.GetOptions{})
+	assert.Nil(t, err)
+
+	// the event is restored on restart
+	restarted := NewSensorContext(sensorClient, k8sClient, dynamicClient, persisted, "1")
+	err = restarted.initStateStore()
+	assert.Nil(t, err)
+	assert.Equal(t, v1alpha1.NodePhaseComplete, restarted.Sensor.Status.Nodes[dep1].Phase)
+	assert.Equal(t, event.Data, restarted.Sensor.Status.Nodes[dep1].Event.Data)
+	assert.Equal(t, v1alpha1.NodePhaseActive, restarted.Sensor.Status.Nodes[dep2].Phase)
+}
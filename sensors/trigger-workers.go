@@ -0,0 +1,138 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"context"
+	"time"
+
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/sensors/triggers"
+	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// triggerJob is a trigger of a cycle waiting for a worker
+type triggerJob struct {
+	ctx          context.Context
+	sensor       *v1alpha1.Sensor
+	notification *types.Notification
+	trigger      v1alpha1.Trigger
+	logger       *logrus.Entry
+	result       chan<- error
+}
+
+// startTriggerWorkers starts the workers that execute the triggers concurrently. No worker is started if workers is not positive,
+// and the triggers are executed sequentially.
+func (sensorCtx *SensorContext) startTriggerWorkers(workers int) {
+	if workers <= 0 {
+		return
+	}
+	sensorCtx.triggerJobs = make(chan *triggerJob, workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range sensorCtx.triggerJobs {
+				job.result <- sensorCtx.runTrigger(job.ctx, job.sensor, job.notification, job.trigger, job.logger)
+			}
+		}()
+	}
+}
+
+// executeTriggerCycle executes the triggers of the sensor and returns the first error, if any.
+// The triggers are executed one after the other and the cycle stops at the first failure, unless the sensor has trigger workers,
+// in which case every trigger is executed concurrently.
+// For each trigger,
+// 1. Apply template level parameters
+// 2. Check if switches are resolved
+// 3. Fetch the resource
+// 4. Apply resource level parameters
+// 5. If any policy is set, apply it
+func (sensorCtx *SensorContext) executeTriggerCycle(ctx context.Context, sensor *v1alpha1.Sensor, notification *types.Notification, logger *logrus.Entry) error {
+	if sensorCtx.triggerJobs == nil {
+		for _, trigger := range sensor.Spec.Triggers {
+			if err := sensorCtx.runTrigger(ctx, sensor, notification, trigger, logger); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	results := make(chan error, len(sensor.Spec.Triggers))
+	for _, trigger := range sensor.Spec.Triggers {
+		sensorCtx.triggerJobs <- &triggerJob{
+			ctx:          ctx,
+			sensor:       sensor,
+			notification: notification,
+			trigger:      trigger,
+			logger:       logger,
+			result:       results,
+		}
+	}
+	var cycleErr error
+	for range sensor.Spec.Triggers {
+		if err := <-results; err != nil && cycleErr == nil {
+			cycleErr = err
+		}
+	}
+	return cycleErr
+}
+
+// runTrigger applies the template parameters and the switches of the trigger and executes it within its timeout.
+// The event that resolved the dependencies is sent to the dead-letter sink if the trigger fails.
+func (sensorCtx *SensorContext) runTrigger(ctx context.Context, sensor *v1alpha1.Sensor, notification *types.Notification, trigger v1alpha1.Trigger, logger *logrus.Entry) error {
+	if err := triggers.ApplyTemplateParameters(sensor, &trigger); err != nil {
+		sensorCtx.deadLetter(sensor.Name, notification, trigger.Template.Name, err)
+		return err
+	}
+	if ok := triggers.ApplySwitches(sensor, &trigger); !ok {
+		logger.WithField("trigger-name", trigger.Template.Name).Infoln("switches/group level when conditions were not resolved, won't execute the trigger")
+		return nil
+	}
+
+	start := time.Now()
+	err := sensorCtx.executeTriggerWithTimeout(ctx, sensor, &trigger, logger)
+	recordTriggerExecution(sensor.Name, trigger.Template.Name, start, err)
+	if err != nil {
+		sensorCtx.deadLetter(sensor.Name, notification, trigger.Template.Name, err)
+	}
+	return err
+}
+
+// executeTriggerWithTimeout executes the trigger with a context that is cancelled once the timeout of the trigger is reached.
+// The HTTP, NATS and AMQP triggers stop on the context while K8s resource operations and Kafka publishes are bounded by their own timeouts,
+// so the trigger is only reported as timed out once its execution actually returned.
+func (sensorCtx *SensorContext) executeTriggerWithTimeout(ctx context.Context, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger, logger *logrus.Entry) error {
+	if trigger.Timeout == "" {
+		return sensorCtx.executeTrigger(ctx, sensor, trigger, logger)
+	}
+	timeout, err := time.ParseDuration(trigger.Timeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the timeout of the trigger %s", trigger.Template.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := sensorCtx.executeTrigger(ctx, sensor, trigger, logger); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Wrapf(err, "trigger %s timed out after %s", trigger.Template.Name, timeout)
+		}
+		return err
+	}
+	return nil
+}
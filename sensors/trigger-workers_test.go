@@ -0,0 +1,186 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sensors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	sensorFake "github.com/argoproj/argo-events/pkg/client/sensor/clientset/versioned/fake"
+	"github.com/argoproj/argo-events/sensors/types"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newBlockingServer returns a server that only responds once the release channel is closed
+func newBlockingServer(release chan struct{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func newTriggerWorkersSensor(url string) *v1alpha1.Sensor {
+	obj := sensorObj.DeepCopy()
+	obj.Spec.Dependencies = []v1alpha1.EventDependency{
+		{
+			Name:        "dep1",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-1",
+		},
+		{
+			Name:        "dep2",
+			GatewayName: "webhook-gateway",
+			EventName:   "example-2",
+		},
+	}
+	obj.Status.Nodes = map[string]v1alpha1.NodeStatus{}
+	for _, dependency := range obj.Spec.Dependencies {
+		id := obj.NodeID(dependency.Name)
+		obj.Status.Nodes[id] = v1alpha1.NodeStatus{
+			ID:          id,
+			Name:        dependency.Name,
+			DisplayName: dependency.Name,
+			Type:        v1alpha1.NodeTypeEventDependency,
+			Phase:       v1alpha1.NodePhaseActive,
+		}
+	}
+	obj.Spec.Triggers = []v1alpha1.Trigger{
+		{
+			Template: &v1alpha1.TriggerTemplate{
+				Name: "slow-trigger",
+				HTTP: &v1alpha1.HTTPTrigger{
+					URL: url,
+				},
+			},
+		},
+	}
+	for _, name := range []string{"deployment-1", "deployment-2"} {
+		obj.Spec.Triggers = append(obj.Spec.Triggers, v1alpha1.Trigger{
+			Template: &v1alpha1.TriggerTemplate{
+				Name: name,
+				GroupVersionResource: &metav1.GroupVersionResource{
+					Group:    "apps",
+					Version:  "v1",
+					Resource: "deployments",
+				},
+				Source: &v1alpha1.ArtifactLocation{
+					Resource: newUnstructured("apps/v1", "Deployment", "fake", name),
+				},
+			},
+		})
+	}
+	return obj
+}
+
+func newTriggerWorkersEvent() *apicommon.Event {
+	return &apicommon.Event{
+		Context: apicommon.EventContext{
+			DataContentType: "application/json",
+			Source:          "webhook-gateway",
+			Time:            metav1.MicroTime{Time: time.Now()},
+		},
+		Data: []byte("{}"),
+	}
+}
+
+func TestTriggerWorkers(t *testing.T) {
+	release := make(chan struct{})
+	server := newBlockingServer(release)
+	defer server.Close()
+
+	sensorClient := sensorFake.NewSimpleClientset()
+	dynamicClient := dfake.NewSimpleDynamicClient(runtime.NewScheme())
+	k8sClient := fake.NewSimpleClientset()
+	obj := newTriggerWorkersSensor(server.URL)
+	obj.Spec.TriggerWorkers = 3
+	newObj, err := sensorClient.ArgoprojV1alpha1().Sensors(obj.Namespace).Create(obj)
+	assert.Nil(t, err)
+	sensorCtx := NewSensorContext(sensorClient, k8sClient, dynamicClient, newObj.DeepCopy(), "1")
+
+	for _, dependency := range obj.Spec.Dependencies {
+		sensorCtx.processQueue(&types.Notification{
+			Event:            newTriggerWorkersEvent(),
+			EventDependency:  dependency.DeepCopy(),
+			NotificationType: v1alpha1.EventNotification,
+		})
+	}
+	// the cycle runs in the background and the dependencies are re-activated
+	assert.Equal(t, int32(0), sensorCtx.Sensor.Status.TriggerCycleCount)
+	assert.Equal(t, v1alpha1.NodePhaseActive, sensorCtx.Sensor.Status.Nodes[obj.NodeID("dep1")].Phase)
+
+	// the events keep being processed while the slow trigger is running
+	sensorCtx.processQueue(&types.Notification{
+		Event:            newTriggerWorkersEvent(),
+		EventDependency:  obj.Spec.Dependencies[0].DeepCopy(),
+		NotificationType: v1alpha1.EventNotification,
+	})
+	assert.Equal(t, v1alpha1.NodePhaseComplete, sensorCtx.Sensor.Status.Nodes[obj.NodeID("dep1")].Phase)
+
+	close(release)
+	select {
+	case notification := <-sensorCtx.NotificationQueue:
+		assert.Equal(t, v1alpha1.TriggerCycleNotification, notification.NotificationType)
+		assert.Nil(t, notification.TriggerCycleErr)
+		sensorCtx.processQueue(notification)
+	case <-time.After(5 * time.Second):
+		t.Fatal("trigger cycle did not complete")
+	}
+	assert.Equal(t, int32(1), sensorCtx.Sensor.Status.TriggerCycleCount)
+	assert.Equal(t, v1alpha1.TriggerCycleSuccess, sensorCtx.Sensor.Status.TriggerCycleStatus)
+
+	nsClient := dynamicClient.Resource(schema.GroupVersionResource{
+		Group:    "apps",
+		Version:  "v1",
+		Resource: "deployments",
+	}).Namespace("fake")
+	for _, name := range []string{"deployment-1", "deployment-2"} {
+		_, err := nsClient.Get(name, metav1.GetOptions{})
+		assert.Nil(t, err)
+	}
+}
+
+func TestTriggerTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := newBlockingServer(release)
+	defer server.Close()
+	defer close(release)
+
+	sensorClient := sensorFake.NewSimpleClientset()
+	dynamicClient := dfake.NewSimpleDynamicClient(runtime.NewScheme())
+	k8sClient := fake.NewSimpleClientset()
+	obj := newTriggerWorkersSensor(server.URL)
+	obj.Spec.Triggers = obj.Spec.Triggers[:1]
+	obj.Spec.Triggers[0].Timeout = "100ms"
+	sensorCtx := NewSensorContext(sensorClient, k8sClient, dynamicClient, obj, "1")
+
+	err := sensorCtx.executeTriggerCycle(context.Background(), obj, &types.Notification{
+		Event:           newTriggerWorkersEvent(),
+		EventDependency: &obj.Spec.Dependencies[0],
+	}, sensorCtx.Logger.WithField("test", t.Name()))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
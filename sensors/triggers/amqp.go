@@ -0,0 +1,101 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/pkg/errors"
+	amqplib "github.com/streadway/amqp"
+	"go.opencensus.io/trace"
+)
+
+// amqpChannel is the subset of the AMQP channel used by the AMQP trigger
+type amqpChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqplib.Table) error
+	Publish(exchange, key string, mandatory, immediate bool, msg amqplib.Publishing) error
+	Close() error
+}
+
+// amqpConn is the subset of the AMQP connection used by the AMQP trigger
+type amqpConn interface {
+	IsClosed() bool
+	Close() error
+}
+
+// openAMQPChannel dials the AMQP server and opens a channel. It returns the channel and its connection.
+// It is a variable so tests can inject a fake channel.
+var openAMQPChannel = func(url string) (amqpChannel, amqpConn, error) {
+	conn, err := amqplib.Dial(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return ch, conn, nil
+}
+
+// ExecuteAMQPTrigger publishes a message constructed from the events to the exchange of the AMQP trigger with the routing key.
+// The channel of the trigger is cached on the connections and reused until its connection is closed or the channel failed.
+func ExecuteAMQPTrigger(ctx context.Context, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger, connections *Connections) error {
+	amqpTrigger := trigger.Template.AMQP
+
+	_, span := trace.StartSpan(ctx, "sensor.execute_amqp_trigger")
+	span.AddAttributes(trace.StringAttribute("exchange", amqpTrigger.ExchangeName))
+	span.AddAttributes(trace.StringAttribute("routing-key", amqpTrigger.RoutingKey))
+	defer span.End()
+
+	payload, err := constructMessage(sensor, amqpTrigger.Payload)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	ch, err := connections.amqpChannel(ctx, trigger)
+	if err != nil {
+		err = errors.Wrap(err, "failed to connect to the amqp server")
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	if err := ch.ExchangeDeclare(amqpTrigger.ExchangeName, amqpTrigger.ExchangeType, true, false, false, false, nil); err != nil {
+		connections.dropAMQPChannel(trigger, ch)
+		err = errors.Wrapf(err, "failed to declare exchange with name %s and type %s", amqpTrigger.ExchangeName, amqpTrigger.ExchangeType)
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	if err := ch.Publish(amqpTrigger.ExchangeName, amqpTrigger.RoutingKey, false, false, amqplib.Publishing{
+		ContentType: common.MediaTypeJSON,
+		Body:        payload,
+		Headers: amqplib.Table{
+			tracing.TraceParentExtension: tracing.TraceParent(span.SpanContext()),
+		},
+	}); err != nil {
+		connections.dropAMQPChannel(trigger, ch)
+		err = errors.Wrapf(err, "failed to publish the message to exchange %s", amqpTrigger.ExchangeName)
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
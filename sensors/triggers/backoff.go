@@ -0,0 +1,46 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// retryWithBackoff runs the attempt as per the backoff until it succeeds, the steps run out or the context is done.
+// It returns the error of the last attempt, or the error of the context if the context is done while waiting.
+func retryWithBackoff(ctx context.Context, backoff wait.Backoff, attempt func() error) error {
+	err := wait.ErrWaitTimeout
+	for backoff.Steps > 0 {
+		if err = attempt(); err == nil {
+			return nil
+		}
+		if backoff.Steps == 1 {
+			break
+		}
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return err
+}
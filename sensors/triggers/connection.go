@@ -0,0 +1,41 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// connect establishes a connection as per the connection backoff of a trigger.
+// The default retry is applied if no backoff is specified. The error of the last attempt is returned,
+// or the error of the context if the context is done while backing off.
+func connect(ctx context.Context, backoff *v1alpha1.Backoff, conn func() error) error {
+	retry := common.DefaultRetry
+	if backoff != nil {
+		retry = wait.Backoff{
+			Duration: backoff.Duration,
+			Factor:   backoff.Factor,
+			Jitter:   backoff.Jitter,
+			Steps:    backoff.Steps,
+		}
+	}
+	return retryWithBackoff(ctx, retry, conn)
+}
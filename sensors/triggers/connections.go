@@ -0,0 +1,240 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	natslib "github.com/nats-io/go-nats"
+	"github.com/pkg/errors"
+)
+
+// Connections caches the producers and connections of the messaging triggers of a sensor, one per trigger, so that the executions of a trigger share its connection.
+type Connections struct {
+	lock sync.Mutex
+	// kafka holds the producers of the Kafka triggers keyed by the trigger name
+	kafka map[string]*kafkaProducer
+	// nats holds the connections of the NATS triggers keyed by the trigger name
+	nats map[string]*natsConnection
+	// amqp holds the channels of the AMQP triggers keyed by the trigger name
+	amqp map[string]*amqpConnection
+}
+
+// natsConnection is a NATS connection along with the url it is connected to
+type natsConnection struct {
+	conn *natslib.Conn
+	url  string
+}
+
+// amqpConnection is an AMQP channel and its connection along with the url it is connected to
+type amqpConnection struct {
+	ch   amqpChannel
+	conn amqpConn
+	url  string
+}
+
+// close closes the channel and the connection
+func (c *amqpConnection) close() error {
+	c.ch.Close()
+	return c.conn.Close()
+}
+
+// kafkaProducer is a producer along with the settings of the trigger it was created with
+type kafkaProducer struct {
+	producer sarama.SyncProducer
+	settings kafkaProducerSettings
+}
+
+// kafkaProducerSettings are the settings of a Kafka trigger the producer depends on.
+// The topic and the payload may be parameterized per execution, so they aren't part of it.
+type kafkaProducerSettings struct {
+	brokers        []string
+	version        string
+	timeout        int64
+	triggerTimeout string
+}
+
+// newKafkaProducerSettings returns the settings of the Kafka trigger the producer depends on
+func newKafkaProducerSettings(trigger *v1alpha1.Trigger) kafkaProducerSettings {
+	return kafkaProducerSettings{
+		brokers:        trigger.Template.Kafka.Brokers,
+		version:        trigger.Template.Kafka.Version,
+		timeout:        trigger.Template.Kafka.Timeout,
+		triggerTimeout: trigger.Timeout,
+	}
+}
+
+// NewConnections returns an empty cache of trigger connections
+func NewConnections() *Connections {
+	return &Connections{
+		kafka: make(map[string]*kafkaProducer),
+		nats:  make(map[string]*natsConnection),
+		amqp:  make(map[string]*amqpConnection),
+	}
+}
+
+// kafkaProducer returns the cached producer of the Kafka trigger.
+// A new producer is created if none is cached yet or if the settings of the trigger changed since the cached one was created.
+func (c *Connections) kafkaProducer(trigger *v1alpha1.Trigger) (sarama.SyncProducer, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	name := trigger.Template.Name
+	settings := newKafkaProducerSettings(trigger)
+	if cached, ok := c.kafka[name]; ok {
+		if reflect.DeepEqual(cached.settings, settings) {
+			return cached.producer, nil
+		}
+		delete(c.kafka, name)
+		if err := cached.producer.Close(); err != nil {
+			return nil, errors.Wrapf(err, "failed to close the outdated producer of the trigger %s", name)
+		}
+	}
+
+	config, err := newKafkaTriggerConfig(trigger)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := newKafkaProducer(trigger.Template.Kafka.Brokers, config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the kafka brokers %v", trigger.Template.Kafka.Brokers)
+	}
+	c.kafka[name] = &kafkaProducer{
+		producer: producer,
+		settings: settings,
+	}
+	return producer, nil
+}
+
+// natsConn returns the cached connection of the NATS trigger.
+// The trigger connects as per its connection backoff if no connection is cached yet, the cached one is closed or the url of the trigger changed.
+// The connection is established without holding the lock so that the backoff of a trigger doesn't hold up the other triggers.
+func (c *Connections) natsConn(ctx context.Context, trigger *v1alpha1.Trigger) (*natslib.Conn, error) {
+	name := trigger.Template.Name
+	url := trigger.Template.NATS.URL
+
+	c.lock.Lock()
+	cached, ok := c.nats[name]
+	c.lock.Unlock()
+	if ok && cached.url == url && !cached.conn.IsClosed() {
+		return cached.conn, nil
+	}
+
+	var conn *natslib.Conn
+	if err := connect(ctx, trigger.Template.NATS.ConnectionBackoff, func() error {
+		var err error
+		conn, err = natslib.Connect(url)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if cached, ok := c.nats[name]; ok {
+		// another execution of the trigger may have connected meanwhile
+		if cached.url == url && !cached.conn.IsClosed() {
+			conn.Close()
+			return cached.conn, nil
+		}
+		cached.conn.Close()
+	}
+	c.nats[name] = &natsConnection{
+		conn: conn,
+		url:  url,
+	}
+	return conn, nil
+}
+
+// amqpChannel returns the cached channel of the AMQP trigger.
+// The trigger connects as per its connection backoff if no channel is cached yet, the cached connection is closed or the url of the trigger changed.
+// The connection is established without holding the lock so that the backoff of a trigger doesn't hold up the other triggers.
+func (c *Connections) amqpChannel(ctx context.Context, trigger *v1alpha1.Trigger) (amqpChannel, error) {
+	name := trigger.Template.Name
+	url := trigger.Template.AMQP.URL
+
+	c.lock.Lock()
+	cached, ok := c.amqp[name]
+	c.lock.Unlock()
+	if ok && cached.url == url && !cached.conn.IsClosed() {
+		return cached.ch, nil
+	}
+
+	connection := &amqpConnection{
+		url: url,
+	}
+	if err := connect(ctx, trigger.Template.AMQP.ConnectionBackoff, func() error {
+		var err error
+		connection.ch, connection.conn, err = openAMQPChannel(url)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if cached, ok := c.amqp[name]; ok {
+		// another execution of the trigger may have connected meanwhile
+		if cached.url == url && !cached.conn.IsClosed() {
+			connection.close()
+			return cached.ch, nil
+		}
+		cached.close()
+	}
+	c.amqp[name] = connection
+	return connection.ch, nil
+}
+
+// dropAMQPChannel closes the channel of the AMQP trigger and removes it from the cache, so the next execution reconnects.
+// It is called once the channel failed, as the server closes the channel on errors.
+func (c *Connections) dropAMQPChannel(trigger *v1alpha1.Trigger, ch amqpChannel) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if cached, ok := c.amqp[trigger.Template.Name]; ok && cached.ch == ch {
+		cached.close()
+		delete(c.amqp, trigger.Template.Name)
+	}
+}
+
+// Close closes the cached connections. The first error is returned after all connections were closed.
+func (c *Connections) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var closeErr error
+	for name, cached := range c.kafka {
+		if err := cached.producer.Close(); err != nil && closeErr == nil {
+			closeErr = errors.Wrapf(err, "failed to close the producer of the trigger %s", name)
+		}
+		delete(c.kafka, name)
+	}
+	for name, cached := range c.nats {
+		cached.conn.Close()
+		delete(c.nats, name)
+	}
+	for name, cached := range c.amqp {
+		if err := cached.close(); err != nil && closeErr == nil {
+			closeErr = errors.Wrapf(err, "failed to close the amqp connection of the trigger %s", name)
+		}
+		delete(c.amqp, name)
+	}
+	return closeErr
+}
@@ -17,12 +17,24 @@ limitations under the License.
 package triggers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/argoproj/argo-events/common/tracing"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	"github.com/argoproj/argo-events/store"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 )
 
 // FetchResource fetches the K8s resource
@@ -45,7 +57,12 @@ func FetchResource(kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor, tri
 	return nil, nil
 }
 
-func Execute(sensor *v1alpha1.Sensor, obj *unstructured.Unstructured, client dynamic.NamespaceableResourceInterface) (*unstructured.Unstructured, error) {
+// Execute performs the operation of the trigger template on the resource and stamps it with the ID of the trace carried by the context.
+// It returns the resulting resource, or nil if the resource was deleted.
+func Execute(ctx context.Context, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger, obj *unstructured.Unstructured, client dynamic.NamespaceableResourceInterface) (*unstructured.Unstructured, error) {
+	_, span := trace.StartSpan(ctx, "sensor.execute_trigger")
+	defer span.End()
+
 	namespace := obj.GetNamespace()
 	// Defaults to sensor's namespace
 	if namespace == "" {
@@ -53,5 +70,159 @@ func Execute(sensor *v1alpha1.Sensor, obj *unstructured.Unstructured, client dyn
 	}
 	obj.SetNamespace(namespace)
 
-	return client.Namespace(namespace).Create(obj, metav1.CreateOptions{})
+	operation := trigger.Template.Operation
+	if operation == "" {
+		operation = v1alpha1.Create
+	}
+
+	span.AddAttributes(trace.StringAttribute("resource-kind", obj.GetKind()))
+	span.AddAttributes(trace.StringAttribute("resource-namespace", namespace))
+	span.AddAttributes(trace.StringAttribute("operation", string(operation)))
+
+	newObj, err := executeOperation(sensor, trigger, operation, setTraceID(obj, span.SpanContext().TraceID.String()), client.Namespace(namespace))
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	if newObj != nil {
+		span.AddAttributes(trace.StringAttribute("resource-name", newObj.GetName()))
+	}
+	return newObj, nil
+}
+
+// setTraceID stamps the trace ID annotation on the resource
+func setTraceID(obj *unstructured.Unstructured, traceID string) *unstructured.Unstructured {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[tracing.AnnotationTraceID] = traceID
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+// executeOperation performs the operation on the resource
+func executeOperation(sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger, operation v1alpha1.KubernetesResourceOperation, obj *unstructured.Unstructured, client dynamic.ResourceInterface) (*unstructured.Unstructured, error) {
+	switch operation {
+	case v1alpha1.Create:
+		return client.Create(obj, metav1.CreateOptions{})
+
+	case v1alpha1.Update:
+		return updateResource(obj, client)
+
+	case v1alpha1.Apply:
+		newObj, err := client.Create(obj, metav1.CreateOptions{})
+		if err != nil && apierrors.IsAlreadyExists(err) {
+			return applyResource(obj, client)
+		}
+		return newObj, err
+
+	case v1alpha1.MergePatch, v1alpha1.JSONPatch, v1alpha1.StrategicMergePatch:
+		patch, err := resolvePatch(sensor, trigger, operation, obj)
+		if err != nil {
+			return nil, err
+		}
+		traceID := obj.GetAnnotations()[tracing.AnnotationTraceID]
+		if operation == v1alpha1.JSONPatch {
+			newObj, err := client.Patch(obj.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{})
+			if err != nil || traceID == "" {
+				return newObj, err
+			}
+			// a json patch can't add the annotation without replacing the existing ones, so it is merged separately
+			return client.Patch(obj.GetName(), types.MergePatchType, traceIDPatch(traceID), metav1.PatchOptions{})
+		}
+		if traceID != "" {
+			if patch, err = mergeTraceID(patch, traceID); err != nil {
+				return nil, err
+			}
+		}
+		return client.Patch(obj.GetName(), patchTypes[operation], patch, metav1.PatchOptions{})
+
+	case v1alpha1.Delete:
+		if err := client.Delete(obj.GetName(), &metav1.DeleteOptions{}); err != nil {
+			return nil, err
+		}
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unknown operation %s", operation)
+	}
+}
+
+// patchTypes maps the patch operations to their patch types
+var patchTypes = map[v1alpha1.KubernetesResourceOperation]types.PatchType{
+	v1alpha1.MergePatch:          types.MergePatchType,
+	v1alpha1.JSONPatch:           types.JSONPatchType,
+	v1alpha1.StrategicMergePatch: types.StrategicMergePatchType,
+}
+
+// updateResource replaces the existing resource with the object. The update is retried on conflicts with the latest version.
+func updateResource(obj *unstructured.Unstructured, client dynamic.ResourceInterface) (*unstructured.Unstructured, error) {
+	var newObj *unstructured.Unstructured
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := client.Get(obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		obj.SetResourceVersion(current.GetResourceVersion())
+		newObj, err = client.Update(obj, metav1.UpdateOptions{})
+		return err
+	})
+	return newObj, err
+}
+
+// applyResource merge patches the existing resource with the object, as the resource could not be created.
+// This is not the apply of kubectl: the fields that are not set on the object are kept, including the ones the object
+// set on a previous execution, and the lists set on the object replace the existing ones.
+// The patch carries no resource version, so it applies to the latest version and does not conflict.
+func applyResource(obj *unstructured.Unstructured, client dynamic.ResourceInterface) (*unstructured.Unstructured, error) {
+	obj = obj.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "metadata", "resourceVersion")
+	patch, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return client.Patch(obj.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// traceIDPatch returns a merge patch that sets the trace ID annotation
+func traceIDPatch(traceID string) []byte {
+	return []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, tracing.AnnotationTraceID, traceID))
+}
+
+// mergeTraceID adds the trace ID annotation to a merge patch document
+func mergeTraceID(patch []byte, traceID string) ([]byte, error) {
+	var document map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(patch))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil || document == nil {
+		return nil, fmt.Errorf("patch must be a JSON object")
+	}
+	if err := unstructured.SetNestedField(document, traceID, "metadata", "annotations", tracing.AnnotationTraceID); err != nil {
+		return nil, errors.Wrap(err, "failed to set the trace ID on the patch")
+	}
+	return json.Marshal(document)
+}
+
+// resolvePatch returns the patch document in JSON with the patch parameters applied.
+// The trigger resource is the patch document of merge patches if the template does not define one.
+func resolvePatch(sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger, operation v1alpha1.KubernetesResourceOperation, obj *unstructured.Unstructured) ([]byte, error) {
+	var patch []byte
+	var err error
+	switch {
+	case trigger.Template.Patch != nil:
+		if patch, err = yaml.YAMLToJSON([]byte(*trigger.Template.Patch)); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the patch")
+		}
+	case operation == v1alpha1.JSONPatch:
+		return nil, fmt.Errorf("json-patch operation requires a patch")
+	default:
+		if patch, err = obj.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+	if len(trigger.PatchParameters) == 0 {
+		return patch, nil
+	}
+	return applyPatchParams(patch, trigger.PatchParameters, extractEvents(sensor, trigger.PatchParameters))
 }
@@ -17,16 +17,25 @@ limitations under the License.
 package triggers
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	dynamicFake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var sensorObj = &v1alpha1.Sensor{
@@ -90,8 +99,172 @@ func TestExecute(t *testing.T) {
 		Version:  trigger.Template.GroupVersionResource.Version,
 		Group:    trigger.Template.GroupVersionResource.Group,
 	})
-	uObj, err := Execute(sensorObj, deployment, namespacableClient)
+	exporter := tracing.NewInMemoryExporter()
+	tracing.SetExporter(exporter)
+	defer tracing.SetExporter(&tracing.LogExporter{})
+
+	ctx, span := trace.StartSpan(context.Background(), "fake-event")
+	uObj, err := Execute(ctx, sensorObj, &trigger, deployment, namespacableClient)
 	assert.Nil(t, err)
 	assert.NotNil(t, uObj)
 	assert.Equal(t, uObj.GetName(), deployment.GetName())
+	assert.Equal(t, span.SpanContext().TraceID.String(), uObj.GetAnnotations()[tracing.AnnotationTraceID])
+
+	spans := exporter.SpansByName("sensor.execute_trigger")
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, span.SpanContext().SpanID, spans[0].ParentSpanID)
+}
+
+func TestExecuteOperations(t *testing.T) {
+	gvr := schema.GroupVersionResource{
+		Group:    "apps",
+		Version:  "v1",
+		Resource: "deployments",
+	}
+
+	newDeployment := func(replicas int64) *unstructured.Unstructured {
+		deployment := newUnstructured("apps/v1", "Deployment", "fake", "test")
+		_ = unstructured.SetNestedField(deployment.Object, replicas, "spec", "replicas")
+		return deployment
+	}
+
+	replicas := func(t *testing.T, client dynamic.NamespaceableResourceInterface) int64 {
+		obj, err := client.Namespace("fake").Get("test", metav1.GetOptions{})
+		assert.Nil(t, err)
+		value, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		return value
+	}
+
+	newTrigger := func(operation v1alpha1.KubernetesResourceOperation) *v1alpha1.Trigger {
+		trigger := sensorObj.Spec.Triggers[0].DeepCopy()
+		trigger.Template.Operation = operation
+		return trigger
+	}
+
+	t.Run("create fails if the resource exists", func(t *testing.T) {
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), newDeployment(1)).Resource(gvr)
+		_, err := Execute(context.Background(), sensorObj, newTrigger(""), newDeployment(2), client)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), newDeployment(1)).Resource(gvr)
+		_, err := Execute(context.Background(), sensorObj, newTrigger(v1alpha1.Update), newDeployment(2), client)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), replicas(t, client))
+	})
+
+	t.Run("apply creates or updates", func(t *testing.T) {
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme()).Resource(gvr)
+		_, err := Execute(context.Background(), sensorObj, newTrigger(v1alpha1.Apply), newDeployment(1), client)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), replicas(t, client))
+
+		_, err = Execute(context.Background(), sensorObj, newTrigger(v1alpha1.Apply), newDeployment(3), client)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), replicas(t, client))
+	})
+
+	t.Run("update retries on conflicts", func(t *testing.T) {
+		fakeClient := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), newDeployment(1))
+		conflicts := 0
+		fakeClient.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if conflicts > 0 {
+				return false, nil, nil
+			}
+			conflicts++
+			return true, nil, apierrors.NewConflict(gvr.GroupResource(), "test", errors.New("fake conflict"))
+		})
+		client := fakeClient.Resource(gvr)
+		_, err := Execute(context.Background(), sensorObj, newTrigger(v1alpha1.Update), newDeployment(2), client)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, conflicts)
+		assert.Equal(t, int64(2), replicas(t, client))
+	})
+
+	t.Run("apply keeps the fields it does not set", func(t *testing.T) {
+		existing := newDeployment(1)
+		_ = unstructured.SetNestedField(existing.Object, true, "spec", "paused")
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), existing).Resource(gvr)
+		_, err := Execute(context.Background(), sensorObj, newTrigger(v1alpha1.Apply), newDeployment(3), client)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), replicas(t, client))
+
+		obj, err := client.Namespace("fake").Get("test", metav1.GetOptions{})
+		assert.Nil(t, err)
+		paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused")
+		assert.True(t, paused)
+	})
+
+	t.Run("merge patch with an explicit patch", func(t *testing.T) {
+		patch := `{"spec": {"replicas": 6}}`
+		trigger := newTrigger(v1alpha1.MergePatch)
+		trigger.Template.Patch = &patch
+
+		ctx, span := trace.StartSpan(context.Background(), "fake-event")
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), newDeployment(1)).Resource(gvr)
+		obj, err := Execute(ctx, sensorObj, trigger, newDeployment(1), client)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(6), replicas(t, client))
+		assert.Equal(t, span.SpanContext().TraceID.String(), obj.GetAnnotations()[tracing.AnnotationTraceID])
+	})
+
+	t.Run("merge patch with the resource", func(t *testing.T) {
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), newDeployment(1)).Resource(gvr)
+		_, err := Execute(context.Background(), sensorObj, newTrigger(v1alpha1.MergePatch), newDeployment(4), client)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), replicas(t, client))
+	})
+
+	t.Run("json patch with parameters", func(t *testing.T) {
+		sensor := sensorObj.DeepCopy()
+		sensor.Status.Nodes = map[string]v1alpha1.NodeStatus{}
+		id := sensor.NodeID("fake-dependency")
+		sensor.Status.Nodes[id] = v1alpha1.NodeStatus{
+			ID:   id,
+			Name: "fake-dependency",
+			Type: v1alpha1.NodeTypeEventDependency,
+			Event: &apicommon.Event{
+				Context: apicommon.EventContext{
+					DataContentType: common.MediaTypeJSON,
+				},
+				Data: []byte(`{"replicas": 5}`),
+			},
+		}
+
+		patch := `[{"op": "replace", "path": "/spec/replicas", "value": 0}]`
+		trigger := newTrigger(v1alpha1.JSONPatch)
+		trigger.Template.Patch = &patch
+		trigger.PatchParameters = []v1alpha1.TriggerParameter{
+			{
+				Src: &v1alpha1.TriggerParameterSource{
+					Event:   "fake-dependency",
+					DataKey: "replicas",
+				},
+				Dest: "0.value",
+			},
+		}
+
+		ctx, span := trace.StartSpan(context.Background(), "fake-event")
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), newDeployment(1)).Resource(gvr)
+		obj, err := Execute(ctx, sensor, trigger, newDeployment(1), client)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), replicas(t, client))
+		assert.Equal(t, span.SpanContext().TraceID.String(), obj.GetAnnotations()[tracing.AnnotationTraceID])
+	})
+
+	t.Run("json patch requires a patch", func(t *testing.T) {
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), newDeployment(1)).Resource(gvr)
+		_, err := Execute(context.Background(), sensorObj, newTrigger(v1alpha1.JSONPatch), newDeployment(1), client)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), newDeployment(1)).Resource(gvr)
+		obj, err := Execute(context.Background(), sensorObj, newTrigger(v1alpha1.Delete), newDeployment(1), client)
+		assert.Nil(t, err)
+		assert.Nil(t, obj)
+		_, err = client.Namespace("fake").Get("test", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
 }
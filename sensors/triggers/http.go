@@ -0,0 +1,195 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/argoproj/argo-events/common/tracing"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/store"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultHTTPTriggerTimeout is the timeout of the HTTP trigger request if none is specified
+const defaultHTTPTriggerTimeout = 60 * time.Second
+
+// the HTTP triggers share the transports, and so their idle connections, instead of building one per execution
+var (
+	httpTriggerTransport         = newHTTPTriggerTransport(false)
+	insecureHTTPTriggerTransport = newHTTPTriggerTransport(true)
+)
+
+// newHTTPTriggerTransport returns a transport with the settings of the default transport
+func newHTTPTriggerTransport(insecure bool) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: insecure,
+		},
+	}
+}
+
+// ExecuteHTTPTrigger sends the HTTP request of the trigger with a payload constructed from the events.
+// The request is retried as per the retry strategy until the response status meets the success criteria.
+func ExecuteHTTPTrigger(ctx context.Context, kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger) error {
+	httpTrigger := trigger.Template.HTTP
+
+	_, span := trace.StartSpan(ctx, "sensor.execute_http_trigger")
+	span.AddAttributes(trace.StringAttribute("url", httpTrigger.URL))
+	defer span.End()
+
+	request, err := newHTTPTriggerRequest(kubeClient, sensor, httpTrigger)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set(tracing.TraceParentExtension, tracing.TraceParent(span.SpanContext()))
+
+	timeout := defaultHTTPTriggerTimeout
+	if httpTrigger.Timeout > 0 {
+		timeout = time.Duration(httpTrigger.Timeout) * time.Second
+	}
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: httpTriggerTransport,
+	}
+	if httpTrigger.Insecure {
+		client.Transport = insecureHTTPTriggerTransport
+	}
+
+	backoff := wait.Backoff{
+		Steps: 1,
+	}
+	if httpTrigger.RetryStrategy != nil {
+		backoff = *httpTrigger.RetryStrategy
+	}
+
+	err = retryWithBackoff(ctx, backoff, func() error {
+		return sendHTTPTriggerRequest(client, request, httpTrigger.SuccessStatuses)
+	})
+	tracing.RecordError(span, err)
+	return err
+}
+
+// newHTTPTriggerRequest returns the request of the HTTP trigger with the payload, headers and auth
+func newHTTPTriggerRequest(kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor, httpTrigger *v1alpha1.HTTPTrigger) (*http.Request, error) {
+	var payload []byte
+	if httpTrigger.Payload != nil {
+		var err error
+		if payload, err = ConstructPayload(sensor, httpTrigger.Payload); err != nil {
+			return nil, errors.Wrap(err, "failed to construct the payload")
+		}
+	}
+
+	method := httpTrigger.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	request, err := http.NewRequest(method, httpTrigger.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range httpTrigger.Headers {
+		request.Header.Set(key, value)
+	}
+	// the body is set on each attempt so the request can be retried
+	request.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(payload)), nil
+	}
+	request.ContentLength = int64(len(payload))
+
+	if httpTrigger.BasicAuth != nil {
+		username, err := store.GetSecrets(kubeClient, sensor.Namespace, httpTrigger.BasicAuth.Username.Name, httpTrigger.BasicAuth.Username.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve the username")
+		}
+		password, err := store.GetSecrets(kubeClient, sensor.Namespace, httpTrigger.BasicAuth.Password.Name, httpTrigger.BasicAuth.Password.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve the password")
+		}
+		request.SetBasicAuth(username, password)
+	}
+	if httpTrigger.BearerToken != nil {
+		token, err := store.GetSecrets(kubeClient, sensor.Namespace, httpTrigger.BearerToken.Name, httpTrigger.BearerToken.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve the bearer token")
+		}
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	return request, nil
+}
+
+// sendHTTPTriggerRequest sends the request and checks the response status against the success statuses
+func sendHTTPTriggerRequest(client *http.Client, request *http.Request, successStatuses []int) error {
+	body, err := request.GetBody()
+	if err != nil {
+		return err
+	}
+	request.Body = body
+
+	response, err := client.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send the request to %s", request.URL.String())
+	}
+	defer response.Body.Close()
+	// drain the body so the connection can be reused
+	_, _ = ioutil.ReadAll(response.Body)
+
+	if !isSuccessStatus(response.StatusCode, successStatuses) {
+		return fmt.Errorf("request to %s failed with the response status %d", request.URL.String(), response.StatusCode)
+	}
+	return nil
+}
+
+// isSuccessStatus returns true if the status is one of the success statuses, or any 2xx status if none is specified
+func isSuccessStatus(status int, successStatuses []int) bool {
+	if len(successStatuses) == 0 {
+		return status >= http.StatusOK && status < http.StatusMultipleChoices
+	}
+	for _, successStatus := range successStatuses {
+		if status == successStatus {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newHTTPTriggerSensor(url string) (*v1alpha1.Sensor, *v1alpha1.Trigger) {
+	sensor := sensorObj.DeepCopy()
+	sensor.Status.Nodes = map[string]v1alpha1.NodeStatus{}
+	id := sensor.NodeID("fake-dependency")
+	sensor.Status.Nodes[id] = v1alpha1.NodeStatus{
+		ID:   id,
+		Name: "fake-dependency",
+		Type: v1alpha1.NodeTypeEventDependency,
+		Event: &apicommon.Event{
+			Context: apicommon.EventContext{
+				DataContentType: common.MediaTypeJSON,
+				Source:          "fake-gateway",
+			},
+			Data: []byte(`{"message": "hello"}`),
+		},
+	}
+
+	trigger := &v1alpha1.Trigger{
+		Template: &v1alpha1.TriggerTemplate{
+			Name: "fake-http-trigger",
+			HTTP: &v1alpha1.HTTPTrigger{
+				URL: url,
+				Headers: map[string]string{
+					"X-Fake-Header": "fake",
+				},
+				BearerToken: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "fake-secret",
+					},
+					Key: "token",
+				},
+				Payload: []v1alpha1.TriggerParameter{
+					{
+						Src: &v1alpha1.TriggerParameterSource{
+							Event:   "fake-dependency",
+							DataKey: "message",
+						},
+						Dest: "message",
+					},
+				},
+			},
+		},
+	}
+	return sensor, trigger
+}
+
+func newHTTPTriggerKubeClient(namespace string) *fake.Clientset {
+	return fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fake-secret",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"token": []byte("fake-token"),
+		},
+	})
+}
+
+func TestExecuteHTTPTrigger(t *testing.T) {
+	var payload map[string]string
+	var request *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		writer.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sensor, trigger := newHTTPTriggerSensor(server.URL)
+	err := ExecuteHTTPTrigger(context.Background(), newHTTPTriggerKubeClient(sensor.Namespace), sensor, trigger)
+	assert.Nil(t, err)
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, "fake", request.Header.Get("X-Fake-Header"))
+	assert.Equal(t, "Bearer fake-token", request.Header.Get("Authorization"))
+	assert.NotEmpty(t, request.Header.Get("traceparent"))
+	assert.Equal(t, "hello", payload["message"])
+}
+
+func TestExecuteHTTPTriggerRetry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sensor, trigger := newHTTPTriggerSensor(server.URL)
+	kubeClient := newHTTPTriggerKubeClient(sensor.Namespace)
+
+	// the request is sent once without a retry strategy
+	err := ExecuteHTTPTrigger(context.Background(), kubeClient, sensor, trigger)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, attempts)
+
+	attempts = 0
+	trigger.Template.HTTP.RetryStrategy = &wait.Backoff{
+		Duration: 10 * time.Millisecond,
+		Factor:   1,
+		Steps:    3,
+	}
+	err = ExecuteHTTPTrigger(context.Background(), kubeClient, sensor, trigger)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// accepted is not a success status when the success statuses are specified
+	attempts = 2
+	trigger.Template.HTTP.RetryStrategy = nil
+	trigger.Template.HTTP.SuccessStatuses = []int{http.StatusOK}
+	err = ExecuteHTTPTrigger(context.Background(), kubeClient, sensor, trigger)
+	assert.NotNil(t, err)
+
+	// the retries stop once the context is done
+	attempts = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	trigger.Template.HTTP.RetryStrategy = &wait.Backoff{
+		Duration: time.Hour,
+		Steps:    3,
+	}
+	err = ExecuteHTTPTrigger(ctx, kubeClient, sensor, trigger)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestIsSuccessStatus(t *testing.T) {
+	assert.True(t, isSuccessStatus(http.StatusOK, nil))
+	assert.True(t, isSuccessStatus(http.StatusNoContent, nil))
+	assert.False(t, isSuccessStatus(http.StatusNotFound, nil))
+	assert.True(t, isSuccessStatus(http.StatusNotFound, []int{http.StatusNotFound}))
+	assert.False(t, isSuccessStatus(http.StatusOK, []int{http.StatusCreated}))
+}
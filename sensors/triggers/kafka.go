@@ -0,0 +1,139 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/argoproj/argo-events/common/tracing"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// defaultKafkaTriggerTimeout is the timeout to publish the message of the Kafka trigger if none is specified
+const defaultKafkaTriggerTimeout = 60 * time.Second
+
+// newKafkaProducer returns a producer connected to the brokers. It is a variable so tests can inject a mock producer.
+var newKafkaProducer = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+	return sarama.NewSyncProducer(brokers, config)
+}
+
+// ExecuteKafkaTrigger publishes a message constructed from the events to the topic of the Kafka trigger.
+// The message key is resolved from the partitioning key so that related events end up on the same partition.
+// The producer of the trigger is cached on the connections and reused by the next executions.
+func ExecuteKafkaTrigger(ctx context.Context, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger, connections *Connections) error {
+	kafkaTrigger := trigger.Template.Kafka
+
+	_, span := trace.StartSpan(ctx, "sensor.execute_kafka_trigger")
+	span.AddAttributes(trace.StringAttribute("topic", kafkaTrigger.Topic))
+	defer span.End()
+
+	message, err := newKafkaTriggerMessage(sensor, kafkaTrigger)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	message.Headers = append(message.Headers, sarama.RecordHeader{
+		Key:   []byte(tracing.TraceParentExtension),
+		Value: []byte(tracing.TraceParent(span.SpanContext())),
+	})
+
+	producer, err := connections.kafkaProducer(trigger)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	// the send itself can't be cancelled, don't publish once the trigger timed out while connecting
+	if err := ctx.Err(); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	partition, offset, err := producer.SendMessage(message)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to publish the message to topic %s", kafkaTrigger.Topic)
+		tracing.RecordError(span, err)
+		return err
+	}
+	span.AddAttributes(trace.StringAttribute("partition", strconv.Itoa(int(partition))))
+	span.AddAttributes(trace.StringAttribute("offset", strconv.FormatInt(offset, 10)))
+	return nil
+}
+
+// newKafkaTriggerConfig returns the producer configuration of the Kafka trigger.
+// The sarama producer isn't cancellable, so its timeouts are capped by the timeout of the trigger.
+func newKafkaTriggerConfig(trigger *v1alpha1.Trigger) (*sarama.Config, error) {
+	kafkaTrigger := trigger.Template.Kafka
+	config := sarama.NewConfig()
+	if kafkaTrigger.Version != "" {
+		version, err := sarama.ParseKafkaVersion(kafkaTrigger.Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid kafka version %s", kafkaTrigger.Version)
+		}
+		config.Version = version
+	}
+	timeout := defaultKafkaTriggerTimeout
+	if kafkaTrigger.Timeout > 0 {
+		timeout = time.Duration(kafkaTrigger.Timeout) * time.Second
+	}
+	if triggerTimeout, err := time.ParseDuration(trigger.Timeout); err == nil && triggerTimeout > 0 && triggerTimeout < timeout {
+		timeout = triggerTimeout
+		config.Net.DialTimeout = timeout
+		config.Net.ReadTimeout = timeout
+		config.Net.WriteTimeout = timeout
+	}
+	config.Producer.Timeout = timeout
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Partitioner = sarama.NewHashPartitioner
+	return config, nil
+}
+
+// newKafkaTriggerMessage returns the message of the Kafka trigger with the key resolved from the partitioning key
+func newKafkaTriggerMessage(sensor *v1alpha1.Sensor, kafkaTrigger *v1alpha1.KafkaTrigger) (*sarama.ProducerMessage, error) {
+	payload, err := constructMessage(sensor, kafkaTrigger.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &sarama.ProducerMessage{
+		Topic: kafkaTrigger.Topic,
+		Value: sarama.ByteEncoder(payload),
+	}
+
+	if kafkaTrigger.PartitioningKey != nil {
+		params := []v1alpha1.TriggerParameter{
+			{
+				Src: kafkaTrigger.PartitioningKey,
+			},
+		}
+		events := extractEvents(sensor, params)
+		if _, ok := events[kafkaTrigger.PartitioningKey.Event]; !ok && !isTransformed(kafkaTrigger.PartitioningKey) && kafkaTrigger.PartitioningKey.Value == nil {
+			return nil, errors.Errorf("event dependency %s of the partitioning key has no event", kafkaTrigger.PartitioningKey.Event)
+		}
+		key, err := resolveParamValue(kafkaTrigger.PartitioningKey, events)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to resolve the partitioning key")
+		}
+		message.Key = sarama.StringEncoder(key)
+	}
+	return message, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/argoproj/argo-events/common"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func newKafkaTriggerSensor() (*v1alpha1.Sensor, *v1alpha1.Trigger) {
+	sensor := sensorObj.DeepCopy()
+	sensor.Status.Nodes = map[string]v1alpha1.NodeStatus{}
+	id := sensor.NodeID("fake-dependency")
+	sensor.Status.Nodes[id] = v1alpha1.NodeStatus{
+		ID:   id,
+		Name: "fake-dependency",
+		Type: v1alpha1.NodeTypeEventDependency,
+		Event: &apicommon.Event{
+			Context: apicommon.EventContext{
+				DataContentType: common.MediaTypeJSON,
+				Source:          "fake-gateway",
+			},
+			Data: []byte(`{"message": "hello", "user": {"id": "fake-user"}}`),
+		},
+	}
+
+	trigger := &v1alpha1.Trigger{
+		Template: &v1alpha1.TriggerTemplate{
+			Name: "fake-kafka-trigger",
+			Kafka: &v1alpha1.KafkaTrigger{
+				Brokers: []string{"kafka.argo-events:9092"},
+				Topic:   "fake-topic",
+				PartitioningKey: &v1alpha1.TriggerParameterSource{
+					Event:   "fake-dependency",
+					DataKey: "user.id",
+				},
+			},
+		},
+	}
+	return sensor, trigger
+}
+
+func TestNewKafkaTriggerMessage(t *testing.T) {
+	sensor, trigger := newKafkaTriggerSensor()
+
+	message, err := newKafkaTriggerMessage(sensor, trigger.Template.Kafka)
+	assert.Nil(t, err)
+	assert.Equal(t, "fake-topic", message.Topic)
+	assert.Equal(t, sarama.StringEncoder("fake-user"), message.Key)
+
+	// without a payload the message contains the events keyed by the dependency name
+	value, err := message.Value.Encode()
+	assert.Nil(t, err)
+	var events map[string]apicommon.Event
+	err = json.Unmarshal(value, &events)
+	assert.Nil(t, err)
+	assert.Equal(t, "fake-gateway", events["fake-dependency"].Context.Source)
+
+	trigger.Template.Kafka.Payload = []v1alpha1.TriggerParameter{
+		{
+			Src: &v1alpha1.TriggerParameterSource{
+				Event:   "fake-dependency",
+				DataKey: "message",
+			},
+			Dest: "text",
+		},
+	}
+	message, err = newKafkaTriggerMessage(sensor, trigger.Template.Kafka)
+	assert.Nil(t, err)
+	value, err = message.Value.Encode()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"text": "hello"}`, string(value))
+
+	trigger.Template.Kafka.PartitioningKey.Event = "unknown-dependency"
+	_, err = newKafkaTriggerMessage(sensor, trigger.Template.Kafka)
+	assert.NotNil(t, err)
+}
+
+// closeRecordingProducer records whether the producer was closed
+type closeRecordingProducer struct {
+	sarama.SyncProducer
+	closed bool
+}
+
+func (p *closeRecordingProducer) Close() error {
+	p.closed = true
+	return p.SyncProducer.Close()
+}
+
+func TestExecuteKafkaTrigger(t *testing.T) {
+	sensor, trigger := newKafkaTriggerSensor()
+
+	var producer *mocks.SyncProducer
+	var created []*closeRecordingProducer
+	defer func(f func([]string, *sarama.Config) (sarama.SyncProducer, error)) {
+		newKafkaProducer = f
+	}(newKafkaProducer)
+	newKafkaProducer = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+		assert.Equal(t, trigger.Template.Kafka.Brokers, brokers)
+		assert.True(t, config.Producer.Return.Successes)
+		created = append(created, &closeRecordingProducer{SyncProducer: producer})
+		return created[len(created)-1], nil
+	}
+
+	connections := NewConnections()
+	producer = mocks.NewSyncProducer(t, nil)
+	checkEvents := func(value []byte) error {
+		var events map[string]apicommon.Event
+		if err := json.Unmarshal(value, &events); err != nil {
+			return err
+		}
+		if _, ok := events["fake-dependency"]; !ok {
+			return fmt.Errorf("message doesn't contain the event of the dependency")
+		}
+		return nil
+	}
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(checkEvents)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(checkEvents)
+	err := ExecuteKafkaTrigger(context.Background(), sensor, trigger, connections)
+	assert.Nil(t, err)
+
+	// the producer is reused by the next executions of the trigger
+	trigger.Template.Kafka.Topic = "another-topic"
+	err = ExecuteKafkaTrigger(context.Background(), sensor, trigger, connections)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(created))
+
+	producer.ExpectSendMessageAndFail(sarama.ErrNotLeaderForPartition)
+	err = ExecuteKafkaTrigger(context.Background(), sensor, trigger, connections)
+	assert.NotNil(t, err)
+
+	// the producer is replaced once the connection settings of the trigger changed
+	producer = mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndSucceed()
+	trigger.Template.Kafka.Brokers = []string{"kafka-2.argo-events:9092"}
+	err = ExecuteKafkaTrigger(context.Background(), sensor, trigger, connections)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(created))
+	assert.True(t, created[0].closed)
+
+	assert.Nil(t, connections.Close())
+	assert.True(t, created[1].closed)
+	assert.Empty(t, connections.kafka)
+
+	trigger.Template.Kafka.Version = "fake-version"
+	err = ExecuteKafkaTrigger(context.Background(), sensor, trigger, connections)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	natstest "github.com/nats-io/gnatsd/test"
+	natslib "github.com/nats-io/go-nats"
+	amqplib "github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteNATSTrigger(t *testing.T) {
+	opts := natstest.DefaultTestOptions
+	opts.Port = 14224
+	server := natstest.RunServer(&opts)
+	defer server.Shutdown()
+
+	conn, err := natslib.Connect("nats://127.0.0.1:14224")
+	assert.Nil(t, err)
+	defer conn.Close()
+	msgCh := make(chan *natslib.Msg, 1)
+	_, err = conn.ChanSubscribe("fake-subject", msgCh)
+	assert.Nil(t, err)
+	assert.Nil(t, conn.Flush())
+
+	sensor, _ := newKafkaTriggerSensor()
+	trigger := &v1alpha1.Trigger{
+		Template: &v1alpha1.TriggerTemplate{
+			Name: "fake-nats-trigger",
+			NATS: &v1alpha1.NATSTrigger{
+				URL:     "nats://127.0.0.1:14224",
+				Subject: "fake-subject",
+				Payload: []v1alpha1.TriggerParameter{
+					{
+						Src: &v1alpha1.TriggerParameterSource{
+							Event:   "fake-dependency",
+							DataKey: "message",
+						},
+						Dest: "text",
+					},
+				},
+			},
+		},
+	}
+
+	connections := NewConnections()
+	defer connections.Close()
+	receive := func() {
+		select {
+		case msg := <-msgCh:
+			assert.JSONEq(t, `{"text": "hello"}`, string(msg.Data))
+		case <-time.After(5 * time.Second):
+			t.Fatal("message wasn't published to the subject")
+		}
+	}
+
+	err = ExecuteNATSTrigger(context.Background(), sensor, trigger, connections)
+	assert.Nil(t, err)
+	receive()
+
+	// the connection is reused by the next executions of the trigger
+	cached := connections.nats["fake-nats-trigger"].conn
+	err = ExecuteNATSTrigger(context.Background(), sensor, trigger, connections)
+	assert.Nil(t, err)
+	receive()
+	assert.Equal(t, cached, connections.nats["fake-nats-trigger"].conn)
+
+	// the trigger reconnects once the connection is closed
+	cached.Close()
+	err = ExecuteNATSTrigger(context.Background(), sensor, trigger, connections)
+	assert.Nil(t, err)
+	receive()
+	assert.NotEqual(t, cached, connections.nats["fake-nats-trigger"].conn)
+
+	trigger.Template.NATS.URL = "nats://127.0.0.1:14225"
+	trigger.Template.NATS.ConnectionBackoff = &v1alpha1.Backoff{
+		Duration: 10 * time.Millisecond,
+		Factor:   1,
+		Steps:    2,
+	}
+	err = ExecuteNATSTrigger(context.Background(), sensor, trigger, connections)
+	assert.NotNil(t, err)
+}
+
+// fakeAMQPChannel records the messages published to it
+type fakeAMQPChannel struct {
+	exchanges  map[string]string
+	published  []amqplib.Publishing
+	routingKey string
+	closed     bool
+}
+
+func (ch *fakeAMQPChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqplib.Table) error {
+	ch.exchanges[name] = kind
+	return nil
+}
+
+func (ch *fakeAMQPChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqplib.Publishing) error {
+	if _, ok := ch.exchanges[exchange]; !ok {
+		return errors.New("exchange is not declared")
+	}
+	ch.routingKey = key
+	ch.published = append(ch.published, msg)
+	return nil
+}
+
+func (ch *fakeAMQPChannel) IsClosed() bool {
+	return ch.closed
+}
+
+func (ch *fakeAMQPChannel) Close() error {
+	ch.closed = true
+	return nil
+}
+
+func TestExecuteAMQPTrigger(t *testing.T) {
+	ch := &fakeAMQPChannel{
+		exchanges: make(map[string]string),
+	}
+	attempts := 0
+	defer func(f func(string) (amqpChannel, amqpConn, error)) {
+		openAMQPChannel = f
+	}(openAMQPChannel)
+	openAMQPChannel = func(url string) (amqpChannel, amqpConn, error) {
+		attempts++
+		// the first attempt fails so the connection is retried
+		if attempts == 1 {
+			return nil, nil, errors.New("connection refused")
+		}
+		return ch, ch, nil
+	}
+
+	sensor, _ := newKafkaTriggerSensor()
+	trigger := &v1alpha1.Trigger{
+		Template: &v1alpha1.TriggerTemplate{
+			Name: "fake-amqp-trigger",
+			AMQP: &v1alpha1.AMQPTrigger{
+				URL:          "amqp://localhost:5672",
+				ExchangeName: "fake-exchange",
+				ExchangeType: "topic",
+				RoutingKey:   "fake-key",
+				ConnectionBackoff: &v1alpha1.Backoff{
+					Duration: 10 * time.Millisecond,
+					Factor:   1,
+					Steps:    2,
+				},
+			},
+		},
+	}
+
+	connections := NewConnections()
+	err := ExecuteAMQPTrigger(context.Background(), sensor, trigger, connections)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, "topic", ch.exchanges["fake-exchange"])
+	assert.Equal(t, "fake-key", ch.routingKey)
+	assert.Equal(t, 1, len(ch.published))
+	assert.NotEmpty(t, ch.published[0].Headers["traceparent"])
+
+	// without a payload the message contains the events keyed by the dependency name
+	var events map[string]apicommon.Event
+	err = json.Unmarshal(ch.published[0].Body, &events)
+	assert.Nil(t, err)
+	assert.Equal(t, "fake-gateway", events["fake-dependency"].Context.Source)
+
+	// the channel is reused by the next executions of the trigger
+	err = ExecuteAMQPTrigger(context.Background(), sensor, trigger, connections)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 2, len(ch.published))
+
+	assert.Nil(t, connections.Close())
+	assert.True(t, ch.closed)
+
+	// the trigger reconnects once the connection is closed
+	attempts = 0
+	trigger.Template.AMQP.ConnectionBackoff.Steps = 1
+	err = ExecuteAMQPTrigger(context.Background(), sensor, trigger, connections)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// the connection backoff is aborted once the context is done
+	attempts = 0
+	trigger.Template.AMQP.ConnectionBackoff.Duration = time.Minute
+	trigger.Template.AMQP.ConnectionBackoff.Steps = 2
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = ExecuteAMQPTrigger(ctx, sensor, trigger, connections)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	assert.Equal(t, 1, attempts)
+}
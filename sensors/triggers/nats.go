@@ -0,0 +1,72 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+
+	"github.com/argoproj/argo-events/common/tracing"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	natslib "github.com/nats-io/go-nats"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// ExecuteNATSTrigger publishes a message constructed from the events to the subject of the NATS trigger.
+// The connection of the trigger is cached on the connections and reused until it is closed.
+func ExecuteNATSTrigger(ctx context.Context, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger, connections *Connections) error {
+	natsTrigger := trigger.Template.NATS
+
+	_, span := trace.StartSpan(ctx, "sensor.execute_nats_trigger")
+	span.AddAttributes(trace.StringAttribute("subject", natsTrigger.Subject))
+	defer span.End()
+
+	payload, err := constructMessage(sensor, natsTrigger.Payload)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	conn, err := connections.natsConn(ctx, trigger)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to connect to the nats cluster %s", natsTrigger.URL)
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	if err := conn.Publish(natsTrigger.Subject, payload); err != nil {
+		err = errors.Wrapf(err, "failed to publish the message to subject %s", natsTrigger.Subject)
+		tracing.RecordError(span, err)
+		return err
+	}
+	// flush to make sure the message reached the server
+	if err := flushNATS(ctx, conn); err != nil {
+		err = errors.Wrapf(err, "failed to flush the message to subject %s", natsTrigger.Subject)
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+// flushNATS flushes the connection until the server acknowledged the published messages or the context is done.
+// The nats client only flushes with a context that has a deadline, the default flush timeout applies otherwise.
+func flushNATS(ctx context.Context, conn *natslib.Conn) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return conn.Flush()
+	}
+	return conn.FlushWithContext(ctx)
+}
@@ -69,8 +69,40 @@ func ApplyResourceParameters(sensor *v1alpha1.Sensor, parameters []v1alpha1.Trig
 	return nil
 }
 
+// ConstructPayload constructs a JSON payload from the parameters, each parameter setting its destination key in the payload
+func ConstructPayload(sensor *v1alpha1.Sensor, parameters []v1alpha1.TriggerParameter) ([]byte, error) {
+	return applyParams([]byte("{}"), parameters, extractEvents(sensor, parameters))
+}
+
+// constructMessage constructs the message of a trigger that publishes to a messaging system.
+// If no parameters are defined, the message is the JSON object of the dependency events keyed by their name.
+func constructMessage(sensor *v1alpha1.Sensor, parameters []v1alpha1.TriggerParameter) ([]byte, error) {
+	if parameters != nil {
+		payload, err := ConstructPayload(sensor, parameters)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to construct the payload")
+		}
+		return payload, nil
+	}
+	payload, err := json.Marshal(dependencyEvents(sensor))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the events")
+	}
+	return payload, nil
+}
+
 // apply the params to the resource json object
 func applyParams(jsonObj []byte, params []v1alpha1.TriggerParameter, events map[string]apicommon.Event) ([]byte, error) {
+	return setParams(jsonObj, params, events, false)
+}
+
+// apply the params to the patch json document. values that are valid JSON, e.g. numbers, are set as is instead of strings.
+func applyPatchParams(jsonObj []byte, params []v1alpha1.TriggerParameter, events map[string]apicommon.Event) ([]byte, error) {
+	return setParams(jsonObj, params, events, true)
+}
+
+// set the param values in the json object, as raw JSON if the value is valid JSON and raw is true
+func setParams(jsonObj []byte, params []v1alpha1.TriggerParameter, events map[string]apicommon.Event, raw bool) ([]byte, error) {
 	for _, param := range params {
 		// let's grab the param value
 		v, err := resolveParamValue(param.Src, events)
@@ -97,7 +129,12 @@ func applyParams(jsonObj []byte, params []v1alpha1.TriggerParameter, events map[
 		}
 
 		// now let's set the value
-		tmp, err := sjson.SetBytes(jsonObj, param.Dest, v)
+		var tmp []byte
+		if raw && isJSON([]byte(v)) {
+			tmp, err = sjson.SetRawBytes(jsonObj, param.Dest, []byte(v))
+		} else {
+			tmp, err = sjson.SetBytes(jsonObj, param.Dest, v)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -138,6 +175,16 @@ func renderEventDataAsJSON(event *apicommon.Event) ([]byte, error) {
 // helper method to resolve the parameter's value from the src
 // returns an error if the Path is invalid/not found and the default value is nil OR if the eventDependency event doesn't exist and default value is nil
 func resolveParamValue(src *v1alpha1.TriggerParameterSource, events map[string]apicommon.Event) (string, error) {
+	if isTransformed(src) {
+		value, err := transformParamValue(src, events)
+		if err != nil {
+			if src.Value != nil {
+				return *src.Value, nil
+			}
+			return "", err
+		}
+		return value, nil
+	}
 	var err error
 	var value []byte
 	var key string
@@ -179,9 +226,15 @@ func resolveParamValue(src *v1alpha1.TriggerParameterSource, events map[string]a
 }
 
 // helper method to extract the events from the event dependencies nodes associated with the resource params
-// returns a map of the events keyed by the event dependency Name
+// returns a map of the events keyed by the event dependency Name. All the events are returned if a param has a template or an expression
 func extractEvents(sensor *v1alpha1.Sensor, params []v1alpha1.TriggerParameter) map[string]apicommon.Event {
 	events := make(map[string]apicommon.Event)
+	for _, param := range params {
+		if param.Src != nil && isTransformed(param.Src) {
+			// templates and expressions are evaluated over the events of all the dependencies
+			return dependencyEvents(sensor)
+		}
+	}
 	for _, param := range params {
 		if param.Src != nil {
 			node := snctrl.GetNodeByName(sensor, param.Src.Event)
@@ -196,3 +249,14 @@ func extractEvents(sensor *v1alpha1.Sensor, params []v1alpha1.TriggerParameter)
 	}
 	return events
 }
+
+// dependencyEvents returns the events of the event dependency nodes keyed by the event dependency name
+func dependencyEvents(sensor *v1alpha1.Sensor) map[string]apicommon.Event {
+	events := make(map[string]apicommon.Event)
+	for _, node := range sensor.Status.Nodes {
+		if node.Type == v1alpha1.NodeTypeEventDependency && node.Event != nil {
+			events[node.Name] = *node.Event
+		}
+	}
+	return events
+}
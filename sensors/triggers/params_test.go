@@ -63,6 +63,17 @@ func TestExtractEvents(t *testing.T) {
 	assert.NotNil(t, events)
 	assert.Equal(t, events["fake-dependency"].Context.Subject, "example-1")
 
+	// templates are evaluated over the events of all the dependencies
+	events = extractEvents(obj, []v1alpha1.TriggerParameter{
+		{
+			Src: &v1alpha1.TriggerParameterSource{
+				Template: "{{ .events }}",
+			},
+		},
+	})
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, events["fake-dependency"].Context.Subject, "example-1")
+
 	delete(obj.Status.Nodes, id)
 	events = extractEvents(obj, []v1alpha1.TriggerParameter{
 		{
@@ -142,6 +153,28 @@ func TestResolveParamValue(t *testing.T) {
 			},
 			result: "fake",
 		},
+		{
+			name: "render a template over the events",
+			source: &v1alpha1.TriggerParameterSource{
+				Template: `{{ index .events "fake-dependency" "Name" "first" | upper }}-{{ index .contexts "fake-dependency" "subject" | trimPrefix "example-" }}`,
+			},
+			result: "FAKE-1",
+		},
+		{
+			name: "evaluate an expression over the events",
+			source: &v1alpha1.TriggerParameterSource{
+				Expression: "[events.fake-dependency.Name.last] == 'user' ? 'admin' : 'guest'",
+			},
+			result: "admin",
+		},
+		{
+			name: "get the default value if the template fails",
+			source: &v1alpha1.TriggerParameterSource{
+				Template: `{{ (index .events "fake-dependency").Unknown }}`,
+				Value:    &defaultValue,
+			},
+			result: defaultValue,
+		},
 	}
 
 	for _, test := range tests {
@@ -151,6 +184,11 @@ func TestResolveParamValue(t *testing.T) {
 			assert.Equal(t, test.result, string(result))
 		})
 	}
+
+	_, err = resolveParamValue(&v1alpha1.TriggerParameterSource{
+		Expression: "[events.unknown-dependency.Name] == 'fake'",
+	}, events)
+	assert.NotNil(t, err)
 }
 
 func TestRenderDataAsJSON(t *testing.T) {
@@ -0,0 +1,146 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"encoding/json"
+
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Kinds of triggers
+const (
+	KindResource = "resource"
+	KindHTTP     = "http"
+	KindKafka    = "kafka"
+	KindNATS     = "nats"
+	KindAMQP     = "amqp"
+	KindSlack    = "slack"
+)
+
+// Rendered is what a trigger creates or sends, rendered without executing the trigger
+type Rendered struct {
+	// Kind of the trigger
+	Kind string `json:"kind"`
+	// Operation performed on the resource. Only set for resource triggers.
+	Operation v1alpha1.KubernetesResourceOperation `json:"operation,omitempty"`
+	// Resource the operation is performed with. Only set for resource triggers.
+	Resource *unstructured.Unstructured `json:"resource,omitempty"`
+	// Patch sent to the API server, with the patch parameters applied. Only set for the patch operations.
+	Patch json.RawMessage `json:"patch,omitempty"`
+	// Destination is the URL, topic, subject, exchange or channel the payload is sent to
+	Destination string `json:"destination,omitempty"`
+	// Payload is the body or message sent by the trigger
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Render renders the resource or the payload of the trigger with the events of the dependencies, without executing the trigger.
+// The template parameters are expected to be applied on the trigger already. The client is only used to read the trigger sources
+// that live in the cluster, it can be nil otherwise.
+func Render(kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger) (*Rendered, error) {
+	template := trigger.Template
+	if kubeClient == nil && inCluster(template.Source) {
+		return nil, errors.New("the source of the trigger is read from the cluster, a kubernetes client is required")
+	}
+	switch {
+	case template.HTTP != nil:
+		rendered := &Rendered{
+			Kind:        KindHTTP,
+			Destination: template.HTTP.URL,
+		}
+		if template.HTTP.Payload != nil {
+			payload, err := ConstructPayload(sensor, template.HTTP.Payload)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to construct the payload")
+			}
+			rendered.Payload = payload
+		}
+		return rendered, nil
+
+	case template.Kafka != nil:
+		payload, err := constructMessage(sensor, template.Kafka.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return &Rendered{Kind: KindKafka, Destination: template.Kafka.Topic, Payload: payload}, nil
+
+	case template.NATS != nil:
+		payload, err := constructMessage(sensor, template.NATS.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return &Rendered{Kind: KindNATS, Destination: template.NATS.Subject, Payload: payload}, nil
+
+	case template.AMQP != nil:
+		payload, err := constructMessage(sensor, template.AMQP.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return &Rendered{Kind: KindAMQP, Destination: template.AMQP.ExchangeName, Payload: payload}, nil
+
+	case template.Slack != nil:
+		message, err := newSlackMessage(sensor, template.Slack)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := json.Marshal(message)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal the slack message")
+		}
+		return &Rendered{Kind: KindSlack, Destination: template.Slack.Channel, Payload: payload}, nil
+	}
+
+	obj, err := FetchResource(kubeClient, sensor, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, errors.Errorf("trigger %s has no resource", template.Name)
+	}
+	if err := ApplyResourceParameters(sensor, trigger.ResourceParameters, obj); err != nil {
+		return nil, err
+	}
+	// Defaults to sensor's namespace
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(sensor.Namespace)
+	}
+	operation := template.Operation
+	if operation == "" {
+		operation = v1alpha1.Create
+	}
+	rendered := &Rendered{Kind: KindResource, Operation: operation, Resource: obj}
+	switch operation {
+	case v1alpha1.MergePatch, v1alpha1.JSONPatch, v1alpha1.StrategicMergePatch:
+		patch, err := resolvePatch(sensor, trigger, operation, obj)
+		if err != nil {
+			return nil, err
+		}
+		rendered.Patch = patch
+	}
+	return rendered, nil
+}
+
+// inCluster tells whether the artifact is read from the cluster
+func inCluster(source *v1alpha1.ArtifactLocation) bool {
+	if source == nil {
+		return false
+	}
+	return source.S3 != nil || source.Git != nil || source.Configmap != nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/argoproj/argo-events/common"
+	"github.com/argoproj/argo-events/common/tracing"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/argoproj/argo-events/store"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// defaultSlackURL is the base URL of the Slack API
+	defaultSlackURL = "https://slack.com/api"
+	// slackPostMessage is the Slack API method that posts a message to a channel
+	slackPostMessage = "chat.postMessage"
+	// slackTriggerTimeout is the timeout of the requests to the Slack API
+	slackTriggerTimeout = 30 * time.Second
+)
+
+// slackMessage is the request of the Slack API method that posts a message
+type slackMessage struct {
+	Channel string          `json:"channel"`
+	Text    string          `json:"text,omitempty"`
+	Blocks  json.RawMessage `json:"blocks,omitempty"`
+}
+
+// slackResponse is the response of the Slack API
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ExecuteSlackTrigger posts the message of the Slack trigger, rendered from the events, to the channel
+func ExecuteSlackTrigger(ctx context.Context, kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor, trigger *v1alpha1.Trigger) error {
+	slackTrigger := trigger.Template.Slack
+
+	_, span := trace.StartSpan(ctx, "sensor.execute_slack_trigger")
+	span.AddAttributes(trace.StringAttribute("channel", slackTrigger.Channel))
+	defer span.End()
+
+	if err := postSlackMessage(kubeClient, sensor, slackTrigger); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+// postSlackMessage renders the message and posts it to the Slack API
+func postSlackMessage(kubeClient kubernetes.Interface, sensor *v1alpha1.Sensor, slackTrigger *v1alpha1.SlackTrigger) error {
+	message, err := newSlackMessage(sensor, slackTrigger)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	token, err := store.GetSecrets(kubeClient, sensor.Namespace, slackTrigger.Token.Name, slackTrigger.Token.Key)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the slack token")
+	}
+
+	url := defaultSlackURL
+	if slackTrigger.URL != "" {
+		url = strings.TrimSuffix(slackTrigger.URL, "/")
+	}
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", url, slackPostMessage), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", fmt.Sprintf("%s; charset=utf-8", common.MediaTypeJSON))
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	client := &http.Client{
+		Timeout: slackTriggerTimeout,
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to post the slack message")
+	}
+	defer response.Body.Close()
+
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the response of the slack api")
+	}
+	if response.StatusCode != http.StatusOK {
+		return errors.Errorf("slack api responded with status %d. body: %s", response.StatusCode, string(content))
+	}
+	var result slackResponse
+	if err := json.Unmarshal(content, &result); err != nil {
+		return errors.Wrap(err, "failed to parse the response of the slack api")
+	}
+	if !result.OK {
+		return errors.Errorf("failed to post the slack message. err: %s", result.Error)
+	}
+	return nil
+}
+
+// newSlackMessage renders the text and blocks templates of the Slack trigger
+func newSlackMessage(sensor *v1alpha1.Sensor, slackTrigger *v1alpha1.SlackTrigger) (*slackMessage, error) {
+	message := &slackMessage{
+		Channel: slackTrigger.Channel,
+	}
+	events := dependencyEvents(sensor)
+	if slackTrigger.Text != "" {
+		text, err := renderTemplate("text", slackTrigger.Text, events)
+		if err != nil {
+			return nil, err
+		}
+		message.Text = text
+	}
+	if slackTrigger.Blocks != "" {
+		blocks, err := renderTemplate("blocks", slackTrigger.Blocks, events)
+		if err != nil {
+			return nil, err
+		}
+		if !isJSON([]byte(blocks)) {
+			return nil, errors.Errorf("blocks must render to a JSON array. rendered: %s", blocks)
+		}
+		message.Blocks = json.RawMessage(blocks)
+	}
+	return message, nil
+}
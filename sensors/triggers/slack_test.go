@@ -0,0 +1,101 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newSlackTrigger(url string) *v1alpha1.Trigger {
+	return &v1alpha1.Trigger{
+		Template: &v1alpha1.TriggerTemplate{
+			Name: "fake-slack-trigger",
+			Slack: &v1alpha1.SlackTrigger{
+				Token: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "fake-secret",
+					},
+					Key: "token",
+				},
+				Channel: "fake-channel",
+				Text:    `{{ index .events "fake-dependency" "message" }} from {{ index .contexts "fake-dependency" "source" }}`,
+				Blocks:  `[{"type": "section", "text": {"type": "mrkdwn", "text": "*{{ index .events "fake-dependency" "message" | upper }}*"}}]`,
+				URL:     url,
+			},
+		},
+	}
+}
+
+func TestExecuteSlackTrigger(t *testing.T) {
+	var message map[string]interface{}
+	var request *http.Request
+	ok := true
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &message)
+		if ok {
+			_, _ = writer.Write([]byte(`{"ok": true}`))
+			return
+		}
+		_, _ = writer.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+	}))
+	defer server.Close()
+
+	sensor, _ := newHTTPTriggerSensor(server.URL)
+	kubeClient := newHTTPTriggerKubeClient(sensor.Namespace)
+	trigger := newSlackTrigger(server.URL)
+
+	err := ExecuteSlackTrigger(context.Background(), kubeClient, sensor, trigger)
+	assert.Nil(t, err)
+	assert.Equal(t, "/chat.postMessage", request.URL.Path)
+	assert.Equal(t, "Bearer fake-token", request.Header.Get("Authorization"))
+	assert.Equal(t, "fake-channel", message["channel"])
+	assert.Equal(t, "hello from fake-gateway", message["text"])
+	blocks, isList := message["blocks"].([]interface{})
+	assert.True(t, isList)
+	assert.Equal(t, 1, len(blocks))
+
+	ok = false
+	err = ExecuteSlackTrigger(context.Background(), kubeClient, sensor, trigger)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "channel_not_found")
+}
+
+func TestNewSlackMessage(t *testing.T) {
+	sensor, _ := newHTTPTriggerSensor("")
+	trigger := newSlackTrigger("")
+
+	// referring to an unknown field of the event data fails the rendering
+	trigger.Template.Slack.Text = `{{ (index .events "fake-dependency").unknown }}`
+	_, err := newSlackMessage(sensor, trigger.Template.Slack)
+	assert.NotNil(t, err)
+
+	trigger.Template.Slack.Text = ""
+	trigger.Template.Slack.Blocks = `{{ index .events "fake-dependency" "message" }}`
+	_, err = newSlackMessage(sensor, trigger.Template.Slack)
+	assert.NotNil(t, err)
+}
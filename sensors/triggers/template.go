@@ -0,0 +1,95 @@
+/*
+Copyright 2018 BlackRock, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triggers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/argoproj/argo-events/common/templates"
+	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
+	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// newTemplateData returns the data the templates and expressions of a trigger are evaluated over.
+// It holds the decoded event data under events and the event context under contexts, both keyed by the dependency name.
+// Data that isn't JSON is exposed as a string.
+func newTemplateData(events map[string]apicommon.Event) map[string]interface{} {
+	data := make(map[string]interface{})
+	contexts := make(map[string]interface{})
+	for name, event := range events {
+		var value interface{} = string(event.Data)
+		if raw, err := renderEventDataAsJSON(&event); err == nil {
+			var decoded interface{}
+			if err := json.Unmarshal(raw, &decoded); err == nil {
+				value = decoded
+			}
+		}
+		data[name] = value
+
+		var eventContext map[string]interface{}
+		if raw, err := json.Marshal(&event.Context); err == nil {
+			if err := json.Unmarshal(raw, &eventContext); err == nil {
+				contexts[name] = eventContext
+			}
+		}
+	}
+	return map[string]interface{}{
+		"events":   data,
+		"contexts": contexts,
+	}
+}
+
+// renderTemplate renders the Go template of a trigger with the events
+func renderTemplate(name, text string, events map[string]apicommon.Event) (string, error) {
+	tmpl, err := templates.Parse(name, text)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the %s template", name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, newTemplateData(events)); err != nil {
+		return "", errors.Wrapf(err, "failed to render the %s template", name)
+	}
+	return buf.String(), nil
+}
+
+// evaluateExpression evaluates the govaluate expression of a trigger with the events
+func evaluateExpression(expression string, events map[string]apicommon.Event) (string, error) {
+	evaluable, err := templates.NewExpression(expression)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the expression")
+	}
+	value, err := templates.Evaluate(evaluable, templates.Parameters(newTemplateData(events)))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to evaluate the expression")
+	}
+	return value, nil
+}
+
+// isTransformed returns true if the value of the parameter source is computed with a template or an expression
+func isTransformed(src *v1alpha1.TriggerParameterSource) bool {
+	return src.Template != "" || src.Expression != ""
+}
+
+// transformParamValue computes the value of a parameter source with its template or expression
+func transformParamValue(src *v1alpha1.TriggerParameterSource, events map[string]apicommon.Event) (string, error) {
+	if src.Template != "" {
+		return renderTemplate("parameter", src.Template, events)
+	}
+	return evaluateExpression(src.Expression, events)
+}
@@ -19,6 +19,7 @@ package types
 import (
 	apicommon "github.com/argoproj/argo-events/pkg/apis/common"
 	"github.com/argoproj/argo-events/pkg/apis/sensor/v1alpha1"
+	"go.opencensus.io/trace"
 )
 
 // Notification to update event dependency's state or the sensor resource
@@ -33,4 +34,10 @@ type Notification struct {
 	Sensor *v1alpha1.Sensor
 	// NotificationType for event notification and state update notification
 	NotificationType v1alpha1.NotificationType
+	// SpanContext is the trace context the event was received with
+	SpanContext trace.SpanContext
+	// TriggerCycleErr is the error of the trigger cycle, if any. Only used for trigger cycle notifications
+	TriggerCycleErr error
+	// Attempts is the number of earlier attempts to process the event, e.g. before the event was replayed from the dead-letter sink
+	Attempts int
 }